@@ -14,6 +14,12 @@
 
 package lily
 
+import (
+	"github.com/aberic/lily/connector"
+	"io"
+	"time"
+)
+
 const (
 	// FormTypeSQL 关系型数据存储方式
 	FormTypeSQL = "FORM_TYPE_SQL"
@@ -21,6 +27,40 @@ const (
 	FormTypeDoc = "FORM_TYPE_DOC"
 )
 
+const (
+	// CompressionNone 不压缩
+	CompressionNone = "COMPRESSION_NONE"
+	// CompressionGzip gzip压缩
+	CompressionGzip = "COMPRESSION_GZIP"
+	// CompressionFlate flate压缩
+	CompressionFlate = "COMPRESSION_FLATE"
+	// CompressionSnappy snappy压缩，压缩率低于gzip/flate，但速度更快
+	CompressionSnappy = "COMPRESSION_SNAPPY"
+)
+
+const (
+	// SyncModeNone 不主动落盘，由操作系统决定落盘时机，掉电时可能丢失已确认的写入
+	SyncModeNone = "none"
+	// SyncModeInterval 每隔 SyncInterval 毫秒落盘一次，掉电时最多丢失该时间段内的写入
+	SyncModeInterval = "interval"
+	// SyncModeAlways 每次写入确认前落盘
+	SyncModeAlways = "always"
+)
+
+const (
+	// EngineFile 基于数据分段文件及索引文件的默认存储引擎
+	EngineFile = "file"
+	// EngineMemory 内存存储引擎，数据不落盘，服务重启后丢失
+	EngineMemory = "memory"
+)
+
+// FormOptions 表选项
+type FormOptions struct {
+	Compression string // Compression 表数据压缩方式，默认 CompressionNone，创建后不可变更
+	Engine      string // Engine 表存储引擎名称，默认为配置文件中的 Engine，创建后不可变更
+	Versions    uint32 // Versions 每条数据保留的版本数，默认1即仅保留当前版本，仅默认存储引擎支持
+}
+
 // API 暴露公共API接口
 //
 // 提供通用 k-v 方法，无需创建新的数据库和表等对象
@@ -55,15 +95,25 @@ type API interface {
 	//
 	// comment 表描述
 	CreateForm(databaseName, formName, comment, formType string) error
-	// CreateKey 新建主键
+	// CreateFormWithOptions 按表选项创建表
 	//
 	// databaseName 数据库名
 	//
 	// name 表名称
 	//
-	// keyStructure 主键结构名，按照规范结构组成的主键字段名称，由对象结构层级字段通过'.'组成，如'i','in.s'
+	// comment 表描述
+	//
+	// options 表选项，为nil时与 CreateForm 一致
+	CreateFormWithOptions(databaseName, formName, comment, formType string, options *FormOptions) error
+	// CreateKey 新建唯一索引，写入数据的索引值与其它主键的记录重复时返回 *UniqueViolationError
+	//
+	// databaseName 数据库名
+	//
+	// name 表名称
+	//
+	// keyStructure 主键结构名，按照规范结构组成的主键字段名称，由对象结构层级字段通过'.'组成，如'i','in.s'，复合唯一索引各字段以','分隔
 	CreateKey(databaseName, formName string, keyStructure string) error
-	// createIndex 新建索引
+	// CreateIndex 新建索引，同一索引值可对应多条记录
 	//
 	// databaseName 数据库名
 	//
@@ -71,6 +121,46 @@ type API interface {
 	//
 	// keyStructure 索引结构名，按照规范结构组成的索引字段名称，由对象结构层级字段通过'.'组成，如'i','in.s'
 	CreateIndex(databaseName, formName string, keyStructure string) error
+	// CreateCompositeIndex 新建复合索引
+	//
+	// databaseName 数据库名
+	//
+	// name 表名称
+	//
+	// keyStructures 复合索引字段名称集合，至少2个，按顺序组成复合索引key，检索条件覆盖其前缀字段时可采用该索引
+	CreateCompositeIndex(databaseName, formName string, keyStructures []string) error
+	// DropDatabase 删除库，进行中的读写完成后删除库存储目录
+	//
+	// name 数据库名称
+	DropDatabase(name string) error
+	// DropForm 删除表，进行中的读写完成后删除表存储目录
+	//
+	// databaseName 数据库名
+	//
+	// formName 表名
+	DropForm(databaseName, formName string) error
+	// DropIndex 删除索引，表默认主键及自增ID索引不能被删除
+	//
+	// databaseName 数据库名
+	//
+	// formName 表名
+	//
+	// keyStructure 索引结构名
+	DropIndex(databaseName, formName, keyStructure string) error
+	// RenameDatabase 重命名库
+	//
+	// name 数据库名称
+	//
+	// newName 新数据库名称
+	RenameDatabase(name, newName string) error
+	// RenameForm 重命名表
+	//
+	// databaseName 数据库名
+	//
+	// formName 表名
+	//
+	// newName 新表名
+	RenameForm(databaseName, formName, newName string) error
 	// PutD 新增数据
 	//
 	// 向_default表中新增一条数据，key相同则返回一个Error
@@ -91,6 +181,14 @@ type API interface {
 	//
 	// 返回 hashKey
 	SetD(key string, value interface{}) (uint64, error)
+	// PutDWithTTL 新增数据，数据在存活时间后过期
+	//
+	// ttl 存活时间，0表示永不过期
+	PutDWithTTL(key string, value interface{}, ttl time.Duration) (uint64, error)
+	// SetDWithTTL 设置数据，数据在存活时间后过期
+	//
+	// ttl 存活时间，0表示永不过期
+	SetDWithTTL(key string, value interface{}, ttl time.Duration) (uint64, error)
 	// GetD 获取数据
 	//
 	// 向_default表中查询一条数据并返回
@@ -125,6 +223,14 @@ type API interface {
 	//
 	// 返回 hashKey
 	Set(databaseName, formName, key string, value interface{}) (uint64, error)
+	// PutWithTTL 新增数据，数据在存活时间后过期，过期数据对 Get/Select 不可见，并由后台回收
+	//
+	// ttl 存活时间，0表示永不过期
+	PutWithTTL(databaseName, formName, key string, value interface{}, ttl time.Duration) (uint64, error)
+	// SetWithTTL 设置数据，数据在存活时间后过期，过期数据对 Get/Select 不可见，并由后台回收
+	//
+	// ttl 存活时间，0表示永不过期
+	SetWithTTL(databaseName, formName, key string, value interface{}, ttl time.Duration) (uint64, error)
 	// Get 获取数据
 	//
 	// 向指定表中查询一条数据并返回
@@ -135,6 +241,16 @@ type API interface {
 	//
 	// keyStructure 插入数据唯一key
 	Get(databaseName, formName, key string) (interface{}, error)
+	// GetVersion 获取数据指定版本
+	//
+	// 仅能获取表保留版本数以内的版本，该版本为删除操作或已过期时与 Get 返回相同错误
+	//
+	// version 版本号，每次写入或删除递增
+	GetVersion(databaseName, formName, key string, version uint64) (interface{}, error)
+	// History 获取数据历史版本，由新至旧，最多返回表保留的版本数
+	//
+	// 超出保留版本数的旧版本在表压缩时回收
+	History(databaseName, formName, key string) ([]*RecordVersion, error)
 	// Remove 删除数据
 	//
 	// 向指定表中删除一条数据并返回
@@ -163,6 +279,98 @@ type API interface {
 	//
 	// int 返回检索条目数量
 	Delete(databaseName, formName string, selector *Selector) (int32, error)
+	// Compact 压缩表数据文件
+	//
+	// 重写表数据文件，仅保留仍被索引引用的有效记录，并同步重写全部索引文件
+	//
+	// 压缩期间表可正常读取，写入将等待压缩完成
+	//
+	// databaseName 数据库名
+	//
+	// formName 表名
+	Compact(databaseName, formName string) (*CompactResult, error)
+	// RebuildIndex 重建索引
+	//
+	// 顺序扫描表数据文件，依据每个key最新且有效的记录重新生成索引文件及内存索引树，用于索引文件丢失或损坏时恢复
+	//
+	// 重建期间表可正常读取，写入将等待重建完成
+	//
+	// databaseName 数据库名
+	//
+	// formName 表名
+	//
+	// keyStructure 索引结构名，主键及自增ID索引同样适用
+	//
+	// 返回重建后的索引记录数
+	RebuildIndex(databaseName, formName, keyStructure string) (int64, error)
+	// IndexStatus 获取索引构建状态
+	//
+	// 新建索引依据表中已有数据在后台回填，回填完成前检索不采用该索引
+	//
+	// databaseName 数据库名
+	//
+	// formName 表名
+	//
+	// keyStructure 索引结构名
+	IndexStatus(databaseName, formName, keyStructure string) (*IndexStatus, error)
+	// GetFormStats 获取表统计信息，含数据记录数、落盘大小及压缩率
+	//
+	// databaseName 数据库名
+	//
+	// formName 表名
+	GetFormStats(databaseName, formName string) (*FormStats, error)
+	// RotateKey 轮换数据密钥
+	//
+	// 生成新版本数据密钥，并以新数据密钥重新加密库中全部表的有效记录，完成后移除旧版本数据密钥
+	//
+	// 轮换期间表可正常读取，写入将在对应表重新加密期间等待
+	//
+	// databaseName 数据库名
+	RotateKey(databaseName string) (*RotateResult, error)
+	// SetSyncMode 设置数据库落盘策略，覆盖配置文件中的 SyncMode
+	//
+	// databaseName 数据库名
+	//
+	// syncMode 落盘策略 SyncModeNone/SyncModeInterval/SyncModeAlways，为空时使用配置文件中的 SyncMode
+	SetSyncMode(databaseName, syncMode string) error
+	// Backup 备份库
+	//
+	// 将库或全部库的快照写入 tar.gz 归档文件，备份期间库可正常读写
+	//
+	// databaseName 数据库名，为空时备份全部库
+	//
+	// path 归档文件路径
+	Backup(databaseName, path string) (*BackupResult, error)
+	// Restore 依据归档文件恢复库，归档中的库将替换同名库
+	//
+	// path 归档文件路径
+	Restore(path string) (*BackupResult, error)
+	// Export 导出表数据
+	//
+	// format 导出格式 ExportJSONL/ExportCSV
+	//
+	// w 导出数据写入对象
+	Export(databaseName, formName, format string, w io.Writer) (int64, error)
+	// Import 导入表数据
+	//
+	// format 导入格式 ExportJSONL/ExportCSV
+	//
+	// r 导入数据读取对象
+	//
+	// reject 无法导入的行写入对象，为nil时丢弃
+	//
+	// progress 导入进度回调，可为nil
+	Import(databaseName, formName, format string, r io.Reader, reject io.Writer, progress func(*ImportResult)) (*ImportResult, error)
+	// PutBlob 分块存储大数据
+	//
+	// r 大数据读取对象
+	PutBlob(databaseName, formName, key string, r io.Reader) (*BlobInfo, error)
+	// GetBlob 读取大数据
+	//
+	// w 大数据写入对象
+	GetBlob(databaseName, formName, key string, w io.Writer) (*BlobInfo, error)
+	// RemoveBlob 删除大数据及其全部分块
+	RemoveBlob(databaseName, formName, key string) error
 }
 
 // Database 数据库接口
@@ -173,10 +381,22 @@ type Database interface {
 	getID() string
 	// getName 返回数据库名称
 	getName() string
+	// setName 设置数据库名称，调用方持有 Lily 锁
+	setName(name string)
 	// getComment 获取数据库描述
 	getComment() string
 	// getForms 获取数据库表集合
 	getForms() map[string]Form
+	// detachForm 自表集合中移除表，调用方持有 Lily 锁
+	detachForm(formName string)
+	// renameForm 以新表名替换表集合中的表名，调用方持有 Lily 锁
+	renameForm(formName, newName string)
+	// getKeyring 获取数据库数据密钥环
+	getKeyring() *keyring
+	// getSyncMode 获取数据库生效的落盘策略
+	getSyncMode() string
+	// setSyncMode 设置数据库落盘策略，为空时使用配置文件中的 SyncMode
+	setSyncMode(syncMode string)
 	// createForm 新建表方法
 	//
 	// 默认自增ID索引
@@ -184,7 +404,9 @@ type Database interface {
 	// name 表名称
 	//
 	// comment 表描述
-	createDoc(formName, comment string) error
+	//
+	// options 表选项，压缩方式及存储引擎均已确定
+	createDoc(formName, comment string, options *FormOptions) error
 	// createForm 新建表方法
 	//
 	// 默认自增ID索引
@@ -192,7 +414,9 @@ type Database interface {
 	// name 表名称
 	//
 	// comment 表描述
-	createSQL(formName, comment string) error
+	//
+	// options 表选项，压缩方式及存储引擎均已确定
+	createSQL(formName, comment string, options *FormOptions) error
 	// createIndex 新建主键
 	//
 	// name 表名称
@@ -204,7 +428,9 @@ type Database interface {
 	// name 表名称
 	//
 	// keyStructure 索引结构名，按照规范结构组成的索引字段名称，由对象结构层级字段通过'.'组成，如'i','in.s'
-	createIndex(formName string, keyStructure string) error
+	//
+	// unique 是否唯一索引
+	createIndex(formName string, keyStructure string, unique bool) error
 	// Put 新增数据
 	//
 	// 向_default表中新增一条数据，key相同则覆盖
@@ -216,13 +442,31 @@ type Database interface {
 	// 返回 hashKey
 	//
 	// update 本次是否执行更新操作
-	put(formName string, key string, value interface{}, update bool) (uint64, error)
+	//
+	// expire 过期时间，unix纳秒，0表示永不过期
+	put(formName string, key string, value interface{}, update bool, expire int64) (uint64, error)
 	// Get 获取数据
 	//
 	// 向_default表中查询一条数据并返回
 	//
 	// keyStructure 插入数据唯一key
 	get(formName string, key string) (interface{}, error)
+	// getVersion 获取数据指定版本
+	//
+	// version 版本号
+	getVersion(formName, key string, version uint64) (interface{}, error)
+	// history 获取数据保留的版本集合，由新至旧
+	history(formName, key string) ([]*RecordVersion, error)
+	// export 导出表数据，返回导出行数
+	export(formName, format string, w io.Writer) (int64, error)
+	// load 导入表数据，无法导入的行写入 reject
+	load(formName, format string, r io.Reader, reject io.Writer, progress func(*ImportResult)) (*ImportResult, error)
+	// putBlob 分块存储大数据
+	putBlob(formName, key string, r io.Reader) (*BlobInfo, error)
+	// getBlob 读取大数据写入 w
+	getBlob(formName, key string, w io.Writer) (*BlobInfo, error)
+	// removeBlob 删除大数据及其全部分块
+	removeBlob(formName, key string) error
 	// remove 删除数据
 	//
 	// 向指定表中删除一条数据并返回
@@ -243,21 +487,65 @@ type Database interface {
 	//
 	// int 返回检索条目数量
 	delete(formName string, selector *Selector) (int32, error)
-	insertDataWithIndexInfo(form Form, key string, indexes map[string]Index, value interface{}, update, valid bool) (uint64, error)
+	insertDataWithIndexInfo(form Form, key string, value interface{}, update, valid bool, expire int64) (uint64, error)
+	// expireData 将key已过期的记录以删除相同的方式标记为无效，持有表写锁后确认key当前记录仍为 location 处的记录，返回是否已标记
+	expireData(form Form, key string, value interface{}, location recordLocation) (bool, error)
+	// compact 压缩表数据文件
+	//
+	// formName 表名
+	compact(formName string) (*CompactResult, error)
+	// rebuildIndex 依据表数据文件重建索引
+	//
+	// formName 表名
+	//
+	// keyStructure 索引结构名
+	rebuildIndex(formName, keyStructure string) (int64, error)
+	// indexStatus 获取索引构建状态
+	//
+	// formName 表名
+	//
+	// keyStructure 索引结构名
+	indexStatus(formName, keyStructure string) (*IndexStatus, error)
+	// resumeBackfill 重新回填重启或恢复前未完成回填的索引
+	resumeBackfill()
+	// formStats 获取表统计信息
+	//
+	// formName 表名
+	formStats(formName string) (*FormStats, error)
+	// rotateKey 轮换数据密钥并重新加密库中全部表的有效记录
+	rotateKey() (*RotateResult, error)
+	// recover 重做预写日志中所有未完成的操作
+	recover() error
+	// flush 将 interval 落盘策略下写入的预写日志落盘
+	flush() error
+	// close 关闭数据库持有的文件资源
+	close() error
 }
 
 // Form 表接口
 //
 // 提供表基本操作方法
 type Form interface {
-	WriteLocker                   // WriteLocker 读写锁接口
-	getAutoID() *uint64           // getAutoID 返回表当前自增ID值
-	getID() string                // getID 返回表唯一ID
-	getName() string              // getName 返回表名称
-	getComment() string           // getComment 获取表描述
-	getDatabase() Database        // getDatabase 返回数据库对象
-	getIndexes() map[string]Index // getIndexes 获取表下索引集合
-	getFormType() string          // getFormType 获取表类型
+	WriteLocker                          // WriteLocker 读写锁接口
+	getAutoID() *uint64                  // getAutoID 返回表当前自增ID值
+	getID() string                       // getID 返回表唯一ID
+	getName() string                     // getName 返回表名称
+	setName(name string)                 // setName 设置表名称，调用方持有 Lily 锁
+	getComment() string                  // getComment 获取表描述
+	getDatabase() Database               // getDatabase 返回数据库对象
+	getIndexes() map[string]Index        // getIndexes 获取表下索引集合
+	setIndexes(indexes map[string]Index) // setIndexes 替换表下索引集合，集合不可原地修改，调用方持有表写锁
+	getFormType() string                 // getFormType 获取表类型
+	getCompression() string              // getCompression 获取表数据压缩方式
+	getEngine() string                   // getEngine 获取表存储引擎名称
+	getVersions() uint32                 // getVersions 获取每条数据保留的版本数
+	getStorage() connector.Storage       // getStorage 获取表存储对象
+	getSwapLocker() WriteLocker          // getSwapLocker 获取数据文件替换锁
+	getSegment() uint32                  // getSegment 获取当前写入的数据分段文件序号
+	setSegment(segment uint32)           // setSegment 设置当前写入的数据分段文件序号，调用方持有表写锁
+	getHeads() *recordHeads              // getHeads 获取没有主键索引的表中每个key当前记录的位置
+	drop()                               // drop 标记表已被删除，调用方持有表写锁
+	isDropped() bool                     // isDropped 表是否已被删除，写入方持有表写锁后判断，已删除则拒绝写入
 }
 
 // Index 索引接口
@@ -267,6 +555,14 @@ type Index interface {
 	getID() string
 	// isPrimary 是否主键
 	isPrimary() bool
+	// isUnique 是否唯一索引，写入数据的索引值不能与其它主键的记录重复
+	isUnique() bool
+	// isReady 是否已构建完成，依据表中已有数据回填期间及回填失败后检索不采用该索引
+	isReady() bool
+	// getStatus 获取构建状态
+	getStatus() *IndexStatus
+	// setStatus 设置构建状态
+	setStatus(status *IndexStatus)
 	// getKey 索引字段名称，由对象结构层级字段通过'.'组成，如
 	//
 	// ref := &ref{
@@ -299,8 +595,20 @@ type Index interface {
 	//
 	// hashKey 索引key，可通过hash转换string生成
 	get(key string, hashKey uint64) *readResult
+	// getLink 获取key对应的链表对象，不存在时返回nil
+	//
+	// key 真实key，必须string类型
+	//
+	// hashKey 索引key，可通过hash转换string生成
+	getLink(key string, hashKey uint64) Link
 	// recover 重置索引数据
 	recover()
+	// rebuild 依据数据记录重建索引树及索引文件
+	//
+	// records 按数据文件顺序排列的有效记录集合
+	//
+	// 返回重建后的索引记录数
+	rebuild(records []*scannedRecord) (int64, error)
 }
 
 // Nodal 节点对象接口
@@ -327,6 +635,13 @@ type Nodal interface {
 	//
 	// flexibleKey 下一级最左最小树所对应真实key
 	get(key string, hashKey, flexibleKey uint64) *readResult
+	// getLink 获取key对应的链表对象，不存在时返回nil
+	//
+	// key 真实key，必须string类型
+	//
+	// flexibleKey 下一级最左最小树所对应真实key
+	getLink(key string, flexibleKey uint64) Link
+	getLevel() uint8        // getLevel 获取节点所在树层级
 	getDegreeIndex() uint16 // getDegreeIndex 获取节点所在树中度集合中的数组下标
 	getPreNode() Nodal      // getPreNode 获取父节点对象
 	getNodes() []Nodal      // getNodes 获取下属节点集合
@@ -335,7 +650,10 @@ type Nodal interface {
 // Leaf 叶子节点对象接口
 type Leaf interface {
 	Nodal
-	getLinks() []Link // getLinks 获取叶子节点下的链表对象集合
+	getLinks() []Link   // getLinks 获取叶子节点下的链表对象集合
+	removeLink(ln Link) // removeLink 移除叶子节点下指定链表对象
+	// rekeyLink 链表md5后key变更时同步更新叶子节点下的链表集合
+	rekeyLink(ln Link, oldMD5Key, newMD5Key string)
 }
 
 // Link 叶子节点下的链表对象接口
@@ -343,15 +661,18 @@ type Link interface {
 	WriteLocker                   // WriteLocker 读写锁接口
 	setMD5Key(md5Key string)      // 设置md5Key
 	setSeekStartIndex(seek int64) // 设置索引最终存储在文件中的起始位置
+	setSegment(segment uint32)    // 设置value所在数据分段文件序号
 	setSeekStart(seek int64)      // 设置value最终存储在文件中的起始位置
 	setSeekLast(seek int)         // 设置value最终存储在文件中的持续长度
 	getNodal() Nodal              // box 所属 node
 	getMD516Key() string          // 获取md516Key
 	getSeekStartIndex() int64     // 索引最终存储在文件中的起始位置
+	getSegment() uint32           // value所在数据分段文件序号
 	getSeekStart() int64          // value最终存储在文件中的起始位置
 	getSeekLast() int             // value最终存储在文件中的持续长度
 	put(key string, hashKey uint64) *indexBack
 	get() *readResult
+	getRecord() (*valueData, error) // 读取链表指向的数据记录，不校验记录是否有效或过期
 }
 
 // IndexBack 索引检索回调结果接口
@@ -52,10 +52,24 @@ type Conf struct {
 	// LilyLockFilePath Lily当前进程地址存储文件地址
 	LilyLockFilePath string `protobuf:"bytes,13,opt,name=LilyLockFilePath,proto3" json:"LilyLockFilePath,omitempty"`
 	// LilyBootstrapFilePath Lily重启引导文件地址
-	LilyBootstrapFilePath string   `protobuf:"bytes,14,opt,name=LilyBootstrapFilePath,proto3" json:"LilyBootstrapFilePath,omitempty"`
-	XXX_NoUnkeyedLiteral  struct{} `json:"-"`
-	XXX_unrecognized      []byte   `json:"-"`
-	XXX_sizecache         int32    `json:"-"`
+	LilyBootstrapFilePath string `protobuf:"bytes,14,opt,name=LilyBootstrapFilePath,proto3" json:"LilyBootstrapFilePath,omitempty"`
+	// FormSegmentSize 每个表数据分段文件的最大尺寸，超过后写入新的分段文件 单位：M
+	FormSegmentSize int32 `protobuf:"varint,15,opt,name=FormSegmentSize,proto3" json:"FormSegmentSize,omitempty"`
+	// MasterKeyFile 主密钥文件地址，配置后启用数据加密
+	MasterKeyFile string `protobuf:"bytes,16,opt,name=MasterKeyFile,proto3" json:"MasterKeyFile,omitempty"`
+	// SyncMode 落盘策略(none/interval/always)
+	SyncMode string `protobuf:"bytes,17,opt,name=SyncMode,proto3" json:"SyncMode,omitempty"`
+	// SyncInterval interval 落盘策略的落盘间隔 单位：毫秒
+	SyncInterval int32 `protobuf:"varint,18,opt,name=SyncInterval,proto3" json:"SyncInterval,omitempty"`
+	// Engine 新建表未指定存储引擎时使用的存储引擎
+	Engine string `protobuf:"bytes,19,opt,name=Engine,proto3" json:"Engine,omitempty"`
+	// SweepInterval 过期数据回收间隔 单位：秒
+	SweepInterval int32 `protobuf:"varint,20,opt,name=SweepInterval,proto3" json:"SweepInterval,omitempty"`
+	// CatalogGenerations lily.sync 保留的历史代数量
+	CatalogGenerations   int32    `protobuf:"varint,21,opt,name=CatalogGenerations,proto3" json:"CatalogGenerations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Conf) Reset()         { *m = Conf{} }
@@ -181,6 +195,55 @@ func (m *Conf) GetLilyBootstrapFilePath() string {
 	return ""
 }
 
+func (m *Conf) GetFormSegmentSize() int32 {
+	if m != nil {
+		return m.FormSegmentSize
+	}
+	return 0
+}
+
+func (m *Conf) GetMasterKeyFile() string {
+	if m != nil {
+		return m.MasterKeyFile
+	}
+	return ""
+}
+
+func (m *Conf) GetSyncMode() string {
+	if m != nil {
+		return m.SyncMode
+	}
+	return ""
+}
+
+func (m *Conf) GetSyncInterval() int32 {
+	if m != nil {
+		return m.SyncInterval
+	}
+	return 0
+}
+
+func (m *Conf) GetEngine() string {
+	if m != nil {
+		return m.Engine
+	}
+	return ""
+}
+
+func (m *Conf) GetSweepInterval() int32 {
+	if m != nil {
+		return m.SweepInterval
+	}
+	return 0
+}
+
+func (m *Conf) GetCatalogGenerations() int32 {
+	if m != nil {
+		return m.CatalogGenerations
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Conf)(nil), "api.Conf")
 }
@@ -188,26 +251,32 @@ func init() {
 func init() { proto.RegisterFile("api/conf.proto", fileDescriptor_deb6b35ebbfdf874) }
 
 var fileDescriptor_deb6b35ebbfdf874 = []byte{
-	// 323 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x92, 0x4f, 0x4b, 0x33, 0x31,
-	0x10, 0xc6, 0xd9, 0x77, 0xfb, 0x77, 0x5e, 0x5b, 0xea, 0xa0, 0x92, 0x93, 0x16, 0xf1, 0x50, 0x44,
-	0xd6, 0x83, 0x9e, 0x3c, 0xb6, 0x45, 0x10, 0xb7, 0x58, 0xb6, 0xfd, 0x02, 0xe9, 0x9a, 0x6a, 0x30,
-	0x66, 0x96, 0x34, 0x16, 0xfa, 0xcd, 0x3d, 0x4a, 0x66, 0x6b, 0x51, 0x57, 0x6f, 0xf3, 0xfc, 0x9e,
-	0x67, 0x32, 0x13, 0x18, 0xe8, 0xca, 0x42, 0x5f, 0xe6, 0x64, 0x97, 0x49, 0xe1, 0xc8, 0x13, 0xc6,
-	0xb2, 0xd0, 0xa7, 0xef, 0x31, 0xd4, 0x46, 0x64, 0x97, 0x88, 0x50, 0x9b, 0x92, 0xf3, 0x22, 0xea,
-	0x47, 0x83, 0x76, 0xc6, 0x35, 0x0a, 0x68, 0x66, 0x44, 0x7e, 0xac, 0x9d, 0xf8, 0xc7, 0xf8, 0x53,
-	0x06, 0x67, 0x2c, 0xbd, 0x0c, 0x4e, 0x5c, 0x3a, 0x5b, 0x89, 0x47, 0xd0, 0x48, 0xe9, 0x29, 0x18,
-	0x35, 0x36, 0xb6, 0x0a, 0xcf, 0xa0, 0x93, 0xea, 0x57, 0xed, 0x1f, 0x0a, 0x65, 0x6f, 0xb5, 0x51,
-	0xa2, 0xde, 0x8f, 0x06, 0xf5, 0xec, 0x3b, 0xc4, 0x1e, 0xc4, 0xf3, 0x74, 0x26, 0x1a, 0xfd, 0x68,
-	0xd0, 0xca, 0x42, 0x89, 0xe7, 0xd0, 0x9b, 0xa7, 0xb3, 0x99, 0x72, 0x6b, 0xe5, 0xee, 0xd5, 0x86,
-	0x5b, 0x9b, 0xfc, 0x72, 0x85, 0xe3, 0x05, 0xec, 0xef, 0xd8, 0x48, 0x39, 0xcf, 0xe1, 0x16, 0x87,
-	0xab, 0x06, 0x1e, 0x40, 0x9d, 0x87, 0x8b, 0x36, 0x4f, 0x2b, 0x45, 0x98, 0xc7, 0xc5, 0x44, 0x1b,
-	0xa3, 0x57, 0x2a, 0x27, 0xfb, 0x28, 0x80, 0x57, 0xad, 0x70, 0x3c, 0x06, 0x60, 0x36, 0xa2, 0x37,
-	0xeb, 0xc5, 0x7f, 0x4e, 0x7d, 0x21, 0x78, 0x03, 0x82, 0xd5, 0x9d, 0xf5, 0xca, 0xad, 0xa5, 0x99,
-	0xe8, 0xdc, 0xd1, 0xf6, 0xcd, 0x3d, 0x4e, 0xff, 0xe9, 0x97, 0x7b, 0x98, 0x4d, 0x4a, 0xf9, 0x4b,
-	0xd8, 0x76, 0x2a, 0xfd, 0xb3, 0xe8, 0x94, 0xff, 0xfe, 0xc9, 0xf1, 0x1a, 0x0e, 0x03, 0x1b, 0x12,
-	0xf9, 0x95, 0x77, 0xb2, 0xd8, 0x35, 0x74, 0xb9, 0xe1, 0x77, 0x73, 0x78, 0x02, 0x98, 0xdb, 0x44,
-	0x2e, 0x94, 0xd3, 0x79, 0x62, 0xb4, 0xd9, 0x24, 0xb2, 0xd0, 0xc3, 0x76, 0xb8, 0x86, 0x69, 0x38,
-	0x90, 0x45, 0x83, 0xef, 0xe4, 0xea, 0x23, 0x00, 0x00, 0xff, 0xff, 0xdf, 0x78, 0x28, 0x41, 0x39,
-	0x02, 0x00, 0x00,
+	// 427 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x93, 0x4d, 0x6f, 0x13, 0x31,
+	0x10, 0x86, 0x15, 0xf2, 0xd1, 0x64, 0xe8, 0x47, 0x3a, 0xb4, 0xc8, 0xe2, 0x00, 0x51, 0xc5, 0x21,
+	0x42, 0x68, 0x39, 0xc0, 0x89, 0x63, 0x52, 0x8a, 0x10, 0x1b, 0x11, 0x65, 0xfb, 0x07, 0xdc, 0xed,
+	0x34, 0x58, 0x38, 0x9e, 0x95, 0xd7, 0x14, 0x2d, 0x3f, 0x9c, 0x33, 0xf2, 0x6c, 0xba, 0xb0, 0x4d,
+	0x7b, 0x9b, 0xf7, 0x79, 0x67, 0x3c, 0x63, 0xd9, 0x03, 0x87, 0xba, 0x30, 0xef, 0x72, 0x76, 0x37,
+	0x49, 0xe1, 0x39, 0x30, 0x76, 0x75, 0x61, 0xce, 0xfe, 0xf4, 0xa1, 0x37, 0x67, 0x77, 0x83, 0x08,
+	0xbd, 0x25, 0xfb, 0xa0, 0x3a, 0x93, 0xce, 0x74, 0xb4, 0x92, 0x18, 0x15, 0xec, 0xad, 0x98, 0xc3,
+	0xb9, 0xf1, 0xea, 0x89, 0xe0, 0x3b, 0x19, 0x9d, 0x73, 0x1d, 0x74, 0x74, 0xba, 0xb5, 0xb3, 0x95,
+	0xf8, 0x1c, 0x06, 0x29, 0xaf, 0xa3, 0xd1, 0x13, 0x63, 0xab, 0xf0, 0x35, 0x1c, 0xa4, 0x66, 0x63,
+	0xc2, 0xb7, 0x82, 0xdc, 0x85, 0xb1, 0xa4, 0xfa, 0x93, 0xce, 0xb4, 0xbf, 0x6a, 0x43, 0x1c, 0x43,
+	0xf7, 0x32, 0xcd, 0xd4, 0x60, 0xd2, 0x99, 0x0e, 0x57, 0x31, 0xc4, 0x37, 0x30, 0xbe, 0x4c, 0xb3,
+	0x8c, 0xfc, 0x2d, 0xf9, 0xaf, 0x54, 0x49, 0xe9, 0x9e, 0x9c, 0xbc, 0xc3, 0xf1, 0x2d, 0x1c, 0x37,
+	0x6c, 0x4e, 0x3e, 0x48, 0xf2, 0x50, 0x92, 0x77, 0x0d, 0x3c, 0x81, 0xbe, 0x34, 0x57, 0x23, 0xe9,
+	0x56, 0x8b, 0xd8, 0x4f, 0x82, 0x85, 0xb1, 0xd6, 0x94, 0x94, 0xb3, 0xbb, 0x56, 0x20, 0xa3, 0xee,
+	0x70, 0x7c, 0x09, 0x20, 0x6c, 0xce, 0x3f, 0x5d, 0x50, 0x4f, 0x25, 0xeb, 0x3f, 0x82, 0x1f, 0x41,
+	0x89, 0xfa, 0xe2, 0x02, 0xf9, 0x5b, 0x6d, 0x17, 0x26, 0xf7, 0xbc, 0x3d, 0x73, 0x5f, 0xb2, 0x1f,
+	0xf5, 0xeb, 0x39, 0x6c, 0x95, 0x72, 0xfe, 0x23, 0x4e, 0xbb, 0xd4, 0xe1, 0xbb, 0x3a, 0xa8, 0xef,
+	0x7d, 0x9f, 0xe3, 0x07, 0x38, 0x8d, 0x6c, 0xc6, 0x1c, 0xca, 0xe0, 0x75, 0xd1, 0x14, 0x1c, 0x4a,
+	0xc1, 0xc3, 0x26, 0x4e, 0xe1, 0xe8, 0x82, 0xfd, 0x26, 0xa3, 0xf5, 0x86, 0x5c, 0xc8, 0xcc, 0x6f,
+	0x52, 0x47, 0x32, 0xd4, 0x7d, 0x1c, 0xdf, 0x6e, 0xa1, 0xcb, 0xf0, 0xef, 0x01, 0xc6, 0x72, 0x6e,
+	0x1b, 0xe2, 0x0b, 0x18, 0x66, 0x95, 0xcb, 0x17, 0x7c, 0x4d, 0xea, 0x58, 0x12, 0x1a, 0x8d, 0x67,
+	0xb0, 0x1f, 0xe3, 0xbb, 0x8b, 0x2a, 0x94, 0x46, 0x2d, 0x16, 0x7f, 0xce, 0x27, 0xb7, 0x36, 0x8e,
+	0xd4, 0xb3, 0xfa, 0xe7, 0xd4, 0x2a, 0x76, 0xcf, 0x7e, 0x11, 0x15, 0x4d, 0xf1, 0x49, 0xfd, 0x73,
+	0x5a, 0x10, 0x13, 0xc0, 0xb9, 0x0e, 0xda, 0xf2, 0xfa, 0x33, 0x39, 0xf2, 0x3a, 0x18, 0x76, 0xa5,
+	0x3a, 0x95, 0xd4, 0x07, 0x9c, 0xd9, 0x2b, 0xc0, 0xdc, 0x25, 0xfa, 0x8a, 0xbc, 0xc9, 0x13, 0x6b,
+	0x6c, 0x95, 0xe8, 0xc2, 0xcc, 0x46, 0x71, 0x17, 0x96, 0x71, 0x3d, 0xae, 0x06, 0xb2, 0x25, 0xef,
+	0xff, 0x0e, 0x00, 0xc5, 0xbe, 0x7b, 0x77, 0x37, 0x03, 0x00, 0x00,
 }
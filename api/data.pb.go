@@ -51,6 +51,42 @@ func (FormType) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_51ac7b4dd81eed94, []int{0}
 }
 
+// Compression 表数据压缩方式
+type Compression int32
+
+const (
+	// None 不压缩
+	Compression_None Compression = 0
+	// Gzip gzip压缩
+	Compression_Gzip Compression = 1
+	// Flate flate压缩
+	Compression_Flate Compression = 2
+	// Snappy snappy压缩
+	Compression_Snappy Compression = 3
+)
+
+var Compression_name = map[int32]string{
+	0: "None",
+	1: "Gzip",
+	2: "Flate",
+	3: "Snappy",
+}
+
+var Compression_value = map[string]int32{
+	"None":   0,
+	"Gzip":   1,
+	"Flate":  2,
+	"Snappy": 3,
+}
+
+func (x Compression) String() string {
+	return proto.EnumName(Compression_name, int32(x))
+}
+
+func (Compression) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_51ac7b4dd81eed94, []int{1}
+}
+
 // Lily 数据库引擎对象
 type Lily struct {
 	// databases 数据库集合
@@ -101,10 +137,16 @@ type Database struct {
 	// Comment 数据库描述
 	Comment string `protobuf:"bytes,3,opt,name=Comment,proto3" json:"Comment,omitempty"`
 	// Forms 数据库表集合
-	Forms                map[string]*Form `protobuf:"bytes,4,rep,name=Forms,proto3" json:"Forms,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	Forms map[string]*Form `protobuf:"bytes,4,rep,name=Forms,proto3" json:"Forms,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// DataKeys 以主密钥加密的数据密钥集合，key为数据密钥版本
+	DataKeys map[uint32][]byte `protobuf:"bytes,5,rep,name=DataKeys,proto3" json:"DataKeys,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// KeyVersion 当前数据密钥版本，0表示未启用加密
+	KeyVersion uint32 `protobuf:"varint,6,opt,name=KeyVersion,proto3" json:"KeyVersion,omitempty"`
+	// SyncMode 数据库落盘策略(none/interval/always)，为空时使用配置文件中的 SyncMode
+	SyncMode             string   `protobuf:"bytes,7,opt,name=SyncMode,proto3" json:"SyncMode,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Database) Reset()         { *m = Database{} }
@@ -160,6 +202,27 @@ func (m *Database) GetForms() map[string]*Form {
 	return nil
 }
 
+func (m *Database) GetDataKeys() map[uint32][]byte {
+	if m != nil {
+		return m.DataKeys
+	}
+	return nil
+}
+
+func (m *Database) GetKeyVersion() uint32 {
+	if m != nil {
+		return m.KeyVersion
+	}
+	return 0
+}
+
+func (m *Database) GetSyncMode() string {
+	if m != nil {
+		return m.SyncMode
+	}
+	return ""
+}
+
 // Form 数据库表对象
 type Form struct {
 	// ID 表唯一ID，不能改变
@@ -171,10 +234,16 @@ type Form struct {
 	// FormType 表类型 SQL/Doc
 	FormType FormType `protobuf:"varint,4,opt,name=FormType,proto3,enum=api.FormType" json:"FormType,omitempty"`
 	// Indexes 索引ID集合
-	Indexes              map[string]*Index `protobuf:"bytes,5,rep,name=Indexes,proto3" json:"Indexes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+	Indexes map[string]*Index `protobuf:"bytes,5,rep,name=Indexes,proto3" json:"Indexes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Compression 表数据压缩方式
+	Compression Compression `protobuf:"varint,6,opt,name=Compression,proto3,enum=api.Compression" json:"Compression,omitempty"`
+	// Engine 表存储引擎名称
+	Engine string `protobuf:"bytes,7,opt,name=Engine,proto3" json:"Engine,omitempty"`
+	// Versions 每条数据保留的版本数，0与1均表示仅保留当前版本
+	Versions             uint32   `protobuf:"varint,8,opt,name=Versions,proto3" json:"Versions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Form) Reset()         { *m = Form{} }
@@ -237,6 +306,27 @@ func (m *Form) GetIndexes() map[string]*Index {
 	return nil
 }
 
+func (m *Form) GetCompression() Compression {
+	if m != nil {
+		return m.Compression
+	}
+	return Compression_None
+}
+
+func (m *Form) GetEngine() string {
+	if m != nil {
+		return m.Engine
+	}
+	return ""
+}
+
+func (m *Form) GetVersions() uint32 {
+	if m != nil {
+		return m.Versions
+	}
+	return 0
+}
+
 // Index 索引对象
 type Index struct {
 	// ID 索引唯一ID
@@ -244,7 +334,13 @@ type Index struct {
 	// Primary 是否主键
 	Primary bool `protobuf:"varint,2,opt,name=Primary,proto3" json:"Primary,omitempty"`
 	// KeyStructure 按照规范结构组成的索引字段名称，由对象结构层级字段通过'.'组成，如'i','in.s'
-	KeyStructure         string   `protobuf:"bytes,3,opt,name=KeyStructure,proto3" json:"KeyStructure,omitempty"`
+	KeyStructure string `protobuf:"bytes,3,opt,name=KeyStructure,proto3" json:"KeyStructure,omitempty"`
+	// Ordered 字符串索引key是否按字典序编码，旧版本创建的索引为散列编码，启动时依据数据重建
+	Ordered bool `protobuf:"varint,4,opt,name=Ordered,proto3" json:"Ordered,omitempty"`
+	// Unique 是否唯一索引，写入数据的索引值不能与其它主键的记录重复
+	Unique bool `protobuf:"varint,5,opt,name=Unique,proto3" json:"Unique,omitempty"`
+	// Building 是否正在依据表中已有数据回填，回填完成前检索不采用该索引
+	Building             bool     `protobuf:"varint,6,opt,name=Building,proto3" json:"Building,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -296,6 +392,27 @@ func (m *Index) GetKeyStructure() string {
 	return ""
 }
 
+func (m *Index) GetOrdered() bool {
+	if m != nil {
+		return m.Ordered
+	}
+	return false
+}
+
+func (m *Index) GetUnique() bool {
+	if m != nil {
+		return m.Unique
+	}
+	return false
+}
+
+func (m *Index) GetBuilding() bool {
+	if m != nil {
+		return m.Building
+	}
+	return false
+}
+
 // Selector 检索选择器
 type Selector struct {
 	// Conditions 条件查询
@@ -497,9 +614,11 @@ func (m *Sort) GetASC() bool {
 
 func init() {
 	proto.RegisterEnum("api.FormType", FormType_name, FormType_value)
+	proto.RegisterEnum("api.Compression", Compression_name, Compression_value)
 	proto.RegisterType((*Lily)(nil), "api.Lily")
 	proto.RegisterMapType((map[string]*Database)(nil), "api.Lily.DatabasesEntry")
 	proto.RegisterType((*Database)(nil), "api.Database")
+	proto.RegisterMapType((map[uint32][]byte)(nil), "api.Database.DataKeysEntry")
 	proto.RegisterMapType((map[string]*Form)(nil), "api.Database.FormsEntry")
 	proto.RegisterType((*Form)(nil), "api.Form")
 	proto.RegisterMapType((map[string]*Index)(nil), "api.Form.IndexesEntry")
@@ -512,36 +631,48 @@ func init() {
 func init() { proto.RegisterFile("api/data.proto", fileDescriptor_51ac7b4dd81eed94) }
 
 var fileDescriptor_51ac7b4dd81eed94 = []byte{
-	// 493 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x53, 0x4d, 0xaf, 0x12, 0x31,
-	0x14, 0xb5, 0xcc, 0x20, 0xcc, 0x7d, 0x40, 0x48, 0x63, 0x4c, 0x43, 0x34, 0x8f, 0x8c, 0x1b, 0x74,
-	0x51, 0xcd, 0x33, 0x31, 0xc6, 0x9d, 0x0f, 0x7c, 0x09, 0x81, 0x18, 0xec, 0xa8, 0xfb, 0x32, 0x74,
-	0xd1, 0x3c, 0xe6, 0x23, 0x65, 0x30, 0xce, 0xda, 0xa5, 0xbf, 0xc9, 0x1f, 0xe4, 0xbf, 0x30, 0xb7,
-	0x33, 0x1d, 0x86, 0xc8, 0xee, 0xed, 0xee, 0x3d, 0xe7, 0xf4, 0xb4, 0xe7, 0xb6, 0x85, 0x91, 0xcc,
-	0xf5, 0xeb, 0x9d, 0x2c, 0x24, 0xcf, 0x4d, 0x56, 0x64, 0xd4, 0x93, 0xb9, 0x0e, 0x7f, 0x13, 0xf0,
-	0xd7, 0x7a, 0x5f, 0xd2, 0x77, 0x10, 0x20, 0xb7, 0x95, 0x07, 0x75, 0x60, 0x64, 0xea, 0xcd, 0xae,
-	0x6e, 0x18, 0x97, 0xb9, 0xe6, 0xc8, 0xf2, 0x85, 0xa3, 0x3e, 0xa5, 0x85, 0x29, 0xc5, 0x49, 0x3a,
-	0x59, 0xc1, 0xe8, 0x9c, 0xa4, 0x63, 0xf0, 0xee, 0x55, 0xc9, 0xc8, 0x94, 0xcc, 0x02, 0x81, 0x25,
-	0x7d, 0x01, 0xdd, 0x1f, 0x72, 0x7f, 0x54, 0xac, 0x33, 0x25, 0xb3, 0xab, 0x9b, 0xa1, 0xf5, 0x75,
-	0xab, 0x44, 0xc5, 0x7d, 0xe8, 0xbc, 0x27, 0xe1, 0x1f, 0x02, 0x7d, 0x87, 0xd3, 0x11, 0x74, 0x96,
-	0x8b, 0xda, 0xa6, 0xb3, 0x5c, 0x50, 0x0a, 0xfe, 0x67, 0x99, 0x54, 0x26, 0x81, 0xb0, 0x35, 0x65,
-	0xd0, 0x9b, 0x67, 0x49, 0xa2, 0xd2, 0x82, 0x79, 0x16, 0x76, 0x2d, 0xe5, 0xd0, 0xbd, 0xcb, 0x4c,
-	0x72, 0x60, 0x7e, 0x2b, 0x8b, 0xf3, 0xe6, 0x96, 0xaa, 0xb2, 0x54, 0xb2, 0xc9, 0x1c, 0xe0, 0x04,
-	0x5e, 0xc8, 0x70, 0x7d, 0x9e, 0x21, 0xb0, 0x7e, 0xb8, 0xa2, 0x7d, 0xfe, 0xbf, 0x04, 0x7c, 0xc4,
-	0x1e, 0x78, 0xf6, 0x97, 0xd0, 0x47, 0x97, 0xaf, 0x65, 0xae, 0x98, 0x3f, 0x25, 0xb3, 0x51, 0x3d,
-	0x32, 0x07, 0x8a, 0x86, 0xa6, 0x6f, 0xa0, 0xb7, 0x4c, 0x77, 0xea, 0xa7, 0x3a, 0xb0, 0xae, 0x0d,
-	0xfa, 0xb4, 0x51, 0xf2, 0x9a, 0xa8, 0x62, 0x3a, 0xd9, 0xe4, 0x0e, 0x06, 0x6d, 0xe2, 0x42, 0xd4,
-	0xe9, 0x79, 0x54, 0xb0, 0x8e, 0x76, 0x4d, 0x3b, 0xeb, 0x37, 0xe8, 0x5a, 0xec, 0xbf, 0xac, 0x0c,
-	0x7a, 0x1b, 0xa3, 0x13, 0x69, 0x4a, 0x6b, 0xd0, 0x17, 0xae, 0xa5, 0x21, 0x0c, 0x56, 0xaa, 0x8c,
-	0x0a, 0x73, 0x8c, 0x8b, 0xa3, 0x51, 0x75, 0xec, 0x33, 0x2c, 0xfc, 0x45, 0xa0, 0x1f, 0xa9, 0xbd,
-	0x8a, 0x8b, 0xcc, 0x50, 0x0e, 0x30, 0xcf, 0xd2, 0x9d, 0x2e, 0x74, 0x96, 0xba, 0x57, 0x39, 0xb2,
-	0xc7, 0x69, 0x60, 0xd1, 0x52, 0xe0, 0x98, 0xa3, 0x7b, 0x9d, 0xdb, 0x7d, 0x87, 0xc2, 0xd6, 0xf4,
-	0x39, 0xf8, 0x51, 0x66, 0xaa, 0x19, 0xbb, 0x7b, 0x43, 0x40, 0x58, 0x98, 0x3e, 0x81, 0xee, 0x5a,
-	0x27, 0xba, 0xb0, 0x83, 0x1e, 0x8a, 0xaa, 0x09, 0x57, 0x10, 0x34, 0xb6, 0x28, 0xd9, 0x48, 0x23,
-	0x93, 0x3a, 0x63, 0xd5, 0xe0, 0x5e, 0x28, 0x71, 0x57, 0x8a, 0x35, 0x2a, 0xbf, 0xdb, 0xc9, 0xe1,
-	0x66, 0x03, 0x51, 0x35, 0x21, 0x87, 0x66, 0xab, 0x0b, 0x3e, 0x63, 0xf0, 0x3e, 0x46, 0xf3, 0x7a,
-	0x54, 0x58, 0xbe, 0x7a, 0x76, 0xba, 0x7e, 0xda, 0x03, 0x2f, 0xfa, 0xb2, 0x1e, 0x3f, 0xc2, 0x62,
-	0x91, 0xc5, 0x63, 0x72, 0x7b, 0x0d, 0x34, 0x4e, 0xb9, 0xdc, 0x2a, 0xa3, 0x63, 0xbe, 0xc7, 0xcf,
-	0x29, 0x73, 0x7d, 0x1b, 0xe0, 0xd3, 0xde, 0xe0, 0xbf, 0xde, 0x3e, 0xb6, 0xdf, 0xfb, 0xed, 0xbf,
-	0x00, 0x00, 0x00, 0xff, 0xff, 0x7d, 0x17, 0x43, 0x53, 0xf0, 0x03, 0x00, 0x00,
+	// 675 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x54, 0x4d, 0x6f, 0xd3, 0x4c,
+	0x10, 0xae, 0x3f, 0x92, 0xd8, 0xd3, 0x26, 0xb2, 0x56, 0xaf, 0xaa, 0x55, 0x5e, 0xa0, 0x51, 0xb8,
+	0x84, 0x1e, 0x0c, 0x0a, 0x12, 0x54, 0x70, 0xa2, 0x49, 0x8b, 0xaa, 0x94, 0x52, 0xd6, 0xd0, 0xfb,
+	0x36, 0x5e, 0x55, 0xab, 0xc6, 0x1f, 0xac, 0x1d, 0x84, 0xb9, 0x72, 0xe4, 0x67, 0xf0, 0x8b, 0xf8,
+	0x25, 0xfc, 0x05, 0xb4, 0xbb, 0x5e, 0xd7, 0x16, 0xbd, 0x71, 0x9b, 0x67, 0x3e, 0x1e, 0xcf, 0x3c,
+	0x33, 0x5e, 0x18, 0xd1, 0x9c, 0x3f, 0x8d, 0x69, 0x49, 0xc3, 0x5c, 0x64, 0x65, 0x86, 0x1c, 0x9a,
+	0xf3, 0xe9, 0x0f, 0x0b, 0xdc, 0x73, 0xbe, 0xa9, 0xd0, 0x0b, 0xf0, 0x65, 0xec, 0x9a, 0x16, 0xac,
+	0xc0, 0xd6, 0xc4, 0x99, 0xed, 0xce, 0x71, 0x48, 0x73, 0x1e, 0xca, 0x68, 0xb8, 0x34, 0xa1, 0x93,
+	0xb4, 0x14, 0x15, 0xb9, 0x4b, 0x1d, 0xaf, 0x60, 0xd4, 0x0d, 0xa2, 0x00, 0x9c, 0x5b, 0x56, 0x61,
+	0x6b, 0x62, 0xcd, 0x7c, 0x22, 0x4d, 0xf4, 0x18, 0x7a, 0x5f, 0xe8, 0x66, 0xcb, 0xb0, 0x3d, 0xb1,
+	0x66, 0xbb, 0xf3, 0xa1, 0xe2, 0x35, 0x55, 0x44, 0xc7, 0x5e, 0xd9, 0x47, 0xd6, 0xf4, 0xb7, 0x0d,
+	0x9e, 0xf1, 0xa3, 0x11, 0xd8, 0x67, 0xcb, 0x9a, 0xc6, 0x3e, 0x5b, 0x22, 0x04, 0xee, 0x05, 0x4d,
+	0x34, 0x89, 0x4f, 0x94, 0x8d, 0x30, 0x0c, 0x16, 0x59, 0x92, 0xb0, 0xb4, 0xc4, 0x8e, 0x72, 0x1b,
+	0x88, 0x42, 0xe8, 0x9d, 0x66, 0x22, 0x29, 0xb0, 0xdb, 0x9a, 0xc5, 0x70, 0x87, 0x2a, 0xa4, 0x67,
+	0xd1, 0x69, 0xe8, 0xa5, 0xfe, 0xf2, 0x8a, 0x55, 0x05, 0xee, 0xa9, 0x92, 0xff, 0xbb, 0x25, 0x26,
+	0xaa, 0xab, 0x9a, 0x64, 0xf4, 0x08, 0x60, 0xc5, 0xaa, 0x2b, 0x26, 0x0a, 0x9e, 0xa5, 0xb8, 0x3f,
+	0xb1, 0x66, 0x43, 0xd2, 0xf2, 0xa0, 0x31, 0x78, 0x51, 0x95, 0xae, 0xdf, 0x65, 0x31, 0xc3, 0x03,
+	0xd5, 0x63, 0x83, 0xc7, 0x0b, 0x80, 0xbb, 0x4e, 0xee, 0x11, 0xee, 0xa0, 0x2b, 0x9c, 0xaf, 0x3a,
+	0x92, 0x15, 0x2d, 0xd1, 0xc6, 0xaf, 0x61, 0xd8, 0xe9, 0xad, 0xcd, 0x33, 0xd4, 0x3c, 0xff, 0xb5,
+	0x79, 0xf6, 0xda, 0x8a, 0xff, 0xb2, 0xc1, 0x95, 0x84, 0xff, 0xa8, 0xf6, 0x13, 0xf0, 0x24, 0xcb,
+	0xc7, 0x2a, 0x67, 0xd8, 0x9d, 0x58, 0xb3, 0x51, 0xbd, 0x64, 0xe3, 0x24, 0x4d, 0x18, 0x3d, 0x83,
+	0xc1, 0x59, 0x1a, 0xb3, 0xaf, 0xcc, 0xe8, 0xbc, 0xdf, 0x64, 0x86, 0x75, 0x40, 0x4b, 0x6c, 0xd2,
+	0xd0, 0x1c, 0x76, 0x17, 0x59, 0x92, 0x0b, 0x56, 0x34, 0x12, 0x8f, 0xe6, 0x81, 0xaa, 0x6a, 0xf9,
+	0x49, 0x3b, 0x09, 0xed, 0x43, 0xff, 0x24, 0xbd, 0xe1, 0xa9, 0xd1, 0xbc, 0x46, 0x72, 0x1b, 0xf5,
+	0x62, 0x0a, 0xec, 0x29, 0x81, 0x1a, 0x3c, 0x3e, 0x85, 0xbd, 0x76, 0x03, 0xf7, 0xec, 0x63, 0xd2,
+	0xdd, 0x07, 0xa8, 0x1e, 0x54, 0x4d, 0x5b, 0xd3, 0x9f, 0x16, 0xf4, 0x94, 0xf3, 0x2f, 0x51, 0x31,
+	0x0c, 0x2e, 0x05, 0x4f, 0xa8, 0xa8, 0x14, 0x83, 0x47, 0x0c, 0x44, 0x53, 0xd8, 0x5b, 0xb1, 0x2a,
+	0x2a, 0xc5, 0x76, 0x5d, 0x6e, 0x05, 0xab, 0xf5, 0xed, 0xf8, 0x64, 0xf5, 0x7b, 0x11, 0x33, 0xc1,
+	0x62, 0xa5, 0xb1, 0x47, 0x0c, 0x94, 0xd3, 0x7e, 0x4a, 0xf9, 0xe7, 0x2d, 0xc3, 0x3d, 0x15, 0xa8,
+	0x91, 0x9c, 0xf6, 0x78, 0xcb, 0x37, 0x31, 0x4f, 0x6f, 0x94, 0x6c, 0x1e, 0x69, 0xf0, 0xf4, 0xbb,
+	0x05, 0x5e, 0xc4, 0x36, 0x6c, 0x5d, 0x66, 0x02, 0x85, 0x00, 0x8b, 0x2c, 0x8d, 0x79, 0xa9, 0x84,
+	0xd1, 0xbf, 0xff, 0xa8, 0x56, 0xb8, 0x76, 0x93, 0x56, 0x86, 0xbc, 0x8e, 0xe8, 0x96, 0xe7, 0x6a,
+	0x8a, 0x21, 0x51, 0x36, 0x7a, 0x08, 0x6e, 0x94, 0x09, 0x7d, 0x1a, 0xe6, 0x56, 0xa5, 0x83, 0x28,
+	0xb7, 0xbc, 0xc1, 0x73, 0x9e, 0xf0, 0x52, 0xf5, 0x3e, 0x24, 0x1a, 0x4c, 0x57, 0xe0, 0x37, 0xb4,
+	0x32, 0xe5, 0x92, 0x0a, 0x9a, 0xd4, 0x8a, 0x69, 0x20, 0xbf, 0x25, 0x53, 0xcc, 0x25, 0x4a, 0x5b,
+	0x66, 0x5e, 0xa9, 0x45, 0x38, 0xfa, 0xa0, 0x15, 0x98, 0x86, 0xd0, 0x7c, 0xea, 0x1e, 0x9e, 0x00,
+	0x9c, 0x37, 0xd1, 0xa2, 0x16, 0x5e, 0x9a, 0x87, 0x0f, 0xee, 0xae, 0x16, 0x0d, 0xc0, 0x89, 0x3e,
+	0x9c, 0x07, 0x3b, 0xd2, 0x58, 0x66, 0xeb, 0xc0, 0x3a, 0x3c, 0xea, 0x9c, 0x1d, 0xf2, 0xc0, 0xbd,
+	0xc8, 0x52, 0x16, 0xec, 0x48, 0xeb, 0xed, 0x37, 0x9e, 0x07, 0x16, 0xf2, 0xa1, 0x77, 0xba, 0xa1,
+	0x25, 0x0b, 0x6c, 0x04, 0xd0, 0x8f, 0x52, 0x9a, 0xe7, 0x55, 0xe0, 0x1c, 0x1f, 0x00, 0x5a, 0xa7,
+	0x21, 0xbd, 0x66, 0x82, 0xaf, 0xc3, 0x8d, 0x7c, 0x3f, 0x69, 0xce, 0x8f, 0x7d, 0xf9, 0x97, 0x5e,
+	0xca, 0xa7, 0xf7, 0xba, 0xaf, 0x5e, 0xe0, 0xe7, 0x7f, 0x06, 0x00, 0xcb, 0x59, 0x54, 0xde, 0x93,
+	0x05, 0x00, 0x00,
 }
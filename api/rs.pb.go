@@ -28,16 +28,20 @@ const (
 	Code_Success Code = 0
 	// Fail 失败
 	Code_Fail Code = 1
+	// UniqueViolation 写入数据违反唯一索引约束
+	Code_UniqueViolation Code = 2
 )
 
 var Code_name = map[int32]string{
 	0: "Success",
 	1: "Fail",
+	2: "UniqueViolation",
 }
 
 var Code_value = map[string]int32{
-	"Success": 0,
-	"Fail":    1,
+	"Success":         0,
+	"Fail":            1,
+	"UniqueViolation": 2,
 }
 
 func (x Code) String() string {
@@ -448,7 +452,13 @@ type ReqCreateForm struct {
 	// Comment 表描述
 	Comment string `protobuf:"bytes,3,opt,name=Comment,proto3" json:"Comment,omitempty"`
 	// FormType 表类型
-	FormType             FormType `protobuf:"varint,4,opt,name=FormType,proto3,enum=api.FormType" json:"FormType,omitempty"`
+	FormType FormType `protobuf:"varint,4,opt,name=FormType,proto3,enum=api.FormType" json:"FormType,omitempty"`
+	// Compression 表数据压缩方式
+	Compression Compression `protobuf:"varint,5,opt,name=Compression,proto3,enum=api.Compression" json:"Compression,omitempty"`
+	// Engine 表存储引擎名称，为空时使用默认存储引擎
+	Engine string `protobuf:"bytes,6,opt,name=Engine,proto3" json:"Engine,omitempty"`
+	// Versions 每条数据保留的版本数，默认仅保留当前版本
+	Versions             uint32   `protobuf:"varint,7,opt,name=Versions,proto3" json:"Versions,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -507,6 +517,27 @@ func (m *ReqCreateForm) GetFormType() FormType {
 	return FormType_SQL
 }
 
+func (m *ReqCreateForm) GetCompression() Compression {
+	if m != nil {
+		return m.Compression
+	}
+	return Compression_None
+}
+
+func (m *ReqCreateForm) GetEngine() string {
+	if m != nil {
+		return m.Engine
+	}
+	return ""
+}
+
+func (m *ReqCreateForm) GetVersions() uint32 {
+	if m != nil {
+		return m.Versions
+	}
+	return 0
+}
+
 // ReqKey 请求新建主键
 type ReqCreateKey struct {
 	// DatabaseName 数据库名称
@@ -573,7 +604,9 @@ type ReqCreateIndex struct {
 	// FormName 表名称
 	FormName string `protobuf:"bytes,2,opt,name=FormName,proto3" json:"FormName,omitempty"`
 	// Comment 主键结构名，按照规范结构组成的主键字段名称，由对象结构层级字段通过'.'组成，如'i','in.s'
-	KeyStructure         string   `protobuf:"bytes,3,opt,name=KeyStructure,proto3" json:"KeyStructure,omitempty"`
+	KeyStructure string `protobuf:"bytes,3,opt,name=KeyStructure,proto3" json:"KeyStructure,omitempty"`
+	// KeyStructures 复合索引字段名称集合，按顺序组成复合索引key，不为空时忽略 KeyStructure
+	KeyStructures        []string `protobuf:"bytes,4,rep,name=KeyStructures,proto3" json:"KeyStructures,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -625,12 +658,280 @@ func (m *ReqCreateIndex) GetKeyStructure() string {
 	return ""
 }
 
+func (m *ReqCreateIndex) GetKeyStructures() []string {
+	if m != nil {
+		return m.KeyStructures
+	}
+	return nil
+}
+
+// ReqDropDatabase 请求删除库
+type ReqDropDatabase struct {
+	// Name 数据库名称
+	Name                 string   `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReqDropDatabase) Reset()         { *m = ReqDropDatabase{} }
+func (m *ReqDropDatabase) String() string { return proto.CompactTextString(m) }
+func (*ReqDropDatabase) ProtoMessage()    {}
+func (*ReqDropDatabase) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{11}
+}
+
+func (m *ReqDropDatabase) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReqDropDatabase.Unmarshal(m, b)
+}
+func (m *ReqDropDatabase) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReqDropDatabase.Marshal(b, m, deterministic)
+}
+func (m *ReqDropDatabase) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReqDropDatabase.Merge(m, src)
+}
+func (m *ReqDropDatabase) XXX_Size() int {
+	return xxx_messageInfo_ReqDropDatabase.Size(m)
+}
+func (m *ReqDropDatabase) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReqDropDatabase.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReqDropDatabase proto.InternalMessageInfo
+
+func (m *ReqDropDatabase) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+// ReqDropForm 请求删除表
+type ReqDropForm struct {
+	// DatabaseName 数据库名称
+	DatabaseName string `protobuf:"bytes,1,opt,name=DatabaseName,proto3" json:"DatabaseName,omitempty"`
+	// FormName 表名称
+	FormName             string   `protobuf:"bytes,2,opt,name=FormName,proto3" json:"FormName,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReqDropForm) Reset()         { *m = ReqDropForm{} }
+func (m *ReqDropForm) String() string { return proto.CompactTextString(m) }
+func (*ReqDropForm) ProtoMessage()    {}
+func (*ReqDropForm) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{12}
+}
+
+func (m *ReqDropForm) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReqDropForm.Unmarshal(m, b)
+}
+func (m *ReqDropForm) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReqDropForm.Marshal(b, m, deterministic)
+}
+func (m *ReqDropForm) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReqDropForm.Merge(m, src)
+}
+func (m *ReqDropForm) XXX_Size() int {
+	return xxx_messageInfo_ReqDropForm.Size(m)
+}
+func (m *ReqDropForm) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReqDropForm.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReqDropForm proto.InternalMessageInfo
+
+func (m *ReqDropForm) GetDatabaseName() string {
+	if m != nil {
+		return m.DatabaseName
+	}
+	return ""
+}
+
+func (m *ReqDropForm) GetFormName() string {
+	if m != nil {
+		return m.FormName
+	}
+	return ""
+}
+
+// ReqDropIndex 请求删除索引
+type ReqDropIndex struct {
+	// DatabaseName 数据库名称
+	DatabaseName string `protobuf:"bytes,1,opt,name=DatabaseName,proto3" json:"DatabaseName,omitempty"`
+	// FormName 表名称
+	FormName string `protobuf:"bytes,2,opt,name=FormName,proto3" json:"FormName,omitempty"`
+	// KeyStructure 索引结构名
+	KeyStructure         string   `protobuf:"bytes,3,opt,name=KeyStructure,proto3" json:"KeyStructure,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReqDropIndex) Reset()         { *m = ReqDropIndex{} }
+func (m *ReqDropIndex) String() string { return proto.CompactTextString(m) }
+func (*ReqDropIndex) ProtoMessage()    {}
+func (*ReqDropIndex) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{13}
+}
+
+func (m *ReqDropIndex) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReqDropIndex.Unmarshal(m, b)
+}
+func (m *ReqDropIndex) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReqDropIndex.Marshal(b, m, deterministic)
+}
+func (m *ReqDropIndex) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReqDropIndex.Merge(m, src)
+}
+func (m *ReqDropIndex) XXX_Size() int {
+	return xxx_messageInfo_ReqDropIndex.Size(m)
+}
+func (m *ReqDropIndex) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReqDropIndex.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReqDropIndex proto.InternalMessageInfo
+
+func (m *ReqDropIndex) GetDatabaseName() string {
+	if m != nil {
+		return m.DatabaseName
+	}
+	return ""
+}
+
+func (m *ReqDropIndex) GetFormName() string {
+	if m != nil {
+		return m.FormName
+	}
+	return ""
+}
+
+func (m *ReqDropIndex) GetKeyStructure() string {
+	if m != nil {
+		return m.KeyStructure
+	}
+	return ""
+}
+
+// ReqRenameDatabase 请求重命名库
+type ReqRenameDatabase struct {
+	// Name 数据库名称
+	Name string `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	// NewName 新数据库名称
+	NewName              string   `protobuf:"bytes,2,opt,name=NewName,proto3" json:"NewName,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReqRenameDatabase) Reset()         { *m = ReqRenameDatabase{} }
+func (m *ReqRenameDatabase) String() string { return proto.CompactTextString(m) }
+func (*ReqRenameDatabase) ProtoMessage()    {}
+func (*ReqRenameDatabase) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{14}
+}
+
+func (m *ReqRenameDatabase) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReqRenameDatabase.Unmarshal(m, b)
+}
+func (m *ReqRenameDatabase) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReqRenameDatabase.Marshal(b, m, deterministic)
+}
+func (m *ReqRenameDatabase) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReqRenameDatabase.Merge(m, src)
+}
+func (m *ReqRenameDatabase) XXX_Size() int {
+	return xxx_messageInfo_ReqRenameDatabase.Size(m)
+}
+func (m *ReqRenameDatabase) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReqRenameDatabase.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReqRenameDatabase proto.InternalMessageInfo
+
+func (m *ReqRenameDatabase) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ReqRenameDatabase) GetNewName() string {
+	if m != nil {
+		return m.NewName
+	}
+	return ""
+}
+
+// ReqRenameForm 请求重命名表
+type ReqRenameForm struct {
+	// DatabaseName 数据库名称
+	DatabaseName string `protobuf:"bytes,1,opt,name=DatabaseName,proto3" json:"DatabaseName,omitempty"`
+	// FormName 表名称
+	FormName string `protobuf:"bytes,2,opt,name=FormName,proto3" json:"FormName,omitempty"`
+	// NewName 新表名称
+	NewName              string   `protobuf:"bytes,3,opt,name=NewName,proto3" json:"NewName,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReqRenameForm) Reset()         { *m = ReqRenameForm{} }
+func (m *ReqRenameForm) String() string { return proto.CompactTextString(m) }
+func (*ReqRenameForm) ProtoMessage()    {}
+func (*ReqRenameForm) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{15}
+}
+
+func (m *ReqRenameForm) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReqRenameForm.Unmarshal(m, b)
+}
+func (m *ReqRenameForm) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReqRenameForm.Marshal(b, m, deterministic)
+}
+func (m *ReqRenameForm) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReqRenameForm.Merge(m, src)
+}
+func (m *ReqRenameForm) XXX_Size() int {
+	return xxx_messageInfo_ReqRenameForm.Size(m)
+}
+func (m *ReqRenameForm) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReqRenameForm.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReqRenameForm proto.InternalMessageInfo
+
+func (m *ReqRenameForm) GetDatabaseName() string {
+	if m != nil {
+		return m.DatabaseName
+	}
+	return ""
+}
+
+func (m *ReqRenameForm) GetFormName() string {
+	if m != nil {
+		return m.FormName
+	}
+	return ""
+}
+
+func (m *ReqRenameForm) GetNewName() string {
+	if m != nil {
+		return m.NewName
+	}
+	return ""
+}
+
 // ReqPutD 新增数据
 type ReqPutD struct {
 	// Key 数据库名称
 	Key string `protobuf:"bytes,1,opt,name=Key,proto3" json:"Key,omitempty"`
 	// Value 插入数据对象
-	Value                []byte   `protobuf:"bytes,2,opt,name=Value,proto3" json:"Value,omitempty"`
+	Value []byte `protobuf:"bytes,2,opt,name=Value,proto3" json:"Value,omitempty"`
+	// TTL 存活时间 单位：毫秒，0表示永不过期
+	TTL                  int64    `protobuf:"varint,3,opt,name=TTL,proto3" json:"TTL,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -640,7 +941,7 @@ func (m *ReqPutD) Reset()         { *m = ReqPutD{} }
 func (m *ReqPutD) String() string { return proto.CompactTextString(m) }
 func (*ReqPutD) ProtoMessage()    {}
 func (*ReqPutD) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ae6ce81ad544face, []int{11}
+	return fileDescriptor_ae6ce81ad544face, []int{16}
 }
 
 func (m *ReqPutD) XXX_Unmarshal(b []byte) error {
@@ -675,6 +976,13 @@ func (m *ReqPutD) GetValue() []byte {
 	return nil
 }
 
+func (m *ReqPutD) GetTTL() int64 {
+	if m != nil {
+		return m.TTL
+	}
+	return 0
+}
+
 // RespPutD 响应新增数据
 type RespPutD struct {
 	// Code 响应结果码
@@ -692,7 +1000,7 @@ func (m *RespPutD) Reset()         { *m = RespPutD{} }
 func (m *RespPutD) String() string { return proto.CompactTextString(m) }
 func (*RespPutD) ProtoMessage()    {}
 func (*RespPutD) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ae6ce81ad544face, []int{12}
+	return fileDescriptor_ae6ce81ad544face, []int{17}
 }
 
 func (m *RespPutD) XXX_Unmarshal(b []byte) error {
@@ -739,7 +1047,9 @@ type ReqSetD struct {
 	// Key 数据库名称
 	Key string `protobuf:"bytes,1,opt,name=Key,proto3" json:"Key,omitempty"`
 	// Value 插入数据对象
-	Value                []byte   `protobuf:"bytes,2,opt,name=Value,proto3" json:"Value,omitempty"`
+	Value []byte `protobuf:"bytes,2,opt,name=Value,proto3" json:"Value,omitempty"`
+	// TTL 存活时间 单位：毫秒，0表示永不过期
+	TTL                  int64    `protobuf:"varint,3,opt,name=TTL,proto3" json:"TTL,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -749,7 +1059,7 @@ func (m *ReqSetD) Reset()         { *m = ReqSetD{} }
 func (m *ReqSetD) String() string { return proto.CompactTextString(m) }
 func (*ReqSetD) ProtoMessage()    {}
 func (*ReqSetD) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ae6ce81ad544face, []int{13}
+	return fileDescriptor_ae6ce81ad544face, []int{18}
 }
 
 func (m *ReqSetD) XXX_Unmarshal(b []byte) error {
@@ -784,6 +1094,13 @@ func (m *ReqSetD) GetValue() []byte {
 	return nil
 }
 
+func (m *ReqSetD) GetTTL() int64 {
+	if m != nil {
+		return m.TTL
+	}
+	return 0
+}
+
 // RespSetD 响应新增数据
 type RespSetD struct {
 	// Code 响应结果码
@@ -801,7 +1118,7 @@ func (m *RespSetD) Reset()         { *m = RespSetD{} }
 func (m *RespSetD) String() string { return proto.CompactTextString(m) }
 func (*RespSetD) ProtoMessage()    {}
 func (*RespSetD) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ae6ce81ad544face, []int{14}
+	return fileDescriptor_ae6ce81ad544face, []int{19}
 }
 
 func (m *RespSetD) XXX_Unmarshal(b []byte) error {
@@ -856,7 +1173,7 @@ func (m *ReqGetD) Reset()         { *m = ReqGetD{} }
 func (m *ReqGetD) String() string { return proto.CompactTextString(m) }
 func (*ReqGetD) ProtoMessage()    {}
 func (*ReqGetD) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ae6ce81ad544face, []int{15}
+	return fileDescriptor_ae6ce81ad544face, []int{20}
 }
 
 func (m *ReqGetD) XXX_Unmarshal(b []byte) error {
@@ -901,7 +1218,7 @@ func (m *RespGetD) Reset()         { *m = RespGetD{} }
 func (m *RespGetD) String() string { return proto.CompactTextString(m) }
 func (*RespGetD) ProtoMessage()    {}
 func (*RespGetD) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ae6ce81ad544face, []int{16}
+	return fileDescriptor_ae6ce81ad544face, []int{21}
 }
 
 func (m *RespGetD) XXX_Unmarshal(b []byte) error {
@@ -952,7 +1269,9 @@ type ReqPut struct {
 	// Key 数据库名称
 	Key string `protobuf:"bytes,3,opt,name=Key,proto3" json:"Key,omitempty"`
 	// Value 插入数据对象
-	Value                []byte   `protobuf:"bytes,4,opt,name=Value,proto3" json:"Value,omitempty"`
+	Value []byte `protobuf:"bytes,4,opt,name=Value,proto3" json:"Value,omitempty"`
+	// TTL 存活时间 单位：毫秒，0表示永不过期
+	TTL                  int64    `protobuf:"varint,5,opt,name=TTL,proto3" json:"TTL,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -962,7 +1281,7 @@ func (m *ReqPut) Reset()         { *m = ReqPut{} }
 func (m *ReqPut) String() string { return proto.CompactTextString(m) }
 func (*ReqPut) ProtoMessage()    {}
 func (*ReqPut) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ae6ce81ad544face, []int{17}
+	return fileDescriptor_ae6ce81ad544face, []int{22}
 }
 
 func (m *ReqPut) XXX_Unmarshal(b []byte) error {
@@ -1011,6 +1330,13 @@ func (m *ReqPut) GetValue() []byte {
 	return nil
 }
 
+func (m *ReqPut) GetTTL() int64 {
+	if m != nil {
+		return m.TTL
+	}
+	return 0
+}
+
 // RespPut 响应新增数据
 type RespPut struct {
 	// Code 响应结果码
@@ -1028,7 +1354,7 @@ func (m *RespPut) Reset()         { *m = RespPut{} }
 func (m *RespPut) String() string { return proto.CompactTextString(m) }
 func (*RespPut) ProtoMessage()    {}
 func (*RespPut) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ae6ce81ad544face, []int{18}
+	return fileDescriptor_ae6ce81ad544face, []int{23}
 }
 
 func (m *RespPut) XXX_Unmarshal(b []byte) error {
@@ -1079,7 +1405,9 @@ type ReqSet struct {
 	// Key 数据库名称
 	Key string `protobuf:"bytes,3,opt,name=Key,proto3" json:"Key,omitempty"`
 	// Value 插入数据对象
-	Value                []byte   `protobuf:"bytes,4,opt,name=Value,proto3" json:"Value,omitempty"`
+	Value []byte `protobuf:"bytes,4,opt,name=Value,proto3" json:"Value,omitempty"`
+	// TTL 存活时间 单位：毫秒，0表示永不过期
+	TTL                  int64    `protobuf:"varint,5,opt,name=TTL,proto3" json:"TTL,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1089,7 +1417,7 @@ func (m *ReqSet) Reset()         { *m = ReqSet{} }
 func (m *ReqSet) String() string { return proto.CompactTextString(m) }
 func (*ReqSet) ProtoMessage()    {}
 func (*ReqSet) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ae6ce81ad544face, []int{19}
+	return fileDescriptor_ae6ce81ad544face, []int{24}
 }
 
 func (m *ReqSet) XXX_Unmarshal(b []byte) error {
@@ -1138,6 +1466,13 @@ func (m *ReqSet) GetValue() []byte {
 	return nil
 }
 
+func (m *ReqSet) GetTTL() int64 {
+	if m != nil {
+		return m.TTL
+	}
+	return 0
+}
+
 // RespSet 响应新增数据
 type RespSet struct {
 	// Code 响应结果码
@@ -1155,7 +1490,7 @@ func (m *RespSet) Reset()         { *m = RespSet{} }
 func (m *RespSet) String() string { return proto.CompactTextString(m) }
 func (*RespSet) ProtoMessage()    {}
 func (*RespSet) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ae6ce81ad544face, []int{20}
+	return fileDescriptor_ae6ce81ad544face, []int{25}
 }
 
 func (m *RespSet) XXX_Unmarshal(b []byte) error {
@@ -1214,7 +1549,7 @@ func (m *ReqGet) Reset()         { *m = ReqGet{} }
 func (m *ReqGet) String() string { return proto.CompactTextString(m) }
 func (*ReqGet) ProtoMessage()    {}
 func (*ReqGet) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ae6ce81ad544face, []int{21}
+	return fileDescriptor_ae6ce81ad544face, []int{26}
 }
 
 func (m *ReqGet) XXX_Unmarshal(b []byte) error {
@@ -1273,7 +1608,7 @@ func (m *RespGet) Reset()         { *m = RespGet{} }
 func (m *RespGet) String() string { return proto.CompactTextString(m) }
 func (*RespGet) ProtoMessage()    {}
 func (*RespGet) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ae6ce81ad544face, []int{22}
+	return fileDescriptor_ae6ce81ad544face, []int{27}
 }
 
 func (m *RespGet) XXX_Unmarshal(b []byte) error {
@@ -1315,68 +1650,331 @@ func (m *RespGet) GetErrMsg() string {
 	return ""
 }
 
-// ReqSelect 获取数据
-type ReqSelect struct {
+// ReqGetVersion 获取数据指定版本
+type ReqGetVersion struct {
 	// DatabaseName 数据库名称
 	DatabaseName string `protobuf:"bytes,1,opt,name=DatabaseName,proto3" json:"DatabaseName,omitempty"`
 	// FormName 表名称
 	FormName string `protobuf:"bytes,2,opt,name=FormName,proto3" json:"FormName,omitempty"`
-	// selector 条件选择器
-	Selector             *Selector `protobuf:"bytes,3,opt,name=Selector,proto3" json:"Selector,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
-	XXX_unrecognized     []byte    `json:"-"`
-	XXX_sizecache        int32     `json:"-"`
+	// Key 数据key
+	Key string `protobuf:"bytes,3,opt,name=Key,proto3" json:"Key,omitempty"`
+	// Version 版本号
+	Version              uint64   `protobuf:"varint,4,opt,name=Version,proto3" json:"Version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *ReqSelect) Reset()         { *m = ReqSelect{} }
-func (m *ReqSelect) String() string { return proto.CompactTextString(m) }
-func (*ReqSelect) ProtoMessage()    {}
-func (*ReqSelect) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ae6ce81ad544face, []int{23}
+func (m *ReqGetVersion) Reset()         { *m = ReqGetVersion{} }
+func (m *ReqGetVersion) String() string { return proto.CompactTextString(m) }
+func (*ReqGetVersion) ProtoMessage()    {}
+func (*ReqGetVersion) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{28}
 }
 
-func (m *ReqSelect) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ReqSelect.Unmarshal(m, b)
+func (m *ReqGetVersion) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReqGetVersion.Unmarshal(m, b)
 }
-func (m *ReqSelect) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ReqSelect.Marshal(b, m, deterministic)
+func (m *ReqGetVersion) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReqGetVersion.Marshal(b, m, deterministic)
 }
-func (m *ReqSelect) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ReqSelect.Merge(m, src)
+func (m *ReqGetVersion) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReqGetVersion.Merge(m, src)
 }
-func (m *ReqSelect) XXX_Size() int {
-	return xxx_messageInfo_ReqSelect.Size(m)
+func (m *ReqGetVersion) XXX_Size() int {
+	return xxx_messageInfo_ReqGetVersion.Size(m)
 }
-func (m *ReqSelect) XXX_DiscardUnknown() {
-	xxx_messageInfo_ReqSelect.DiscardUnknown(m)
+func (m *ReqGetVersion) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReqGetVersion.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ReqSelect proto.InternalMessageInfo
+var xxx_messageInfo_ReqGetVersion proto.InternalMessageInfo
 
-func (m *ReqSelect) GetDatabaseName() string {
+func (m *ReqGetVersion) GetDatabaseName() string {
 	if m != nil {
 		return m.DatabaseName
 	}
 	return ""
 }
 
-func (m *ReqSelect) GetFormName() string {
+func (m *ReqGetVersion) GetFormName() string {
 	if m != nil {
 		return m.FormName
 	}
 	return ""
 }
 
-func (m *ReqSelect) GetSelector() *Selector {
+func (m *ReqGetVersion) GetKey() string {
 	if m != nil {
-		return m.Selector
+		return m.Key
 	}
-	return nil
+	return ""
 }
 
-// RespSelect 响应获取数据
-type RespSelect struct {
-	// Code 响应结果码
+func (m *ReqGetVersion) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+// ReqHistory 获取数据历史版本
+type ReqHistory struct {
+	// DatabaseName 数据库名称
+	DatabaseName string `protobuf:"bytes,1,opt,name=DatabaseName,proto3" json:"DatabaseName,omitempty"`
+	// FormName 表名称
+	FormName string `protobuf:"bytes,2,opt,name=FormName,proto3" json:"FormName,omitempty"`
+	// Key 数据key
+	Key                  string   `protobuf:"bytes,3,opt,name=Key,proto3" json:"Key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReqHistory) Reset()         { *m = ReqHistory{} }
+func (m *ReqHistory) String() string { return proto.CompactTextString(m) }
+func (*ReqHistory) ProtoMessage()    {}
+func (*ReqHistory) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{29}
+}
+
+func (m *ReqHistory) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReqHistory.Unmarshal(m, b)
+}
+func (m *ReqHistory) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReqHistory.Marshal(b, m, deterministic)
+}
+func (m *ReqHistory) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReqHistory.Merge(m, src)
+}
+func (m *ReqHistory) XXX_Size() int {
+	return xxx_messageInfo_ReqHistory.Size(m)
+}
+func (m *ReqHistory) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReqHistory.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReqHistory proto.InternalMessageInfo
+
+func (m *ReqHistory) GetDatabaseName() string {
+	if m != nil {
+		return m.DatabaseName
+	}
+	return ""
+}
+
+func (m *ReqHistory) GetFormName() string {
+	if m != nil {
+		return m.FormName
+	}
+	return ""
+}
+
+func (m *ReqHistory) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+// RespHistory 响应获取数据历史版本
+type RespHistory struct {
+	// Code 响应结果码
+	Code Code `protobuf:"varint,1,opt,name=Code,proto3,enum=api.Code" json:"Code,omitempty"`
+	// Versions 保留的版本集合，由新至旧
+	Versions []*Version `protobuf:"bytes,2,rep,name=Versions,proto3" json:"Versions,omitempty"`
+	// ErrMsg 错误信息
+	ErrMsg               string   `protobuf:"bytes,3,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RespHistory) Reset()         { *m = RespHistory{} }
+func (m *RespHistory) String() string { return proto.CompactTextString(m) }
+func (*RespHistory) ProtoMessage()    {}
+func (*RespHistory) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{30}
+}
+
+func (m *RespHistory) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RespHistory.Unmarshal(m, b)
+}
+func (m *RespHistory) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RespHistory.Marshal(b, m, deterministic)
+}
+func (m *RespHistory) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RespHistory.Merge(m, src)
+}
+func (m *RespHistory) XXX_Size() int {
+	return xxx_messageInfo_RespHistory.Size(m)
+}
+func (m *RespHistory) XXX_DiscardUnknown() {
+	xxx_messageInfo_RespHistory.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RespHistory proto.InternalMessageInfo
+
+func (m *RespHistory) GetCode() Code {
+	if m != nil {
+		return m.Code
+	}
+	return Code_Success
+}
+
+func (m *RespHistory) GetVersions() []*Version {
+	if m != nil {
+		return m.Versions
+	}
+	return nil
+}
+
+func (m *RespHistory) GetErrMsg() string {
+	if m != nil {
+		return m.ErrMsg
+	}
+	return ""
+}
+
+// Version 数据的一个版本
+type Version struct {
+	// Version 版本号
+	Version uint64 `protobuf:"varint,1,opt,name=Version,proto3" json:"Version,omitempty"`
+	// Value 该版本存储数据
+	Value []byte `protobuf:"bytes,2,opt,name=Value,proto3" json:"Value,omitempty"`
+	// Deleted 该版本是否为删除操作
+	Deleted bool `protobuf:"varint,3,opt,name=Deleted,proto3" json:"Deleted,omitempty"`
+	// Time 写入时间，unix纳秒
+	Time int64 `protobuf:"varint,4,opt,name=Time,proto3" json:"Time,omitempty"`
+	// Expire 过期时间，unix纳秒，0表示永不过期
+	Expire               int64    `protobuf:"varint,5,opt,name=Expire,proto3" json:"Expire,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Version) Reset()         { *m = Version{} }
+func (m *Version) String() string { return proto.CompactTextString(m) }
+func (*Version) ProtoMessage()    {}
+func (*Version) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{31}
+}
+
+func (m *Version) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Version.Unmarshal(m, b)
+}
+func (m *Version) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Version.Marshal(b, m, deterministic)
+}
+func (m *Version) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Version.Merge(m, src)
+}
+func (m *Version) XXX_Size() int {
+	return xxx_messageInfo_Version.Size(m)
+}
+func (m *Version) XXX_DiscardUnknown() {
+	xxx_messageInfo_Version.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Version proto.InternalMessageInfo
+
+func (m *Version) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *Version) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *Version) GetDeleted() bool {
+	if m != nil {
+		return m.Deleted
+	}
+	return false
+}
+
+func (m *Version) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *Version) GetExpire() int64 {
+	if m != nil {
+		return m.Expire
+	}
+	return 0
+}
+
+// ReqSelect 获取数据
+type ReqSelect struct {
+	// DatabaseName 数据库名称
+	DatabaseName string `protobuf:"bytes,1,opt,name=DatabaseName,proto3" json:"DatabaseName,omitempty"`
+	// FormName 表名称
+	FormName string `protobuf:"bytes,2,opt,name=FormName,proto3" json:"FormName,omitempty"`
+	// selector 条件选择器
+	Selector             *Selector `protobuf:"bytes,3,opt,name=Selector,proto3" json:"Selector,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ReqSelect) Reset()         { *m = ReqSelect{} }
+func (m *ReqSelect) String() string { return proto.CompactTextString(m) }
+func (*ReqSelect) ProtoMessage()    {}
+func (*ReqSelect) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{32}
+}
+
+func (m *ReqSelect) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReqSelect.Unmarshal(m, b)
+}
+func (m *ReqSelect) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReqSelect.Marshal(b, m, deterministic)
+}
+func (m *ReqSelect) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReqSelect.Merge(m, src)
+}
+func (m *ReqSelect) XXX_Size() int {
+	return xxx_messageInfo_ReqSelect.Size(m)
+}
+func (m *ReqSelect) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReqSelect.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReqSelect proto.InternalMessageInfo
+
+func (m *ReqSelect) GetDatabaseName() string {
+	if m != nil {
+		return m.DatabaseName
+	}
+	return ""
+}
+
+func (m *ReqSelect) GetFormName() string {
+	if m != nil {
+		return m.FormName
+	}
+	return ""
+}
+
+func (m *ReqSelect) GetSelector() *Selector {
+	if m != nil {
+		return m.Selector
+	}
+	return nil
+}
+
+// RespSelect 响应获取数据
+type RespSelect struct {
+	// Code 响应结果码
 	Code Code `protobuf:"varint,1,opt,name=Code,proto3,enum=api.Code" json:"Code,omitempty"`
 	// Count 获取数据总条数
 	Count int32 `protobuf:"varint,2,opt,name=Count,proto3" json:"Count,omitempty"`
@@ -1393,7 +1991,7 @@ func (m *RespSelect) Reset()         { *m = RespSelect{} }
 func (m *RespSelect) String() string { return proto.CompactTextString(m) }
 func (*RespSelect) ProtoMessage()    {}
 func (*RespSelect) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ae6ce81ad544face, []int{24}
+	return fileDescriptor_ae6ce81ad544face, []int{33}
 }
 
 func (m *RespSelect) XXX_Unmarshal(b []byte) error {
@@ -1459,7 +2057,7 @@ func (m *ReqRemove) Reset()         { *m = ReqRemove{} }
 func (m *ReqRemove) String() string { return proto.CompactTextString(m) }
 func (*ReqRemove) ProtoMessage()    {}
 func (*ReqRemove) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ae6ce81ad544face, []int{25}
+	return fileDescriptor_ae6ce81ad544face, []int{34}
 }
 
 func (m *ReqRemove) XXX_Unmarshal(b []byte) error {
@@ -1518,7 +2116,7 @@ func (m *ReqDelete) Reset()         { *m = ReqDelete{} }
 func (m *ReqDelete) String() string { return proto.CompactTextString(m) }
 func (*ReqDelete) ProtoMessage()    {}
 func (*ReqDelete) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ae6ce81ad544face, []int{26}
+	return fileDescriptor_ae6ce81ad544face, []int{35}
 }
 
 func (m *ReqDelete) XXX_Unmarshal(b []byte) error {
@@ -1577,7 +2175,7 @@ func (m *RespDelete) Reset()         { *m = RespDelete{} }
 func (m *RespDelete) String() string { return proto.CompactTextString(m) }
 func (*RespDelete) ProtoMessage()    {}
 func (*RespDelete) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ae6ce81ad544face, []int{27}
+	return fileDescriptor_ae6ce81ad544face, []int{36}
 }
 
 func (m *RespDelete) XXX_Unmarshal(b []byte) error {
@@ -1619,22 +2217,1559 @@ func (m *RespDelete) GetErrMsg() string {
 	return ""
 }
 
-// Resp 通用响应对象
-type Resp struct {
+// ReqCompact 压缩表数据文件
+type ReqCompact struct {
+	// DatabaseName 数据库名称
+	DatabaseName string `protobuf:"bytes,1,opt,name=DatabaseName,proto3" json:"DatabaseName,omitempty"`
+	// FormName 表名称
+	FormName string `protobuf:"bytes,2,opt,name=FormName,proto3" json:"FormName,omitempty"`
+	// Background 是否后台执行，后台执行时立即返回
+	Background           bool     `protobuf:"varint,3,opt,name=Background,proto3" json:"Background,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReqCompact) Reset()         { *m = ReqCompact{} }
+func (m *ReqCompact) String() string { return proto.CompactTextString(m) }
+func (*ReqCompact) ProtoMessage()    {}
+func (*ReqCompact) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{37}
+}
+
+func (m *ReqCompact) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReqCompact.Unmarshal(m, b)
+}
+func (m *ReqCompact) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReqCompact.Marshal(b, m, deterministic)
+}
+func (m *ReqCompact) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReqCompact.Merge(m, src)
+}
+func (m *ReqCompact) XXX_Size() int {
+	return xxx_messageInfo_ReqCompact.Size(m)
+}
+func (m *ReqCompact) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReqCompact.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReqCompact proto.InternalMessageInfo
+
+func (m *ReqCompact) GetDatabaseName() string {
+	if m != nil {
+		return m.DatabaseName
+	}
+	return ""
+}
+
+func (m *ReqCompact) GetFormName() string {
+	if m != nil {
+		return m.FormName
+	}
+	return ""
+}
+
+func (m *ReqCompact) GetBackground() bool {
+	if m != nil {
+		return m.Background
+	}
+	return false
+}
+
+// RespCompact 响应压缩表数据文件
+type RespCompact struct {
 	// Code 响应结果码
 	Code Code `protobuf:"varint,1,opt,name=Code,proto3,enum=api.Code" json:"Code,omitempty"`
+	// Records 压缩后保留的有效记录数
+	Records int64 `protobuf:"varint,2,opt,name=Records,proto3" json:"Records,omitempty"`
+	// SizeBefore 压缩前数据文件大小
+	SizeBefore int64 `protobuf:"varint,3,opt,name=SizeBefore,proto3" json:"SizeBefore,omitempty"`
+	// SizeAfter 压缩后数据文件大小
+	SizeAfter int64 `protobuf:"varint,4,opt,name=SizeAfter,proto3" json:"SizeAfter,omitempty"`
 	// ErrMsg 错误信息
-	ErrMsg               string   `protobuf:"bytes,2,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+	ErrMsg               string   `protobuf:"bytes,5,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Resp) Reset()         { *m = Resp{} }
-func (m *Resp) String() string { return proto.CompactTextString(m) }
-func (*Resp) ProtoMessage()    {}
-func (*Resp) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ae6ce81ad544face, []int{28}
+func (m *RespCompact) Reset()         { *m = RespCompact{} }
+func (m *RespCompact) String() string { return proto.CompactTextString(m) }
+func (*RespCompact) ProtoMessage()    {}
+func (*RespCompact) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{38}
+}
+
+func (m *RespCompact) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RespCompact.Unmarshal(m, b)
+}
+func (m *RespCompact) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RespCompact.Marshal(b, m, deterministic)
+}
+func (m *RespCompact) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RespCompact.Merge(m, src)
+}
+func (m *RespCompact) XXX_Size() int {
+	return xxx_messageInfo_RespCompact.Size(m)
+}
+func (m *RespCompact) XXX_DiscardUnknown() {
+	xxx_messageInfo_RespCompact.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RespCompact proto.InternalMessageInfo
+
+func (m *RespCompact) GetCode() Code {
+	if m != nil {
+		return m.Code
+	}
+	return Code_Success
+}
+
+func (m *RespCompact) GetRecords() int64 {
+	if m != nil {
+		return m.Records
+	}
+	return 0
+}
+
+func (m *RespCompact) GetSizeBefore() int64 {
+	if m != nil {
+		return m.SizeBefore
+	}
+	return 0
+}
+
+func (m *RespCompact) GetSizeAfter() int64 {
+	if m != nil {
+		return m.SizeAfter
+	}
+	return 0
+}
+
+func (m *RespCompact) GetErrMsg() string {
+	if m != nil {
+		return m.ErrMsg
+	}
+	return ""
+}
+
+// ReqRebuildIndex 重建索引
+type ReqRebuildIndex struct {
+	// DatabaseName 数据库名称
+	DatabaseName string `protobuf:"bytes,1,opt,name=DatabaseName,proto3" json:"DatabaseName,omitempty"`
+	// FormName 表名称
+	FormName string `protobuf:"bytes,2,opt,name=FormName,proto3" json:"FormName,omitempty"`
+	// KeyStructure 索引结构名
+	KeyStructure         string   `protobuf:"bytes,3,opt,name=KeyStructure,proto3" json:"KeyStructure,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReqRebuildIndex) Reset()         { *m = ReqRebuildIndex{} }
+func (m *ReqRebuildIndex) String() string { return proto.CompactTextString(m) }
+func (*ReqRebuildIndex) ProtoMessage()    {}
+func (*ReqRebuildIndex) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{39}
+}
+
+func (m *ReqRebuildIndex) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReqRebuildIndex.Unmarshal(m, b)
+}
+func (m *ReqRebuildIndex) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReqRebuildIndex.Marshal(b, m, deterministic)
+}
+func (m *ReqRebuildIndex) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReqRebuildIndex.Merge(m, src)
+}
+func (m *ReqRebuildIndex) XXX_Size() int {
+	return xxx_messageInfo_ReqRebuildIndex.Size(m)
+}
+func (m *ReqRebuildIndex) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReqRebuildIndex.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReqRebuildIndex proto.InternalMessageInfo
+
+func (m *ReqRebuildIndex) GetDatabaseName() string {
+	if m != nil {
+		return m.DatabaseName
+	}
+	return ""
+}
+
+func (m *ReqRebuildIndex) GetFormName() string {
+	if m != nil {
+		return m.FormName
+	}
+	return ""
+}
+
+func (m *ReqRebuildIndex) GetKeyStructure() string {
+	if m != nil {
+		return m.KeyStructure
+	}
+	return ""
+}
+
+// RespRebuildIndex 响应重建索引
+type RespRebuildIndex struct {
+	// Code 响应结果码
+	Code Code `protobuf:"varint,1,opt,name=Code,proto3,enum=api.Code" json:"Code,omitempty"`
+	// Count 重建后的索引记录数
+	Count int64 `protobuf:"varint,2,opt,name=Count,proto3" json:"Count,omitempty"`
+	// ErrMsg 错误信息
+	ErrMsg               string   `protobuf:"bytes,3,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RespRebuildIndex) Reset()         { *m = RespRebuildIndex{} }
+func (m *RespRebuildIndex) String() string { return proto.CompactTextString(m) }
+func (*RespRebuildIndex) ProtoMessage()    {}
+func (*RespRebuildIndex) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{40}
+}
+
+func (m *RespRebuildIndex) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RespRebuildIndex.Unmarshal(m, b)
+}
+func (m *RespRebuildIndex) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RespRebuildIndex.Marshal(b, m, deterministic)
+}
+func (m *RespRebuildIndex) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RespRebuildIndex.Merge(m, src)
+}
+func (m *RespRebuildIndex) XXX_Size() int {
+	return xxx_messageInfo_RespRebuildIndex.Size(m)
+}
+func (m *RespRebuildIndex) XXX_DiscardUnknown() {
+	xxx_messageInfo_RespRebuildIndex.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RespRebuildIndex proto.InternalMessageInfo
+
+func (m *RespRebuildIndex) GetCode() Code {
+	if m != nil {
+		return m.Code
+	}
+	return Code_Success
+}
+
+func (m *RespRebuildIndex) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *RespRebuildIndex) GetErrMsg() string {
+	if m != nil {
+		return m.ErrMsg
+	}
+	return ""
+}
+
+// ReqIndexStatus 请求索引构建状态
+type ReqIndexStatus struct {
+	// DatabaseName 数据库名称
+	DatabaseName string `protobuf:"bytes,1,opt,name=DatabaseName,proto3" json:"DatabaseName,omitempty"`
+	// FormName 表名称
+	FormName string `protobuf:"bytes,2,opt,name=FormName,proto3" json:"FormName,omitempty"`
+	// KeyStructure 索引结构名
+	KeyStructure         string   `protobuf:"bytes,3,opt,name=KeyStructure,proto3" json:"KeyStructure,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReqIndexStatus) Reset()         { *m = ReqIndexStatus{} }
+func (m *ReqIndexStatus) String() string { return proto.CompactTextString(m) }
+func (*ReqIndexStatus) ProtoMessage()    {}
+func (*ReqIndexStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{41}
+}
+
+func (m *ReqIndexStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReqIndexStatus.Unmarshal(m, b)
+}
+func (m *ReqIndexStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReqIndexStatus.Marshal(b, m, deterministic)
+}
+func (m *ReqIndexStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReqIndexStatus.Merge(m, src)
+}
+func (m *ReqIndexStatus) XXX_Size() int {
+	return xxx_messageInfo_ReqIndexStatus.Size(m)
+}
+func (m *ReqIndexStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReqIndexStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReqIndexStatus proto.InternalMessageInfo
+
+func (m *ReqIndexStatus) GetDatabaseName() string {
+	if m != nil {
+		return m.DatabaseName
+	}
+	return ""
+}
+
+func (m *ReqIndexStatus) GetFormName() string {
+	if m != nil {
+		return m.FormName
+	}
+	return ""
+}
+
+func (m *ReqIndexStatus) GetKeyStructure() string {
+	if m != nil {
+		return m.KeyStructure
+	}
+	return ""
+}
+
+// RespIndexStatus 响应索引构建状态
+type RespIndexStatus struct {
+	// Code 响应结果码
+	Code Code `protobuf:"varint,1,opt,name=Code,proto3,enum=api.Code" json:"Code,omitempty"`
+	// State 构建状态，building、ready 或 failed
+	State string `protobuf:"bytes,2,opt,name=State,proto3" json:"State,omitempty"`
+	// Total 待回填的记录数
+	Total int64 `protobuf:"varint,3,opt,name=Total,proto3" json:"Total,omitempty"`
+	// Done 已回填的记录数
+	Done int64 `protobuf:"varint,4,opt,name=Done,proto3" json:"Done,omitempty"`
+	// ErrMsg 错误信息，构建失败时为失败原因
+	ErrMsg               string   `protobuf:"bytes,5,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RespIndexStatus) Reset()         { *m = RespIndexStatus{} }
+func (m *RespIndexStatus) String() string { return proto.CompactTextString(m) }
+func (*RespIndexStatus) ProtoMessage()    {}
+func (*RespIndexStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{42}
+}
+
+func (m *RespIndexStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RespIndexStatus.Unmarshal(m, b)
+}
+func (m *RespIndexStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RespIndexStatus.Marshal(b, m, deterministic)
+}
+func (m *RespIndexStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RespIndexStatus.Merge(m, src)
+}
+func (m *RespIndexStatus) XXX_Size() int {
+	return xxx_messageInfo_RespIndexStatus.Size(m)
+}
+func (m *RespIndexStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_RespIndexStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RespIndexStatus proto.InternalMessageInfo
+
+func (m *RespIndexStatus) GetCode() Code {
+	if m != nil {
+		return m.Code
+	}
+	return Code_Success
+}
+
+func (m *RespIndexStatus) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *RespIndexStatus) GetTotal() int64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+func (m *RespIndexStatus) GetDone() int64 {
+	if m != nil {
+		return m.Done
+	}
+	return 0
+}
+
+func (m *RespIndexStatus) GetErrMsg() string {
+	if m != nil {
+		return m.ErrMsg
+	}
+	return ""
+}
+
+// ReqFormStats 请求表统计信息
+type ReqFormStats struct {
+	// DatabaseName 数据库名称
+	DatabaseName string `protobuf:"bytes,1,opt,name=DatabaseName,proto3" json:"DatabaseName,omitempty"`
+	// FormName 表名称
+	FormName             string   `protobuf:"bytes,2,opt,name=FormName,proto3" json:"FormName,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReqFormStats) Reset()         { *m = ReqFormStats{} }
+func (m *ReqFormStats) String() string { return proto.CompactTextString(m) }
+func (*ReqFormStats) ProtoMessage()    {}
+func (*ReqFormStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{43}
+}
+
+func (m *ReqFormStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReqFormStats.Unmarshal(m, b)
+}
+func (m *ReqFormStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReqFormStats.Marshal(b, m, deterministic)
+}
+func (m *ReqFormStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReqFormStats.Merge(m, src)
+}
+func (m *ReqFormStats) XXX_Size() int {
+	return xxx_messageInfo_ReqFormStats.Size(m)
+}
+func (m *ReqFormStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReqFormStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReqFormStats proto.InternalMessageInfo
+
+func (m *ReqFormStats) GetDatabaseName() string {
+	if m != nil {
+		return m.DatabaseName
+	}
+	return ""
+}
+
+func (m *ReqFormStats) GetFormName() string {
+	if m != nil {
+		return m.FormName
+	}
+	return ""
+}
+
+// RespFormStats 响应表统计信息
+type RespFormStats struct {
+	// Code 响应结果码
+	Code Code `protobuf:"varint,1,opt,name=Code,proto3,enum=api.Code" json:"Code,omitempty"`
+	// Compression 表数据压缩方式
+	Compression Compression `protobuf:"varint,2,opt,name=Compression,proto3,enum=api.Compression" json:"Compression,omitempty"`
+	// Segments 数据分段文件数
+	Segments int64 `protobuf:"varint,3,opt,name=Segments,proto3" json:"Segments,omitempty"`
+	// Records 数据文件中的记录数，含已失效记录
+	Records int64 `protobuf:"varint,4,opt,name=Records,proto3" json:"Records,omitempty"`
+	// DataSize 数据文件落盘总大小
+	DataSize int64 `protobuf:"varint,5,opt,name=DataSize,proto3" json:"DataSize,omitempty"`
+	// RawSize 数据记录压缩前总大小
+	RawSize int64 `protobuf:"varint,6,opt,name=RawSize,proto3" json:"RawSize,omitempty"`
+	// Ratio 压缩率，DataSize/RawSize
+	Ratio float64 `protobuf:"fixed64,7,opt,name=Ratio,proto3" json:"Ratio,omitempty"`
+	// ErrMsg 错误信息
+	ErrMsg               string   `protobuf:"bytes,8,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RespFormStats) Reset()         { *m = RespFormStats{} }
+func (m *RespFormStats) String() string { return proto.CompactTextString(m) }
+func (*RespFormStats) ProtoMessage()    {}
+func (*RespFormStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{44}
+}
+
+func (m *RespFormStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RespFormStats.Unmarshal(m, b)
+}
+func (m *RespFormStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RespFormStats.Marshal(b, m, deterministic)
+}
+func (m *RespFormStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RespFormStats.Merge(m, src)
+}
+func (m *RespFormStats) XXX_Size() int {
+	return xxx_messageInfo_RespFormStats.Size(m)
+}
+func (m *RespFormStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_RespFormStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RespFormStats proto.InternalMessageInfo
+
+func (m *RespFormStats) GetCode() Code {
+	if m != nil {
+		return m.Code
+	}
+	return Code_Success
+}
+
+func (m *RespFormStats) GetCompression() Compression {
+	if m != nil {
+		return m.Compression
+	}
+	return Compression_None
+}
+
+func (m *RespFormStats) GetSegments() int64 {
+	if m != nil {
+		return m.Segments
+	}
+	return 0
+}
+
+func (m *RespFormStats) GetRecords() int64 {
+	if m != nil {
+		return m.Records
+	}
+	return 0
+}
+
+func (m *RespFormStats) GetDataSize() int64 {
+	if m != nil {
+		return m.DataSize
+	}
+	return 0
+}
+
+func (m *RespFormStats) GetRawSize() int64 {
+	if m != nil {
+		return m.RawSize
+	}
+	return 0
+}
+
+func (m *RespFormStats) GetRatio() float64 {
+	if m != nil {
+		return m.Ratio
+	}
+	return 0
+}
+
+func (m *RespFormStats) GetErrMsg() string {
+	if m != nil {
+		return m.ErrMsg
+	}
+	return ""
+}
+
+// ReqRotateKey 请求轮换数据密钥
+type ReqRotateKey struct {
+	// DatabaseName 数据库名称
+	DatabaseName string `protobuf:"bytes,1,opt,name=DatabaseName,proto3" json:"DatabaseName,omitempty"`
+	// Background 是否后台执行，后台执行时立即返回
+	Background           bool     `protobuf:"varint,2,opt,name=Background,proto3" json:"Background,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReqRotateKey) Reset()         { *m = ReqRotateKey{} }
+func (m *ReqRotateKey) String() string { return proto.CompactTextString(m) }
+func (*ReqRotateKey) ProtoMessage()    {}
+func (*ReqRotateKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{45}
+}
+
+func (m *ReqRotateKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReqRotateKey.Unmarshal(m, b)
+}
+func (m *ReqRotateKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReqRotateKey.Marshal(b, m, deterministic)
+}
+func (m *ReqRotateKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReqRotateKey.Merge(m, src)
+}
+func (m *ReqRotateKey) XXX_Size() int {
+	return xxx_messageInfo_ReqRotateKey.Size(m)
+}
+func (m *ReqRotateKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReqRotateKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReqRotateKey proto.InternalMessageInfo
+
+func (m *ReqRotateKey) GetDatabaseName() string {
+	if m != nil {
+		return m.DatabaseName
+	}
+	return ""
+}
+
+func (m *ReqRotateKey) GetBackground() bool {
+	if m != nil {
+		return m.Background
+	}
+	return false
+}
+
+// RespRotateKey 响应轮换数据密钥
+type RespRotateKey struct {
+	// Code 响应结果码
+	Code Code `protobuf:"varint,1,opt,name=Code,proto3,enum=api.Code" json:"Code,omitempty"`
+	// KeyVersion 轮换后的数据密钥版本
+	KeyVersion uint32 `protobuf:"varint,2,opt,name=KeyVersion,proto3" json:"KeyVersion,omitempty"`
+	// Records 重新加密的记录数
+	Records int64 `protobuf:"varint,3,opt,name=Records,proto3" json:"Records,omitempty"`
+	// ErrMsg 错误信息
+	ErrMsg               string   `protobuf:"bytes,4,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RespRotateKey) Reset()         { *m = RespRotateKey{} }
+func (m *RespRotateKey) String() string { return proto.CompactTextString(m) }
+func (*RespRotateKey) ProtoMessage()    {}
+func (*RespRotateKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{46}
+}
+
+func (m *RespRotateKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RespRotateKey.Unmarshal(m, b)
+}
+func (m *RespRotateKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RespRotateKey.Marshal(b, m, deterministic)
+}
+func (m *RespRotateKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RespRotateKey.Merge(m, src)
+}
+func (m *RespRotateKey) XXX_Size() int {
+	return xxx_messageInfo_RespRotateKey.Size(m)
+}
+func (m *RespRotateKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_RespRotateKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RespRotateKey proto.InternalMessageInfo
+
+func (m *RespRotateKey) GetCode() Code {
+	if m != nil {
+		return m.Code
+	}
+	return Code_Success
+}
+
+func (m *RespRotateKey) GetKeyVersion() uint32 {
+	if m != nil {
+		return m.KeyVersion
+	}
+	return 0
+}
+
+func (m *RespRotateKey) GetRecords() int64 {
+	if m != nil {
+		return m.Records
+	}
+	return 0
+}
+
+func (m *RespRotateKey) GetErrMsg() string {
+	if m != nil {
+		return m.ErrMsg
+	}
+	return ""
+}
+
+// ReqSetSyncMode 请求设置数据库落盘策略
+type ReqSetSyncMode struct {
+	// DatabaseName 数据库名称
+	DatabaseName string `protobuf:"bytes,1,opt,name=DatabaseName,proto3" json:"DatabaseName,omitempty"`
+	// SyncMode 落盘策略(none/interval/always)，为空时使用配置文件中的 SyncMode
+	SyncMode             string   `protobuf:"bytes,2,opt,name=SyncMode,proto3" json:"SyncMode,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReqSetSyncMode) Reset()         { *m = ReqSetSyncMode{} }
+func (m *ReqSetSyncMode) String() string { return proto.CompactTextString(m) }
+func (*ReqSetSyncMode) ProtoMessage()    {}
+func (*ReqSetSyncMode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{47}
+}
+
+func (m *ReqSetSyncMode) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReqSetSyncMode.Unmarshal(m, b)
+}
+func (m *ReqSetSyncMode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReqSetSyncMode.Marshal(b, m, deterministic)
+}
+func (m *ReqSetSyncMode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReqSetSyncMode.Merge(m, src)
+}
+func (m *ReqSetSyncMode) XXX_Size() int {
+	return xxx_messageInfo_ReqSetSyncMode.Size(m)
+}
+func (m *ReqSetSyncMode) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReqSetSyncMode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReqSetSyncMode proto.InternalMessageInfo
+
+func (m *ReqSetSyncMode) GetDatabaseName() string {
+	if m != nil {
+		return m.DatabaseName
+	}
+	return ""
+}
+
+func (m *ReqSetSyncMode) GetSyncMode() string {
+	if m != nil {
+		return m.SyncMode
+	}
+	return ""
+}
+
+// ReqBackup 请求备份库
+type ReqBackup struct {
+	// DatabaseName 数据库名称，为空时备份全部库
+	DatabaseName string `protobuf:"bytes,1,opt,name=DatabaseName,proto3" json:"DatabaseName,omitempty"`
+	// Path 服务端归档文件路径
+	Path                 string   `protobuf:"bytes,2,opt,name=Path,proto3" json:"Path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReqBackup) Reset()         { *m = ReqBackup{} }
+func (m *ReqBackup) String() string { return proto.CompactTextString(m) }
+func (*ReqBackup) ProtoMessage()    {}
+func (*ReqBackup) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{48}
+}
+
+func (m *ReqBackup) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReqBackup.Unmarshal(m, b)
+}
+func (m *ReqBackup) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReqBackup.Marshal(b, m, deterministic)
+}
+func (m *ReqBackup) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReqBackup.Merge(m, src)
+}
+func (m *ReqBackup) XXX_Size() int {
+	return xxx_messageInfo_ReqBackup.Size(m)
+}
+func (m *ReqBackup) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReqBackup.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReqBackup proto.InternalMessageInfo
+
+func (m *ReqBackup) GetDatabaseName() string {
+	if m != nil {
+		return m.DatabaseName
+	}
+	return ""
+}
+
+func (m *ReqBackup) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+// ReqRestore 请求依据归档文件恢复库
+type ReqRestore struct {
+	// Path 服务端归档文件路径
+	Path                 string   `protobuf:"bytes,1,opt,name=Path,proto3" json:"Path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReqRestore) Reset()         { *m = ReqRestore{} }
+func (m *ReqRestore) String() string { return proto.CompactTextString(m) }
+func (*ReqRestore) ProtoMessage()    {}
+func (*ReqRestore) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{49}
+}
+
+func (m *ReqRestore) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReqRestore.Unmarshal(m, b)
+}
+func (m *ReqRestore) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReqRestore.Marshal(b, m, deterministic)
+}
+func (m *ReqRestore) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReqRestore.Merge(m, src)
+}
+func (m *ReqRestore) XXX_Size() int {
+	return xxx_messageInfo_ReqRestore.Size(m)
+}
+func (m *ReqRestore) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReqRestore.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReqRestore proto.InternalMessageInfo
+
+func (m *ReqRestore) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+// RespBackup 响应备份或恢复库
+type RespBackup struct {
+	// Code 响应结果码
+	Code Code `protobuf:"varint,1,opt,name=Code,proto3,enum=api.Code" json:"Code,omitempty"`
+	// Databases 库数
+	Databases int64 `protobuf:"varint,2,opt,name=Databases,proto3" json:"Databases,omitempty"`
+	// Forms 表数
+	Forms int64 `protobuf:"varint,3,opt,name=Forms,proto3" json:"Forms,omitempty"`
+	// Files 数据分段文件及索引文件数
+	Files int64 `protobuf:"varint,4,opt,name=Files,proto3" json:"Files,omitempty"`
+	// Size 数据分段文件及索引文件总大小
+	Size int64 `protobuf:"varint,5,opt,name=Size,proto3" json:"Size,omitempty"`
+	// ErrMsg 错误信息
+	ErrMsg               string   `protobuf:"bytes,6,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RespBackup) Reset()         { *m = RespBackup{} }
+func (m *RespBackup) String() string { return proto.CompactTextString(m) }
+func (*RespBackup) ProtoMessage()    {}
+func (*RespBackup) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{50}
+}
+
+func (m *RespBackup) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RespBackup.Unmarshal(m, b)
+}
+func (m *RespBackup) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RespBackup.Marshal(b, m, deterministic)
+}
+func (m *RespBackup) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RespBackup.Merge(m, src)
+}
+func (m *RespBackup) XXX_Size() int {
+	return xxx_messageInfo_RespBackup.Size(m)
+}
+func (m *RespBackup) XXX_DiscardUnknown() {
+	xxx_messageInfo_RespBackup.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RespBackup proto.InternalMessageInfo
+
+func (m *RespBackup) GetCode() Code {
+	if m != nil {
+		return m.Code
+	}
+	return Code_Success
+}
+
+func (m *RespBackup) GetDatabases() int64 {
+	if m != nil {
+		return m.Databases
+	}
+	return 0
+}
+
+func (m *RespBackup) GetForms() int64 {
+	if m != nil {
+		return m.Forms
+	}
+	return 0
+}
+
+func (m *RespBackup) GetFiles() int64 {
+	if m != nil {
+		return m.Files
+	}
+	return 0
+}
+
+func (m *RespBackup) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *RespBackup) GetErrMsg() string {
+	if m != nil {
+		return m.ErrMsg
+	}
+	return ""
+}
+
+// ReqExport 请求导出表数据
+type ReqExport struct {
+	// DatabaseName 数据库名称
+	DatabaseName string `protobuf:"bytes,1,opt,name=DatabaseName,proto3" json:"DatabaseName,omitempty"`
+	// FormName 表名称
+	FormName string `protobuf:"bytes,2,opt,name=FormName,proto3" json:"FormName,omitempty"`
+	// Format 导出格式 jsonl/csv
+	Format               string   `protobuf:"bytes,3,opt,name=Format,proto3" json:"Format,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReqExport) Reset()         { *m = ReqExport{} }
+func (m *ReqExport) String() string { return proto.CompactTextString(m) }
+func (*ReqExport) ProtoMessage()    {}
+func (*ReqExport) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{51}
+}
+
+func (m *ReqExport) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReqExport.Unmarshal(m, b)
+}
+func (m *ReqExport) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReqExport.Marshal(b, m, deterministic)
+}
+func (m *ReqExport) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReqExport.Merge(m, src)
+}
+func (m *ReqExport) XXX_Size() int {
+	return xxx_messageInfo_ReqExport.Size(m)
+}
+func (m *ReqExport) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReqExport.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReqExport proto.InternalMessageInfo
+
+func (m *ReqExport) GetDatabaseName() string {
+	if m != nil {
+		return m.DatabaseName
+	}
+	return ""
+}
+
+func (m *ReqExport) GetFormName() string {
+	if m != nil {
+		return m.FormName
+	}
+	return ""
+}
+
+func (m *ReqExport) GetFormat() string {
+	if m != nil {
+		return m.Format
+	}
+	return ""
+}
+
+// RespExport 响应导出表数据，数据分块返回
+type RespExport struct {
+	// Code 响应结果码
+	Code Code `protobuf:"varint,1,opt,name=Code,proto3,enum=api.Code" json:"Code,omitempty"`
+	// Data 导出数据分块
+	Data []byte `protobuf:"bytes,2,opt,name=Data,proto3" json:"Data,omitempty"`
+	// Rows 导出行数，仅在最后一块中返回
+	Rows int64 `protobuf:"varint,3,opt,name=Rows,proto3" json:"Rows,omitempty"`
+	// Done 是否为最后一块
+	Done bool `protobuf:"varint,4,opt,name=Done,proto3" json:"Done,omitempty"`
+	// ErrMsg 错误信息
+	ErrMsg               string   `protobuf:"bytes,5,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RespExport) Reset()         { *m = RespExport{} }
+func (m *RespExport) String() string { return proto.CompactTextString(m) }
+func (*RespExport) ProtoMessage()    {}
+func (*RespExport) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{52}
+}
+
+func (m *RespExport) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RespExport.Unmarshal(m, b)
+}
+func (m *RespExport) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RespExport.Marshal(b, m, deterministic)
+}
+func (m *RespExport) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RespExport.Merge(m, src)
+}
+func (m *RespExport) XXX_Size() int {
+	return xxx_messageInfo_RespExport.Size(m)
+}
+func (m *RespExport) XXX_DiscardUnknown() {
+	xxx_messageInfo_RespExport.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RespExport proto.InternalMessageInfo
+
+func (m *RespExport) GetCode() Code {
+	if m != nil {
+		return m.Code
+	}
+	return Code_Success
+}
+
+func (m *RespExport) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *RespExport) GetRows() int64 {
+	if m != nil {
+		return m.Rows
+	}
+	return 0
+}
+
+func (m *RespExport) GetDone() bool {
+	if m != nil {
+		return m.Done
+	}
+	return false
+}
+
+func (m *RespExport) GetErrMsg() string {
+	if m != nil {
+		return m.ErrMsg
+	}
+	return ""
+}
+
+// ReqImport 请求导入表数据，数据分块发送，库表名称及格式仅在首块中读取
+type ReqImport struct {
+	// DatabaseName 数据库名称
+	DatabaseName string `protobuf:"bytes,1,opt,name=DatabaseName,proto3" json:"DatabaseName,omitempty"`
+	// FormName 表名称
+	FormName string `protobuf:"bytes,2,opt,name=FormName,proto3" json:"FormName,omitempty"`
+	// Format 导入格式 jsonl/csv
+	Format string `protobuf:"bytes,3,opt,name=Format,proto3" json:"Format,omitempty"`
+	// Data 导入数据分块
+	Data                 []byte   `protobuf:"bytes,4,opt,name=Data,proto3" json:"Data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReqImport) Reset()         { *m = ReqImport{} }
+func (m *ReqImport) String() string { return proto.CompactTextString(m) }
+func (*ReqImport) ProtoMessage()    {}
+func (*ReqImport) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{53}
+}
+
+func (m *ReqImport) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReqImport.Unmarshal(m, b)
+}
+func (m *ReqImport) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReqImport.Marshal(b, m, deterministic)
+}
+func (m *ReqImport) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReqImport.Merge(m, src)
+}
+func (m *ReqImport) XXX_Size() int {
+	return xxx_messageInfo_ReqImport.Size(m)
+}
+func (m *ReqImport) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReqImport.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReqImport proto.InternalMessageInfo
+
+func (m *ReqImport) GetDatabaseName() string {
+	if m != nil {
+		return m.DatabaseName
+	}
+	return ""
+}
+
+func (m *ReqImport) GetFormName() string {
+	if m != nil {
+		return m.FormName
+	}
+	return ""
+}
+
+func (m *ReqImport) GetFormat() string {
+	if m != nil {
+		return m.Format
+	}
+	return ""
+}
+
+func (m *ReqImport) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+// RespImport 响应导入进度
+type RespImport struct {
+	// Code 响应结果码
+	Code Code `protobuf:"varint,1,opt,name=Code,proto3,enum=api.Code" json:"Code,omitempty"`
+	// Rows 已处理行数
+	Rows int64 `protobuf:"varint,2,opt,name=Rows,proto3" json:"Rows,omitempty"`
+	// Imported 成功导入行数
+	Imported int64 `protobuf:"varint,3,opt,name=Imported,proto3" json:"Imported,omitempty"`
+	// Rejected 无法导入行数
+	Rejected int64 `protobuf:"varint,4,opt,name=Rejected,proto3" json:"Rejected,omitempty"`
+	// Reject 无法导入的行分块
+	Reject []byte `protobuf:"bytes,5,opt,name=Reject,proto3" json:"Reject,omitempty"`
+	// Done 导入是否完成
+	Done bool `protobuf:"varint,6,opt,name=Done,proto3" json:"Done,omitempty"`
+	// ErrMsg 错误信息
+	ErrMsg               string   `protobuf:"bytes,7,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RespImport) Reset()         { *m = RespImport{} }
+func (m *RespImport) String() string { return proto.CompactTextString(m) }
+func (*RespImport) ProtoMessage()    {}
+func (*RespImport) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{54}
+}
+
+func (m *RespImport) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RespImport.Unmarshal(m, b)
+}
+func (m *RespImport) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RespImport.Marshal(b, m, deterministic)
+}
+func (m *RespImport) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RespImport.Merge(m, src)
+}
+func (m *RespImport) XXX_Size() int {
+	return xxx_messageInfo_RespImport.Size(m)
+}
+func (m *RespImport) XXX_DiscardUnknown() {
+	xxx_messageInfo_RespImport.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RespImport proto.InternalMessageInfo
+
+func (m *RespImport) GetCode() Code {
+	if m != nil {
+		return m.Code
+	}
+	return Code_Success
+}
+
+func (m *RespImport) GetRows() int64 {
+	if m != nil {
+		return m.Rows
+	}
+	return 0
+}
+
+func (m *RespImport) GetImported() int64 {
+	if m != nil {
+		return m.Imported
+	}
+	return 0
+}
+
+func (m *RespImport) GetRejected() int64 {
+	if m != nil {
+		return m.Rejected
+	}
+	return 0
+}
+
+func (m *RespImport) GetReject() []byte {
+	if m != nil {
+		return m.Reject
+	}
+	return nil
+}
+
+func (m *RespImport) GetDone() bool {
+	if m != nil {
+		return m.Done
+	}
+	return false
+}
+
+func (m *RespImport) GetErrMsg() string {
+	if m != nil {
+		return m.ErrMsg
+	}
+	return ""
+}
+
+// Blob 大数据描述
+type Blob struct {
+	// Key 大数据key
+	Key string `protobuf:"bytes,1,opt,name=Key,proto3" json:"Key,omitempty"`
+	// ID 大数据唯一ID
+	ID string `protobuf:"bytes,2,opt,name=ID,proto3" json:"ID,omitempty"`
+	// Size 大数据总字节数
+	Size int64 `protobuf:"varint,3,opt,name=Size,proto3" json:"Size,omitempty"`
+	// Chunks 分块数
+	Chunks int64 `protobuf:"varint,4,opt,name=Chunks,proto3" json:"Chunks,omitempty"`
+	// ChunkSize 分块大小
+	ChunkSize int64 `protobuf:"varint,5,opt,name=ChunkSize,proto3" json:"ChunkSize,omitempty"`
+	// SHA256 大数据内容的16进制sha256
+	SHA256               string   `protobuf:"bytes,6,opt,name=SHA256,proto3" json:"SHA256,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Blob) Reset()         { *m = Blob{} }
+func (m *Blob) String() string { return proto.CompactTextString(m) }
+func (*Blob) ProtoMessage()    {}
+func (*Blob) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{55}
+}
+
+func (m *Blob) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Blob.Unmarshal(m, b)
+}
+func (m *Blob) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Blob.Marshal(b, m, deterministic)
+}
+func (m *Blob) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Blob.Merge(m, src)
+}
+func (m *Blob) XXX_Size() int {
+	return xxx_messageInfo_Blob.Size(m)
+}
+func (m *Blob) XXX_DiscardUnknown() {
+	xxx_messageInfo_Blob.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Blob proto.InternalMessageInfo
+
+func (m *Blob) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *Blob) GetID() string {
+	if m != nil {
+		return m.ID
+	}
+	return ""
+}
+
+func (m *Blob) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *Blob) GetChunks() int64 {
+	if m != nil {
+		return m.Chunks
+	}
+	return 0
+}
+
+func (m *Blob) GetChunkSize() int64 {
+	if m != nil {
+		return m.ChunkSize
+	}
+	return 0
+}
+
+func (m *Blob) GetSHA256() string {
+	if m != nil {
+		return m.SHA256
+	}
+	return ""
+}
+
+// ReqPutBlob 请求存储大数据，数据分块发送，库表名称及key仅在首块中读取
+type ReqPutBlob struct {
+	// DatabaseName 数据库名称
+	DatabaseName string `protobuf:"bytes,1,opt,name=DatabaseName,proto3" json:"DatabaseName,omitempty"`
+	// FormName 表名称
+	FormName string `protobuf:"bytes,2,opt,name=FormName,proto3" json:"FormName,omitempty"`
+	// Key 大数据key
+	Key string `protobuf:"bytes,3,opt,name=Key,proto3" json:"Key,omitempty"`
+	// Data 大数据分块
+	Data                 []byte   `protobuf:"bytes,4,opt,name=Data,proto3" json:"Data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReqPutBlob) Reset()         { *m = ReqPutBlob{} }
+func (m *ReqPutBlob) String() string { return proto.CompactTextString(m) }
+func (*ReqPutBlob) ProtoMessage()    {}
+func (*ReqPutBlob) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{56}
+}
+
+func (m *ReqPutBlob) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReqPutBlob.Unmarshal(m, b)
+}
+func (m *ReqPutBlob) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReqPutBlob.Marshal(b, m, deterministic)
+}
+func (m *ReqPutBlob) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReqPutBlob.Merge(m, src)
+}
+func (m *ReqPutBlob) XXX_Size() int {
+	return xxx_messageInfo_ReqPutBlob.Size(m)
+}
+func (m *ReqPutBlob) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReqPutBlob.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReqPutBlob proto.InternalMessageInfo
+
+func (m *ReqPutBlob) GetDatabaseName() string {
+	if m != nil {
+		return m.DatabaseName
+	}
+	return ""
+}
+
+func (m *ReqPutBlob) GetFormName() string {
+	if m != nil {
+		return m.FormName
+	}
+	return ""
+}
+
+func (m *ReqPutBlob) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *ReqPutBlob) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+// ReqBlob 请求读取或删除大数据
+type ReqBlob struct {
+	// DatabaseName 数据库名称
+	DatabaseName string `protobuf:"bytes,1,opt,name=DatabaseName,proto3" json:"DatabaseName,omitempty"`
+	// FormName 表名称
+	FormName string `protobuf:"bytes,2,opt,name=FormName,proto3" json:"FormName,omitempty"`
+	// Key 大数据key
+	Key                  string   `protobuf:"bytes,3,opt,name=Key,proto3" json:"Key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReqBlob) Reset()         { *m = ReqBlob{} }
+func (m *ReqBlob) String() string { return proto.CompactTextString(m) }
+func (*ReqBlob) ProtoMessage()    {}
+func (*ReqBlob) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{57}
+}
+
+func (m *ReqBlob) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReqBlob.Unmarshal(m, b)
+}
+func (m *ReqBlob) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReqBlob.Marshal(b, m, deterministic)
+}
+func (m *ReqBlob) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReqBlob.Merge(m, src)
+}
+func (m *ReqBlob) XXX_Size() int {
+	return xxx_messageInfo_ReqBlob.Size(m)
+}
+func (m *ReqBlob) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReqBlob.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReqBlob proto.InternalMessageInfo
+
+func (m *ReqBlob) GetDatabaseName() string {
+	if m != nil {
+		return m.DatabaseName
+	}
+	return ""
+}
+
+func (m *ReqBlob) GetFormName() string {
+	if m != nil {
+		return m.FormName
+	}
+	return ""
+}
+
+func (m *ReqBlob) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+// RespBlob 响应存储大数据
+type RespBlob struct {
+	// Code 响应结果码
+	Code Code `protobuf:"varint,1,opt,name=Code,proto3,enum=api.Code" json:"Code,omitempty"`
+	// Blob 大数据描述
+	Blob *Blob `protobuf:"bytes,2,opt,name=Blob,proto3" json:"Blob,omitempty"`
+	// ErrMsg 错误信息
+	ErrMsg               string   `protobuf:"bytes,3,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RespBlob) Reset()         { *m = RespBlob{} }
+func (m *RespBlob) String() string { return proto.CompactTextString(m) }
+func (*RespBlob) ProtoMessage()    {}
+func (*RespBlob) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{58}
+}
+
+func (m *RespBlob) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RespBlob.Unmarshal(m, b)
+}
+func (m *RespBlob) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RespBlob.Marshal(b, m, deterministic)
+}
+func (m *RespBlob) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RespBlob.Merge(m, src)
+}
+func (m *RespBlob) XXX_Size() int {
+	return xxx_messageInfo_RespBlob.Size(m)
+}
+func (m *RespBlob) XXX_DiscardUnknown() {
+	xxx_messageInfo_RespBlob.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RespBlob proto.InternalMessageInfo
+
+func (m *RespBlob) GetCode() Code {
+	if m != nil {
+		return m.Code
+	}
+	return Code_Success
+}
+
+func (m *RespBlob) GetBlob() *Blob {
+	if m != nil {
+		return m.Blob
+	}
+	return nil
+}
+
+func (m *RespBlob) GetErrMsg() string {
+	if m != nil {
+		return m.ErrMsg
+	}
+	return ""
+}
+
+// RespGetBlob 响应读取大数据，数据分块返回
+type RespGetBlob struct {
+	// Code 响应结果码
+	Code Code `protobuf:"varint,1,opt,name=Code,proto3,enum=api.Code" json:"Code,omitempty"`
+	// Data 大数据分块
+	Data []byte `protobuf:"bytes,2,opt,name=Data,proto3" json:"Data,omitempty"`
+	// Blob 大数据描述，仅在最后一块中返回
+	Blob *Blob `protobuf:"bytes,3,opt,name=Blob,proto3" json:"Blob,omitempty"`
+	// Done 是否为最后一块
+	Done bool `protobuf:"varint,4,opt,name=Done,proto3" json:"Done,omitempty"`
+	// ErrMsg 错误信息
+	ErrMsg               string   `protobuf:"bytes,5,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RespGetBlob) Reset()         { *m = RespGetBlob{} }
+func (m *RespGetBlob) String() string { return proto.CompactTextString(m) }
+func (*RespGetBlob) ProtoMessage()    {}
+func (*RespGetBlob) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{59}
+}
+
+func (m *RespGetBlob) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RespGetBlob.Unmarshal(m, b)
+}
+func (m *RespGetBlob) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RespGetBlob.Marshal(b, m, deterministic)
+}
+func (m *RespGetBlob) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RespGetBlob.Merge(m, src)
+}
+func (m *RespGetBlob) XXX_Size() int {
+	return xxx_messageInfo_RespGetBlob.Size(m)
+}
+func (m *RespGetBlob) XXX_DiscardUnknown() {
+	xxx_messageInfo_RespGetBlob.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RespGetBlob proto.InternalMessageInfo
+
+func (m *RespGetBlob) GetCode() Code {
+	if m != nil {
+		return m.Code
+	}
+	return Code_Success
+}
+
+func (m *RespGetBlob) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *RespGetBlob) GetBlob() *Blob {
+	if m != nil {
+		return m.Blob
+	}
+	return nil
+}
+
+func (m *RespGetBlob) GetDone() bool {
+	if m != nil {
+		return m.Done
+	}
+	return false
+}
+
+func (m *RespGetBlob) GetErrMsg() string {
+	if m != nil {
+		return m.ErrMsg
+	}
+	return ""
+}
+
+// Resp 通用响应对象
+type Resp struct {
+	// Code 响应结果码
+	Code Code `protobuf:"varint,1,opt,name=Code,proto3,enum=api.Code" json:"Code,omitempty"`
+	// ErrMsg 错误信息
+	ErrMsg               string   `protobuf:"bytes,2,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Resp) Reset()         { *m = Resp{} }
+func (m *Resp) String() string { return proto.CompactTextString(m) }
+func (*Resp) ProtoMessage()    {}
+func (*Resp) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae6ce81ad544face, []int{60}
 }
 
 func (m *Resp) XXX_Unmarshal(b []byte) error {
@@ -1682,6 +3817,11 @@ func init() {
 	proto.RegisterType((*ReqCreateForm)(nil), "api.ReqCreateForm")
 	proto.RegisterType((*ReqCreateKey)(nil), "api.ReqCreateKey")
 	proto.RegisterType((*ReqCreateIndex)(nil), "api.ReqCreateIndex")
+	proto.RegisterType((*ReqDropDatabase)(nil), "api.ReqDropDatabase")
+	proto.RegisterType((*ReqDropForm)(nil), "api.ReqDropForm")
+	proto.RegisterType((*ReqDropIndex)(nil), "api.ReqDropIndex")
+	proto.RegisterType((*ReqRenameDatabase)(nil), "api.ReqRenameDatabase")
+	proto.RegisterType((*ReqRenameForm)(nil), "api.ReqRenameForm")
 	proto.RegisterType((*ReqPutD)(nil), "api.ReqPutD")
 	proto.RegisterType((*RespPutD)(nil), "api.RespPutD")
 	proto.RegisterType((*ReqSetD)(nil), "api.ReqSetD")
@@ -1694,57 +3834,137 @@ func init() {
 	proto.RegisterType((*RespSet)(nil), "api.RespSet")
 	proto.RegisterType((*ReqGet)(nil), "api.ReqGet")
 	proto.RegisterType((*RespGet)(nil), "api.RespGet")
+	proto.RegisterType((*ReqGetVersion)(nil), "api.ReqGetVersion")
+	proto.RegisterType((*ReqHistory)(nil), "api.ReqHistory")
+	proto.RegisterType((*RespHistory)(nil), "api.RespHistory")
+	proto.RegisterType((*Version)(nil), "api.Version")
 	proto.RegisterType((*ReqSelect)(nil), "api.ReqSelect")
 	proto.RegisterType((*RespSelect)(nil), "api.RespSelect")
 	proto.RegisterType((*ReqRemove)(nil), "api.ReqRemove")
 	proto.RegisterType((*ReqDelete)(nil), "api.ReqDelete")
 	proto.RegisterType((*RespDelete)(nil), "api.RespDelete")
+	proto.RegisterType((*ReqCompact)(nil), "api.ReqCompact")
+	proto.RegisterType((*RespCompact)(nil), "api.RespCompact")
+	proto.RegisterType((*ReqRebuildIndex)(nil), "api.ReqRebuildIndex")
+	proto.RegisterType((*RespRebuildIndex)(nil), "api.RespRebuildIndex")
+	proto.RegisterType((*ReqIndexStatus)(nil), "api.ReqIndexStatus")
+	proto.RegisterType((*RespIndexStatus)(nil), "api.RespIndexStatus")
+	proto.RegisterType((*ReqFormStats)(nil), "api.ReqFormStats")
+	proto.RegisterType((*RespFormStats)(nil), "api.RespFormStats")
+	proto.RegisterType((*ReqRotateKey)(nil), "api.ReqRotateKey")
+	proto.RegisterType((*RespRotateKey)(nil), "api.RespRotateKey")
+	proto.RegisterType((*ReqSetSyncMode)(nil), "api.ReqSetSyncMode")
+	proto.RegisterType((*ReqBackup)(nil), "api.ReqBackup")
+	proto.RegisterType((*ReqRestore)(nil), "api.ReqRestore")
+	proto.RegisterType((*RespBackup)(nil), "api.RespBackup")
+	proto.RegisterType((*ReqExport)(nil), "api.ReqExport")
+	proto.RegisterType((*RespExport)(nil), "api.RespExport")
+	proto.RegisterType((*ReqImport)(nil), "api.ReqImport")
+	proto.RegisterType((*RespImport)(nil), "api.RespImport")
+	proto.RegisterType((*Blob)(nil), "api.Blob")
+	proto.RegisterType((*ReqPutBlob)(nil), "api.ReqPutBlob")
+	proto.RegisterType((*ReqBlob)(nil), "api.ReqBlob")
+	proto.RegisterType((*RespBlob)(nil), "api.RespBlob")
+	proto.RegisterType((*RespGetBlob)(nil), "api.RespGetBlob")
 	proto.RegisterType((*Resp)(nil), "api.Resp")
 }
 
 func init() { proto.RegisterFile("api/rs.proto", fileDescriptor_ae6ce81ad544face) }
 
 var fileDescriptor_ae6ce81ad544face = []byte{
-	// 650 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x56, 0xdb, 0x6e, 0xd3, 0x4c,
-	0x10, 0xfe, 0x13, 0xbb, 0x4d, 0x3c, 0x4d, 0xa3, 0xfe, 0x2b, 0x84, 0xac, 0xa2, 0x8a, 0x68, 0xaf,
-	0x52, 0x90, 0x8c, 0x28, 0xd7, 0x5c, 0xd0, 0x94, 0x06, 0x54, 0x81, 0xaa, 0x35, 0x2a, 0xa2, 0x08,
-	0x89, 0xad, 0x3b, 0x01, 0x4b, 0x8e, 0xed, 0xf8, 0x50, 0x91, 0x77, 0xe0, 0xa1, 0xd1, 0xec, 0xda,
-	0x4e, 0x82, 0x62, 0xb9, 0xa5, 0x87, 0x3b, 0xcf, 0xcc, 0xce, 0x7c, 0x87, 0xd9, 0xc4, 0x86, 0x9e,
-	0x8c, 0xfd, 0x17, 0x49, 0xea, 0xc4, 0x49, 0x94, 0x45, 0xcc, 0x90, 0xb1, 0xbf, 0xdb, 0xa7, 0xd4,
-	0xa5, 0xcc, 0xa4, 0x4e, 0xea, 0xd8, 0x8b, 0xc2, 0x89, 0x8e, 0xb9, 0x05, 0x1d, 0x81, 0xb3, 0x51,
-	0x14, 0x4e, 0xf8, 0x77, 0xe8, 0x0a, 0x4c, 0x63, 0x7a, 0x66, 0x7b, 0x60, 0x8e, 0xa2, 0x4b, 0xb4,
-	0x5b, 0x83, 0xd6, 0xb0, 0x7f, 0x60, 0x39, 0x32, 0xf6, 0x1d, 0x4a, 0x08, 0x95, 0xd6, 0xe5, 0x70,
-	0x62, 0xb7, 0x07, 0xad, 0xe1, 0x56, 0x55, 0x0e, 0x27, 0x42, 0xa5, 0xd9, 0x63, 0xd8, 0x7c, 0x9b,
-	0x24, 0x1f, 0xd2, 0x1f, 0xb6, 0x31, 0x68, 0x0d, 0x2d, 0x51, 0x44, 0xbc, 0x0f, 0x3d, 0x81, 0xb3,
-	0x23, 0x99, 0xc9, 0x0b, 0x99, 0x62, 0xca, 0x53, 0xd8, 0x26, 0xc4, 0x2a, 0xd1, 0x04, 0xfb, 0x1c,
-	0xac, 0xea, 0xac, 0xdd, 0x1e, 0x18, 0xc3, 0xad, 0x83, 0x6d, 0x75, 0xa6, 0xcc, 0x8a, 0x45, 0xbd,
-	0x96, 0x84, 0x43, 0x32, 0x67, 0xc7, 0x51, 0x32, 0x4d, 0x19, 0x87, 0x5e, 0xd9, 0xf0, 0x51, 0x4e,
-	0x35, 0xae, 0x25, 0x56, 0x72, 0xdc, 0x03, 0x8b, 0x48, 0xea, 0x86, 0x06, 0x82, 0x4f, 0x61, 0x43,
-	0x9d, 0x2b, 0xc8, 0xe9, 0x3a, 0x65, 0x84, 0xce, 0xd7, 0x92, 0x7a, 0x03, 0xff, 0xd3, 0x1a, 0x12,
-	0x94, 0x19, 0x96, 0xe8, 0x8c, 0x81, 0xb9, 0xc4, 0x4a, 0x3d, 0x33, 0x1b, 0x3a, 0xa3, 0x68, 0x3a,
-	0xc5, 0x30, 0x53, 0xe6, 0x5b, 0xa2, 0x0c, 0x79, 0x4c, 0xe6, 0x2e, 0xcc, 0x6c, 0xa2, 0xba, 0x0f,
-	0xdd, 0xf2, 0x68, 0xb1, 0xc6, 0xbf, 0xac, 0xac, 0xca, 0xb5, 0xa4, 0x7f, 0xb7, 0x68, 0x7f, 0x05,
-	0x6b, 0xd2, 0x77, 0x1d, 0x3f, 0x2b, 0x55, 0xed, 0xf5, 0xaa, 0x8c, 0x15, 0x55, 0x44, 0x93, 0x26,
-	0x7f, 0x9a, 0xc7, 0x68, 0x9b, 0x4a, 0xc9, 0x76, 0x65, 0x2a, 0x25, 0x45, 0x55, 0xe6, 0x89, 0xba,
-	0x5d, 0x9a, 0xcd, 0x09, 0xce, 0xaf, 0x45, 0x66, 0x57, 0x8f, 0x5f, 0x22, 0x54, 0xc5, 0xd4, 0x7f,
-	0x82, 0x73, 0x37, 0x4b, 0x72, 0x2f, 0xcb, 0x13, 0x2c, 0x98, 0xad, 0xe4, 0x78, 0x06, 0xfd, 0x0a,
-	0xf3, 0x7d, 0x78, 0x89, 0xbf, 0x1e, 0x04, 0xf5, 0xa5, 0xfa, 0xd1, 0x9e, 0xe6, 0xd9, 0x11, 0xdb,
-	0x01, 0xe3, 0x04, 0xe7, 0x05, 0x0a, 0x3d, 0xb2, 0x47, 0xb0, 0x71, 0x26, 0x83, 0x5c, 0x4f, 0xee,
-	0x09, 0x1d, 0xf0, 0xaf, 0xfa, 0xc7, 0xad, 0x7a, 0x1a, 0x6e, 0x86, 0x0d, 0x9d, 0x77, 0x32, 0xfd,
-	0x49, 0x63, 0x69, 0x84, 0x29, 0xca, 0xb0, 0xf6, 0x22, 0x68, 0x3e, 0x2e, 0xde, 0x9c, 0x8f, 0xea,
-	0xb9, 0x73, 0x3e, 0x4f, 0x14, 0x9f, 0xf1, 0x5a, 0x3e, 0xfc, 0xb3, 0x46, 0x1e, 0x5f, 0x03, 0x79,
-	0x2d, 0xf5, 0x5a, 0xd4, 0x18, 0x36, 0xf5, 0x56, 0x6e, 0x7d, 0x07, 0x0a, 0xd2, 0xc6, 0x1a, 0x13,
-	0xcd, 0x65, 0x13, 0xcf, 0x49, 0xa7, 0x5a, 0xea, 0xdd, 0x7b, 0xa8, 0xd5, 0xb8, 0xf8, 0xe0, 0x6a,
-	0x08, 0xf2, 0xce, 0xd5, 0x9c, 0x2b, 0x35, 0xe3, 0xfb, 0x50, 0xc3, 0xcf, 0x34, 0xef, 0x71, 0x33,
-	0xef, 0x9b, 0xdd, 0xa7, 0x2b, 0x7a, 0xf1, 0xcc, 0x5c, 0x0c, 0xd0, 0xbb, 0x3d, 0xed, 0x7d, 0xe8,
-	0xea, 0x49, 0x51, 0xa2, 0x60, 0xca, 0xbf, 0xfb, 0x32, 0x29, 0xaa, 0x32, 0x8f, 0x00, 0xf4, 0x1e,
-	0x14, 0x70, 0xb3, 0xa4, 0x51, 0x94, 0x17, 0x6f, 0xa3, 0x0d, 0xa1, 0x83, 0x85, 0x50, 0x63, 0xbd,
-	0x50, 0x73, 0x45, 0xe8, 0x37, 0x25, 0x54, 0xe0, 0x34, 0xba, 0xc2, 0x7b, 0xd8, 0x8f, 0xf6, 0xf1,
-	0x08, 0x03, 0xcc, 0xf0, 0x21, 0x7d, 0xfc, 0xa2, 0x7d, 0x2c, 0x80, 0xff, 0xc9, 0xc7, 0xba, 0xab,
-	0xf1, 0x1a, 0x4c, 0x1a, 0xdd, 0x34, 0x74, 0xd1, 0xde, 0x5e, 0x6e, 0x7f, 0x56, 0xb4, 0xb1, 0x2d,
-	0xe8, 0xb8, 0xb9, 0xe7, 0x61, 0x9a, 0xee, 0xfc, 0xc7, 0xba, 0x60, 0x1e, 0x4b, 0x3f, 0xd8, 0x69,
-	0x1d, 0xee, 0x01, 0xf3, 0x42, 0x47, 0x5e, 0x60, 0xe2, 0x7b, 0x4e, 0xe0, 0x07, 0x73, 0x9a, 0x7b,
-	0xd8, 0x11, 0xee, 0x29, 0x7d, 0x32, 0x5e, 0x6c, 0xaa, 0x2f, 0xc7, 0x57, 0x7f, 0x02, 0x00, 0x00,
-	0xff, 0xff, 0xf8, 0xb7, 0xbd, 0xfd, 0x6e, 0x0a, 0x00, 0x00,
+	// 1494 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x59, 0x5b, 0x6f, 0x1b, 0xc5,
+	0x17, 0xff, 0xaf, 0xd7, 0xf1, 0xe5, 0xc4, 0x4e, 0xf3, 0x5f, 0x50, 0xb5, 0x0a, 0x6d, 0xb1, 0x56,
+	0x20, 0xb9, 0x20, 0x05, 0x29, 0x08, 0xde, 0x78, 0x68, 0x9c, 0x36, 0x8d, 0x42, 0xab, 0x68, 0x1c,
+	0x82, 0x68, 0xc5, 0x65, 0xe3, 0x4c, 0xd2, 0xa5, 0xf6, 0xce, 0x66, 0x2f, 0x4d, 0x0c, 0x0f, 0x45,
+	0x3c, 0x21, 0xf8, 0x06, 0x88, 0x17, 0x3e, 0x07, 0x5f, 0x8b, 0x47, 0x1e, 0xd0, 0x39, 0x73, 0xf1,
+	0x6e, 0xb1, 0xbb, 0x0e, 0x89, 0x23, 0xde, 0xe6, 0x77, 0x76, 0x66, 0xce, 0xf9, 0xfd, 0xe6, 0xcc,
+	0x75, 0xa1, 0xe5, 0x47, 0xc1, 0x07, 0x71, 0xb2, 0x1e, 0xc5, 0x22, 0x15, 0x8e, 0xed, 0x47, 0xc1,
+	0xda, 0x0a, 0x9a, 0x8e, 0xfc, 0xd4, 0x97, 0x46, 0x89, 0x07, 0x22, 0x3c, 0x96, 0xd8, 0x6b, 0x42,
+	0x9d, 0xf1, 0xd3, 0x9e, 0x08, 0x8f, 0xbd, 0x6f, 0xa0, 0xc1, 0x78, 0x12, 0x61, 0xd9, 0xb9, 0x0d,
+	0xd5, 0x9e, 0x38, 0xe2, 0xae, 0xd5, 0xb1, 0xba, 0x2b, 0x1b, 0xcd, 0x75, 0x3f, 0x0a, 0xd6, 0xd1,
+	0xc0, 0xc8, 0x2c, 0x3f, 0x87, 0xc7, 0x6e, 0xa5, 0x63, 0x75, 0x97, 0xcd, 0xe7, 0xf0, 0x98, 0x91,
+	0xd9, 0xb9, 0x09, 0xb5, 0xfb, 0x71, 0xfc, 0x28, 0x39, 0x71, 0xed, 0x8e, 0xd5, 0x6d, 0x32, 0x85,
+	0xbc, 0x15, 0x68, 0x31, 0x7e, 0xba, 0xe5, 0xa7, 0xfe, 0xa1, 0x9f, 0xf0, 0xc4, 0x4b, 0xa0, 0x8d,
+	0x1e, 0x8d, 0xa1, 0xcc, 0xed, 0xfb, 0xd0, 0x34, 0x75, 0xdd, 0x4a, 0xc7, 0xee, 0x2e, 0x6f, 0xb4,
+	0xa9, 0x8e, 0xb6, 0xb2, 0xc9, 0xf7, 0x99, 0x41, 0xac, 0x23, 0xcd, 0xd3, 0x07, 0x22, 0x1e, 0x25,
+	0x8e, 0x07, 0x2d, 0xdd, 0xe0, 0xb1, 0x3f, 0x92, 0x7e, 0x9b, 0xac, 0x60, 0xf3, 0x06, 0xd0, 0xc4,
+	0x20, 0x65, 0x83, 0x92, 0x00, 0xdf, 0x86, 0x25, 0xaa, 0xa7, 0x82, 0x93, 0xdf, 0xd1, 0xc2, 0xa4,
+	0x7d, 0x66, 0x50, 0xf7, 0xe0, 0xff, 0x38, 0x0c, 0x31, 0xf7, 0x53, 0xae, 0xbd, 0x3b, 0x0e, 0x54,
+	0x73, 0x51, 0x51, 0xd9, 0x71, 0xa1, 0xde, 0x13, 0xa3, 0x11, 0x0f, 0x53, 0x12, 0xbf, 0xc9, 0x34,
+	0xf4, 0x22, 0x68, 0xe5, 0xc5, 0x2c, 0x0b, 0xf5, 0x2e, 0x34, 0x74, 0x55, 0x35, 0x8c, 0xaf, 0x48,
+	0x69, 0x3e, 0xcf, 0x0c, 0xfa, 0x4f, 0x0b, 0xda, 0x26, 0x6a, 0xe4, 0x37, 0x8f, 0x9e, 0x86, 0x55,
+	0x65, 0x3a, 0x2b, 0xbb, 0xc0, 0x0a, 0xc3, 0xc4, 0x9e, 0xf7, 0xc7, 0x11, 0x77, 0xab, 0xc4, 0xa4,
+	0x6d, 0x44, 0x45, 0x23, 0x33, 0x9f, 0x9d, 0x0d, 0x58, 0xee, 0x89, 0x51, 0x14, 0xf3, 0x24, 0x09,
+	0x44, 0xe8, 0x2e, 0x51, 0xed, 0x55, 0xc5, 0xdb, 0xd8, 0x59, 0xbe, 0x12, 0x51, 0x0b, 0x4f, 0x82,
+	0x90, 0xbb, 0x35, 0x45, 0x8d, 0x90, 0xb3, 0x06, 0x8d, 0x03, 0x1e, 0x63, 0x95, 0xc4, 0xad, 0x77,
+	0xac, 0x6e, 0x9b, 0x19, 0xec, 0xc5, 0xd0, 0x32, 0xac, 0x77, 0xf9, 0x78, 0x2e, 0xd2, 0x6b, 0x92,
+	0x46, 0x8e, 0xb8, 0xc1, 0xd8, 0x7e, 0x97, 0x8f, 0xfb, 0x69, 0x9c, 0x0d, 0xd2, 0x2c, 0xe6, 0x4a,
+	0x81, 0x82, 0xcd, 0xfb, 0xd5, 0x82, 0x15, 0xe3, 0x74, 0x27, 0x3c, 0xe2, 0xe7, 0xd7, 0xe1, 0xd6,
+	0x79, 0x07, 0xda, 0x79, 0x9c, 0xb8, 0xd5, 0x8e, 0xdd, 0x6d, 0xb2, 0xa2, 0xd1, 0x7b, 0x17, 0x6e,
+	0xe0, 0xb4, 0x8e, 0x45, 0xf4, 0xba, 0xd4, 0xf5, 0x1e, 0xc1, 0xb2, 0xaa, 0x36, 0x77, 0xae, 0xbc,
+	0x26, 0x7e, 0x35, 0x0c, 0xd8, 0xdd, 0xb5, 0xe9, 0xa1, 0xa6, 0x29, 0xe3, 0xa1, 0x3f, 0x2a, 0x9d,
+	0xa6, 0x8f, 0xf9, 0x59, 0xce, 0x8f, 0x86, 0x5e, 0x00, 0x6d, 0xd3, 0xc5, 0x55, 0xe8, 0x90, 0x77,
+	0x65, 0x17, 0x5d, 0xf5, 0x68, 0x6d, 0xdf, 0xcb, 0xd2, 0x2d, 0x67, 0x15, 0xec, 0x5d, 0x3e, 0x56,
+	0x7d, 0x63, 0xd1, 0x79, 0x13, 0x96, 0x0e, 0xfc, 0x61, 0x26, 0xfb, 0x6b, 0x31, 0x09, 0xb0, 0xde,
+	0xfe, 0xfe, 0xa7, 0xd4, 0x91, 0xcd, 0xb0, 0xe8, 0x3d, 0x95, 0xbb, 0x02, 0xf5, 0x52, 0xb2, 0xa4,
+	0xb8, 0x50, 0x7f, 0xe8, 0x27, 0xcf, 0xd0, 0x11, 0x76, 0x5a, 0x65, 0x1a, 0xce, 0x5c, 0x41, 0x64,
+	0x84, 0x7d, 0x7e, 0x15, 0x11, 0xf6, 0xf9, 0x22, 0x22, 0x7c, 0x8b, 0x22, 0xdc, 0x9e, 0x1a, 0xa1,
+	0xf7, 0xb9, 0xf4, 0xbc, 0x3d, 0x87, 0xe7, 0xe9, 0x64, 0x66, 0x79, 0xfd, 0xd1, 0x82, 0x9a, 0x1c,
+	0xba, 0x4b, 0xa7, 0x87, 0x8a, 0xda, 0x9e, 0xa2, 0x6b, 0x75, 0x8a, 0xae, 0x4b, 0x13, 0x5d, 0x9f,
+	0x40, 0x5d, 0x8d, 0xfc, 0xd5, 0xcb, 0xaa, 0x08, 0xf6, 0xf9, 0x7f, 0x80, 0x60, 0x9f, 0x2f, 0x80,
+	0xe0, 0x13, 0xe2, 0xb7, 0xbd, 0x08, 0x7e, 0xde, 0x81, 0x8c, 0x7b, 0xbb, 0x3c, 0xee, 0x8b, 0x65,
+	0xdd, 0xf7, 0xb4, 0x34, 0x6d, 0xf3, 0x54, 0x6d, 0x75, 0x0b, 0x18, 0x1a, 0x17, 0xea, 0xaa, 0x73,
+	0x1a, 0x9c, 0x2a, 0xd3, 0xd0, 0xfb, 0x0a, 0x80, 0xf1, 0xd3, 0x87, 0x41, 0x92, 0x8a, 0x78, 0xbc,
+	0x00, 0xd1, 0x42, 0xdc, 0x7d, 0x92, 0x48, 0x3b, 0x28, 0x11, 0xae, 0x9b, 0xdb, 0xff, 0xe5, 0x59,
+	0xae, 0x45, 0x55, 0x94, 0x71, 0x72, 0x1a, 0x98, 0x29, 0xe6, 0x4b, 0xc3, 0x34, 0x4f, 0xda, 0x2a,
+	0x90, 0x9e, 0x31, 0x3e, 0x2e, 0xd4, 0xb7, 0xf8, 0x90, 0xa7, 0xfc, 0x88, 0xfa, 0x6c, 0x30, 0x0d,
+	0x71, 0xab, 0xd9, 0x0f, 0x46, 0x32, 0xb1, 0x6d, 0x46, 0x65, 0x0a, 0xe0, 0x3c, 0x0a, 0x62, 0xae,
+	0x52, 0x5b, 0x21, 0xef, 0x05, 0x34, 0x69, 0x86, 0x0d, 0xf9, 0xe0, 0xf2, 0x49, 0x78, 0x17, 0x1a,
+	0xb2, 0x27, 0x11, 0xbb, 0x76, 0xee, 0xb4, 0xa8, 0x8d, 0xcc, 0x7c, 0xf6, 0x04, 0x80, 0x9c, 0x55,
+	0xe4, 0xb8, 0x3c, 0x41, 0x7b, 0x22, 0x53, 0x87, 0xd9, 0x25, 0x26, 0xc1, 0x44, 0x16, 0x7b, 0x7a,
+	0xda, 0x56, 0x0b, 0x4a, 0x7f, 0x49, 0x44, 0x19, 0x1f, 0x89, 0x17, 0x7c, 0x01, 0x89, 0x23, 0x75,
+	0x94, 0x23, 0x70, 0x9d, 0x3a, 0x7e, 0x21, 0x75, 0x54, 0x8e, 0xff, 0x95, 0x8e, 0xb3, 0x72, 0x73,
+	0x48, 0x73, 0x0d, 0xcf, 0xc1, 0xfe, 0x15, 0xe4, 0xc6, 0x1d, 0x80, 0x4d, 0x7f, 0xf0, 0xfc, 0x24,
+	0x16, 0x59, 0xa8, 0x33, 0x36, 0x67, 0xf1, 0x7e, 0xb3, 0xe4, 0xd4, 0xd3, 0xfe, 0xca, 0xd7, 0x5a,
+	0xc6, 0x07, 0x22, 0x3e, 0x4a, 0xc8, 0x93, 0xcd, 0x34, 0x44, 0x47, 0xfd, 0xe0, 0x3b, 0xbe, 0xc9,
+	0x8f, 0x85, 0x3a, 0x9f, 0xd9, 0x2c, 0x67, 0x71, 0x6e, 0x41, 0x13, 0xd1, 0xbd, 0xe3, 0x94, 0xc7,
+	0x6a, 0x8a, 0x4c, 0x0c, 0x39, 0x31, 0x96, 0x0a, 0x62, 0x64, 0x74, 0x7a, 0x65, 0xfc, 0x30, 0x0b,
+	0x86, 0x47, 0xd7, 0x77, 0x94, 0xfc, 0x1a, 0x56, 0x51, 0x94, 0x82, 0xdf, 0x8b, 0x0c, 0xb2, 0x5d,
+	0x36, 0xc8, 0x29, 0xdd, 0x18, 0xa8, 0xe3, 0x7e, 0xea, 0xa7, 0x59, 0x72, 0x2d, 0xb4, 0x7e, 0xb2,
+	0x50, 0xce, 0x24, 0xca, 0xfb, 0x2d, 0xa7, 0x85, 0x15, 0xb5, 0x3f, 0x09, 0xd0, 0xba, 0x2f, 0x52,
+	0x7f, 0xa8, 0xc6, 0x59, 0x02, 0x5c, 0x00, 0xb7, 0x44, 0x68, 0x16, 0x40, 0x2c, 0xcf, 0x1c, 0xd8,
+	0xc7, 0x74, 0x41, 0xc0, 0xe8, 0xb1, 0xc7, 0x4b, 0xd3, 0xf7, 0xfe, 0xb2, 0xe4, 0x73, 0xc5, 0xa4,
+	0xc7, 0x12, 0x62, 0xaf, 0x5c, 0x48, 0x2b, 0xf3, 0x5c, 0x48, 0xd7, 0x70, 0x81, 0x38, 0xc1, 0xab,
+	0x6f, 0xa2, 0x98, 0x1b, 0x9c, 0x9f, 0x19, 0xd5, 0xe2, 0xcc, 0x58, 0x93, 0x97, 0x79, 0x4c, 0x76,
+	0xb5, 0x0b, 0x18, 0x4c, 0xad, 0xfc, 0x33, 0xfa, 0x54, 0x53, 0xad, 0x24, 0x44, 0x89, 0x99, 0x9f,
+	0x06, 0x82, 0x6e, 0xb8, 0x16, 0x93, 0x20, 0x27, 0x67, 0xa3, 0x20, 0x27, 0x23, 0x39, 0x99, 0x48,
+	0x2f, 0x70, 0xed, 0x2d, 0x2e, 0x0d, 0x95, 0x7f, 0x2c, 0x0d, 0x3f, 0x28, 0x49, 0x27, 0xbd, 0x96,
+	0x48, 0x7a, 0x07, 0x60, 0x97, 0x8f, 0xf5, 0x6e, 0x5a, 0xa1, 0x9b, 0x79, 0xce, 0x92, 0x97, 0xc8,
+	0x2e, 0x4a, 0x34, 0x6b, 0xf7, 0xd8, 0xa3, 0x69, 0xd2, 0xe7, 0x69, 0x7f, 0x1c, 0x0e, 0x1e, 0xa1,
+	0x8f, 0x39, 0xf3, 0x44, 0xd7, 0xd7, 0x79, 0xa2, 0xb1, 0xd7, 0xa3, 0x0d, 0x03, 0x59, 0x66, 0xd1,
+	0xbc, 0x2f, 0x22, 0x7b, 0x7e, 0xfa, 0x4c, 0x75, 0x44, 0x65, 0xaf, 0x43, 0x4b, 0x34, 0xe3, 0x78,
+	0x5a, 0x99, 0xd4, 0xb0, 0x72, 0x35, 0x7e, 0xb7, 0xe4, 0x06, 0xa1, 0x1c, 0x95, 0x08, 0x77, 0xab,
+	0xf8, 0x74, 0x46, 0x6b, 0xa3, 0x31, 0x60, 0x26, 0xc8, 0x77, 0x2b, 0x35, 0xd9, 0x08, 0x90, 0x35,
+	0x18, 0x72, 0x9d, 0x6d, 0x12, 0x60, 0x2c, 0xb9, 0x3c, 0xa3, 0x72, 0x4e, 0xdc, 0x5a, 0x41, 0x5c,
+	0x7a, 0x3b, 0x3b, 0xbd, 0x7f, 0x1e, 0x89, 0xf8, 0xf2, 0xfb, 0xcc, 0x4d, 0xa8, 0x61, 0xd9, 0xd7,
+	0x6f, 0x44, 0x0a, 0x79, 0x2f, 0xa5, 0x0e, 0xca, 0x4b, 0x89, 0x0e, 0xb8, 0x80, 0xf8, 0xa9, 0xaf,
+	0x0e, 0x5c, 0x54, 0x46, 0x1b, 0x13, 0x67, 0x9a, 0x3c, 0x95, 0x0b, 0x0b, 0x4d, 0xa3, 0x64, 0xa1,
+	0x39, 0x23, 0x96, 0x3b, 0xa3, 0x45, 0xb2, 0x34, 0x81, 0x57, 0x27, 0x81, 0x7b, 0x7f, 0xa8, 0x14,
+	0xd8, 0x19, 0xcd, 0x49, 0x9d, 0x68, 0x56, 0x72, 0x34, 0xd7, 0xa0, 0x21, 0x1b, 0xab, 0xb3, 0xa6,
+	0xcd, 0x0c, 0xc6, 0x6f, 0x8c, 0x7f, 0xcb, 0x07, 0xf8, 0x4d, 0x66, 0x80, 0xc1, 0x18, 0xa5, 0x2c,
+	0x93, 0x14, 0x2d, 0xa6, 0x90, 0x91, 0xad, 0x36, 0x55, 0xb6, 0x7a, 0x41, 0xb6, 0x9f, 0x2d, 0xa8,
+	0x6e, 0x0e, 0xc5, 0xe1, 0x94, 0xab, 0xff, 0x0a, 0x54, 0x76, 0xb6, 0x94, 0x34, 0x95, 0x9d, 0x2d,
+	0x93, 0x73, 0x76, 0x31, 0xe7, 0x7a, 0xcf, 0xb2, 0xf0, 0xb9, 0x4e, 0x4f, 0x85, 0x30, 0xd3, 0xa9,
+	0x94, 0x4b, 0xd2, 0x89, 0x01, 0x5b, 0xf5, 0x1f, 0xde, 0xdb, 0xf8, 0xe8, 0x63, 0x9d, 0xa9, 0x12,
+	0x79, 0x31, 0xcd, 0xb7, 0xbd, 0x2c, 0xa5, 0x88, 0xae, 0xfe, 0xe2, 0x33, 0x6d, 0xf8, 0x9e, 0xd2,
+	0xdb, 0xc2, 0x62, 0x1c, 0xea, 0xd7, 0x7c, 0xea, 0xbd, 0xfc, 0x35, 0x1f, 0xab, 0x15, 0x5e, 0xf3,
+	0xd1, 0xc0, 0xe4, 0xf0, 0xcc, 0x3a, 0x60, 0xfc, 0xa2, 0xce, 0x75, 0xdb, 0x3c, 0x9d, 0xc7, 0xcb,
+	0xb4, 0x99, 0xa7, 0x3d, 0xdb, 0xd3, 0x3d, 0x5f, 0x64, 0x12, 0x7e, 0x02, 0x55, 0x0c, 0xa6, 0x2c,
+	0x8a, 0x49, 0xf3, 0x4a, 0xbe, 0xf9, 0x7b, 0x1b, 0xb2, 0x99, 0xb3, 0x0c, 0xf5, 0x7e, 0x36, 0x18,
+	0xf0, 0x24, 0x59, 0xfd, 0x9f, 0xd3, 0x80, 0xea, 0x03, 0x3f, 0x18, 0xae, 0x5a, 0xce, 0x1b, 0x70,
+	0xe3, 0xb3, 0x30, 0x38, 0xcd, 0xf8, 0x41, 0x20, 0x86, 0xb8, 0x4d, 0x86, 0xab, 0x95, 0xcd, 0xdb,
+	0xe0, 0x0c, 0xc2, 0x75, 0xff, 0x90, 0xc7, 0xc1, 0x60, 0x7d, 0x18, 0x0c, 0xc7, 0xe8, 0x6c, 0xb3,
+	0xce, 0xfa, 0x7b, 0xf8, 0x6b, 0xe5, 0xb0, 0x46, 0x7f, 0x58, 0x3e, 0xfc, 0x7b, 0x00, 0x53, 0x7f,
+	0x14, 0xdc, 0x96, 0x19, 0x00, 0x00,
 }
@@ -25,30 +25,48 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 func init() { proto.RegisterFile("api/server.proto", fileDescriptor_19b13ee64afa9929) }
 
 var fileDescriptor_19b13ee64afa9929 = []byte{
-	// 363 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x93, 0x51, 0x6b, 0xea, 0x30,
-	0x14, 0xc7, 0x0b, 0xde, 0x5b, 0xf1, 0xd4, 0xab, 0xd7, 0x33, 0xd8, 0x43, 0xde, 0x56, 0x18, 0x0c,
-	0x64, 0x15, 0xb6, 0xb7, 0xc1, 0x1e, 0xa6, 0xb2, 0x22, 0x1b, 0xac, 0xd8, 0x4f, 0x90, 0xba, 0x33,
-	0x28, 0xd4, 0xb6, 0xb6, 0x51, 0xe6, 0x87, 0xdc, 0x77, 0x1a, 0x49, 0x34, 0x4d, 0xb6, 0xc7, 0xdf,
-	0x3f, 0xbf, 0xff, 0x49, 0x8c, 0x29, 0xfc, 0xe7, 0x75, 0x3e, 0x6b, 0xa9, 0x39, 0x50, 0x13, 0xd5,
-	0x4d, 0x25, 0x2a, 0xec, 0xf1, 0x3a, 0x67, 0x43, 0x19, 0x37, 0xad, 0x8e, 0xee, 0xbe, 0xfe, 0x42,
-	0xff, 0x35, 0x2f, 0x8e, 0x4f, 0xc9, 0x0a, 0x6f, 0xa0, 0x1f, 0x93, 0x58, 0x54, 0xe5, 0x07, 0x0e,
-	0x23, 0x5e, 0xe7, 0xd1, 0x9a, 0x76, 0x92, 0xd8, 0xbf, 0x13, 0xb5, 0xb5, 0xc4, 0xd0, 0xc3, 0x07,
-	0x18, 0xbf, 0x65, 0x82, 0xe7, 0xe5, 0x92, 0x0b, 0x9e, 0xf1, 0x96, 0x5a, 0x9c, 0x9c, 0x1b, 0x26,
-	0x62, 0x68, 0x6a, 0x26, 0x0b, 0x3d, 0x8c, 0x20, 0xd0, 0xdd, 0xe7, 0xaa, 0xd9, 0xb6, 0x78, 0x9e,
-	0xbd, 0x53, 0xc8, 0x46, 0xa6, 0xa3, 0x38, 0xf4, 0xf0, 0x11, 0x46, 0x8b, 0x86, 0xb8, 0xa0, 0xf3,
-	0x10, 0xbc, 0x34, 0x87, 0x73, 0x72, 0x36, 0xf9, 0xb5, 0x5f, 0xe8, 0xe1, 0x2d, 0x80, 0xd6, 0xe4,
-	0x3c, 0x44, 0xb7, 0x2a, 0x33, 0x36, 0x30, 0xb5, 0xd0, 0xc3, 0x29, 0x0c, 0xf4, 0xd2, 0x0b, 0x1d,
-	0xbb, 0xdf, 0x64, 0x22, 0x57, 0x9e, 0x41, 0xa0, 0x57, 0x56, 0xe5, 0x3b, 0x7d, 0xe2, 0x85, 0xab,
-	0xab, 0xd0, 0x2d, 0x5c, 0xc3, 0x9f, 0x64, 0x2f, 0x96, 0xdd, 0xf5, 0x4a, 0xb2, 0xae, 0x57, 0xa2,
-	0xd6, 0x52, 0xb2, 0x35, 0x49, 0x96, 0x26, 0x51, 0x6b, 0xb1, 0xa3, 0xc5, 0xae, 0x16, 0x6b, 0x2d,
-	0x84, 0x5e, 0xb2, 0x17, 0x18, 0x58, 0x7b, 0xb2, 0xa1, 0xbd, 0xa5, 0x76, 0x52, 0xb2, 0x9c, 0x94,
-	0x6c, 0x27, 0xa5, 0x93, 0x13, 0xdb, 0x4e, 0xec, 0x38, 0xb1, 0x72, 0xa6, 0xe0, 0xa7, 0x54, 0xd0,
-	0x46, 0xe0, 0xa8, 0x1b, 0x25, 0x99, 0x8d, 0xad, 0x69, 0x32, 0x50, 0xe7, 0xf7, 0xd7, 0xb4, 0xad,
-	0x0e, 0xd4, 0xc9, 0x9a, 0x7f, 0xfe, 0x25, 0xfe, 0x92, 0x0a, 0x12, 0x96, 0xa6, 0xd9, 0x9a, 0xa9,
-	0x83, 0xd0, 0x9b, 0x5f, 0x01, 0x6e, 0xca, 0x88, 0x67, 0xd4, 0xe4, 0x9b, 0xa8, 0xc8, 0x8b, 0xa3,
-	0x54, 0xe6, 0x41, 0xaa, 0x3e, 0x83, 0x44, 0x3e, 0xf9, 0xcc, 0x57, 0x2f, 0xff, 0xfe, 0x3b, 0x00,
-	0x00, 0xff, 0xff, 0xf5, 0x35, 0x4d, 0xfe, 0x20, 0x03, 0x00, 0x00,
+	// 649 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x95, 0x4d, 0x6f, 0xd3, 0x4c,
+	0x10, 0x80, 0x1d, 0xf5, 0x55, 0xf2, 0x76, 0x92, 0xa6, 0xe9, 0x52, 0x38, 0xf8, 0x86, 0x25, 0x44,
+	0x11, 0xaa, 0x1b, 0xbe, 0x84, 0x84, 0xc4, 0x81, 0x34, 0x60, 0x22, 0x40, 0x44, 0xb1, 0xc4, 0xdd,
+	0x49, 0x07, 0xc9, 0xc2, 0xf1, 0xba, 0xf6, 0xa6, 0x6a, 0x7e, 0x16, 0xff, 0x10, 0xed, 0x8e, 0xb3,
+	0x3b, 0xdb, 0xe4, 0x38, 0xcf, 0x3e, 0xb3, 0x9e, 0x4c, 0x66, 0x6c, 0x18, 0x65, 0x55, 0x7e, 0xd5,
+	0x60, 0x7d, 0x87, 0x75, 0x5c, 0xd5, 0x52, 0x49, 0x71, 0x94, 0x55, 0x79, 0x38, 0xd0, 0xb8, 0x6e,
+	0x08, 0xbd, 0xfe, 0x7b, 0x02, 0xbd, 0xef, 0x79, 0xb1, 0xfd, 0x34, 0x9f, 0x89, 0x0b, 0xe8, 0x25,
+	0xa8, 0xae, 0x65, 0xf9, 0x5b, 0x0c, 0xe2, 0xac, 0xca, 0xe3, 0x05, 0xde, 0xea, 0x28, 0x3c, 0x69,
+	0xa3, 0xa6, 0xd2, 0x61, 0x14, 0x88, 0x0f, 0x70, 0xfa, 0x73, 0xa9, 0xb2, 0xbc, 0x9c, 0x66, 0x2a,
+	0x5b, 0x66, 0x0d, 0x36, 0xe2, 0x6c, 0x97, 0x61, 0x51, 0x28, 0x6c, 0x9a, 0x65, 0x51, 0x20, 0x62,
+	0xe8, 0x53, 0xee, 0x17, 0x59, 0xaf, 0x1b, 0xb1, 0xbb, 0xfb, 0xd6, 0x84, 0xe1, 0xd0, 0xe6, 0x98,
+	0x38, 0x0a, 0xc4, 0x47, 0x18, 0x5e, 0xd7, 0x98, 0x29, 0xdc, 0x5d, 0x22, 0x9e, 0xd8, 0xe2, 0x3c,
+	0x1e, 0x9e, 0xed, 0x3d, 0x2f, 0x0a, 0xc4, 0x25, 0x00, 0x69, 0xfa, 0x3e, 0x21, 0xfc, 0x54, 0xcd,
+	0xc2, 0x63, 0x9b, 0x16, 0x05, 0xe2, 0x25, 0x1c, 0xd3, 0xd1, 0x37, 0xdc, 0xba, 0xdf, 0x64, 0x91,
+	0x2f, 0x5f, 0x41, 0x9f, 0x4e, 0x66, 0xe5, 0x0d, 0xde, 0x8b, 0x47, 0xbe, 0x6e, 0xa0, 0x9f, 0xf0,
+	0x0a, 0x06, 0xd3, 0x5a, 0xda, 0xf2, 0xc4, 0xb9, 0x6d, 0x1a, 0xa3, 0x7e, 0xca, 0x0b, 0xf8, 0x5f,
+	0x1f, 0x9a, 0xea, 0x47, 0x5c, 0x3f, 0x58, 0xbb, 0x3e, 0xa0, 0x62, 0xce, 0xb8, 0x7b, 0xa0, 0x94,
+	0x77, 0x30, 0x5c, 0x60, 0x99, 0xad, 0x0f, 0xb4, 0xd5, 0xe7, 0x7e, 0xda, 0x25, 0x00, 0x1d, 0xfb,
+	0xed, 0x74, 0xcc, 0xd7, 0x9f, 0xc1, 0x7f, 0xf3, 0x8d, 0x9a, 0xba, 0x79, 0xd2, 0x11, 0x9b, 0x27,
+	0x1d, 0x92, 0x96, 0x22, 0xd7, 0x52, 0xf4, 0xb4, 0x14, 0x77, 0x5a, 0xe2, 0x69, 0x89, 0xaf, 0x25,
+	0xa4, 0x45, 0x70, 0x34, 0xdf, 0x28, 0xd1, 0x67, 0xcf, 0x0c, 0x07, 0xfc, 0x91, 0xe4, 0xa4, 0xc8,
+	0x9c, 0x14, 0xb9, 0x93, 0x62, 0xeb, 0x24, 0xdc, 0x49, 0x3c, 0x27, 0x31, 0xce, 0x18, 0x20, 0x41,
+	0xf5, 0x0b, 0xeb, 0x26, 0x97, 0xa5, 0xeb, 0x87, 0x63, 0x7b, 0x19, 0x31, 0xf4, 0xbe, 0xe6, 0x8d,
+	0x92, 0xf5, 0x56, 0x9c, 0xee, 0xf4, 0x16, 0x84, 0x23, 0xeb, 0xb6, 0xc4, 0xfc, 0xab, 0xdd, 0x14,
+	0x0b, 0x5c, 0x29, 0x31, 0x74, 0xc5, 0xea, 0x38, 0x3c, 0x65, 0xf5, 0x6a, 0x60, 0x3a, 0xd4, 0x5d,
+	0xe0, 0x5a, 0xde, 0xa1, 0x93, 0x29, 0x7e, 0x38, 0x29, 0xdd, 0x29, 0x16, 0xa8, 0x98, 0x46, 0x31,
+	0xbb, 0x93, 0x00, 0x15, 0x7c, 0x2d, 0xd7, 0x55, 0xb6, 0x52, 0xae, 0xe0, 0x16, 0xb0, 0x82, 0x5b,
+	0x62, 0x16, 0x76, 0xb0, 0xc0, 0xe5, 0x26, 0x2f, 0x6e, 0x68, 0x12, 0xcf, 0x5d, 0x25, 0x8e, 0x86,
+	0x8f, 0x6d, 0x26, 0xc7, 0xb4, 0xef, 0x09, 0x2a, 0x13, 0xa5, 0x2a, 0x53, 0x9b, 0xc6, 0xed, 0x15,
+	0x83, 0xe1, 0xb9, 0xcd, 0x67, 0x34, 0x0a, 0xc4, 0x7b, 0x18, 0x24, 0xa8, 0xf4, 0x24, 0x6a, 0xc4,
+	0xde, 0x4b, 0x16, 0xb1, 0xf7, 0x92, 0x65, 0x51, 0x20, 0xde, 0xc2, 0xf1, 0x42, 0xaa, 0x87, 0x9b,
+	0x6f, 0x11, 0xcb, 0xb2, 0x8c, 0x5e, 0x01, 0x29, 0xaa, 0x74, 0x5b, 0xae, 0x7e, 0xc8, 0x1b, 0x74,
+	0xa5, 0x32, 0xb8, 0xd7, 0xfa, 0x49, 0xb6, 0xfa, 0xb3, 0xa9, 0x5c, 0xeb, 0x29, 0x66, 0xad, 0x27,
+	0x60, 0xb6, 0xad, 0xb7, 0x40, 0x3d, 0x08, 0xe8, 0x5a, 0xdf, 0x82, 0xc3, 0x7a, 0xf7, 0xf3, 0x7d,
+	0x25, 0x6b, 0x36, 0x2a, 0x14, 0x33, 0x99, 0x40, 0x14, 0x8c, 0x3b, 0xe2, 0x0a, 0xba, 0xb3, 0xb5,
+	0xaf, 0xcf, 0xd6, 0x0f, 0x74, 0x02, 0x51, 0x70, 0xd1, 0x19, 0x77, 0x74, 0x39, 0xf3, 0x8d, 0x9a,
+	0x14, 0x72, 0xe9, 0xca, 0x69, 0x01, 0xdb, 0x42, 0x1d, 0xea, 0x04, 0xad, 0x27, 0x48, 0xba, 0xdd,
+	0x58, 0xe3, 0x8e, 0xf8, 0x4a, 0x90, 0x3e, 0xee, 0x88, 0xe7, 0x00, 0x34, 0xab, 0x07, 0x32, 0x78,
+	0x0b, 0x27, 0x4f, 0x41, 0xac, 0xca, 0x38, 0x5b, 0x62, 0x9d, 0xaf, 0xe2, 0x22, 0x2f, 0xb6, 0xfa,
+	0x70, 0xd2, 0x4f, 0xcd, 0xa7, 0x6e, 0xae, 0x3f, 0x6b, 0xcb, 0xae, 0xf9, 0xba, 0xbd, 0xf9, 0x37,
+	0x00, 0x67, 0xe1, 0x22, 0x7c, 0x04, 0x07, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -73,10 +91,20 @@ type LilyAPIClient interface {
 	CreateDatabase(ctx context.Context, in *ReqCreateDatabase, opts ...grpc.CallOption) (*RespDatabase, error)
 	// CreateForm 创建表
 	CreateForm(ctx context.Context, in *ReqCreateForm, opts ...grpc.CallOption) (*Resp, error)
-	// CreateKey 新建主键
+	// CreateKey 新建唯一索引，写入数据的索引值与其它主键的记录重复时返回 AlreadyExists
 	CreateKey(ctx context.Context, in *ReqCreateKey, opts ...grpc.CallOption) (*Resp, error)
 	// CreateIndex 新建索引
 	CreateIndex(ctx context.Context, in *ReqCreateIndex, opts ...grpc.CallOption) (*Resp, error)
+	// DropDatabase 删除库
+	DropDatabase(ctx context.Context, in *ReqDropDatabase, opts ...grpc.CallOption) (*Resp, error)
+	// DropForm 删除表
+	DropForm(ctx context.Context, in *ReqDropForm, opts ...grpc.CallOption) (*Resp, error)
+	// DropIndex 删除索引
+	DropIndex(ctx context.Context, in *ReqDropIndex, opts ...grpc.CallOption) (*Resp, error)
+	// RenameDatabase 重命名库
+	RenameDatabase(ctx context.Context, in *ReqRenameDatabase, opts ...grpc.CallOption) (*Resp, error)
+	// RenameForm 重命名表
+	RenameForm(ctx context.Context, in *ReqRenameForm, opts ...grpc.CallOption) (*Resp, error)
 	// PutD 新增数据
 	PutD(ctx context.Context, in *ReqPutD, opts ...grpc.CallOption) (*RespPutD, error)
 	// SetD 新增数据
@@ -89,12 +117,42 @@ type LilyAPIClient interface {
 	Set(ctx context.Context, in *ReqSet, opts ...grpc.CallOption) (*RespSet, error)
 	// Get 获取数据
 	Get(ctx context.Context, in *ReqGet, opts ...grpc.CallOption) (*RespGet, error)
+	// GetVersion 获取数据指定版本
+	GetVersion(ctx context.Context, in *ReqGetVersion, opts ...grpc.CallOption) (*RespGet, error)
+	// History 获取数据历史版本
+	History(ctx context.Context, in *ReqHistory, opts ...grpc.CallOption) (*RespHistory, error)
 	// Select 获取数据
 	Select(ctx context.Context, in *ReqSelect, opts ...grpc.CallOption) (*RespSelect, error)
 	// Remove 删除数据
 	Remove(ctx context.Context, in *ReqRemove, opts ...grpc.CallOption) (*Resp, error)
 	// Delete 删除数据
 	Delete(ctx context.Context, in *ReqDelete, opts ...grpc.CallOption) (*RespDelete, error)
+	// Compact 压缩表数据文件
+	Compact(ctx context.Context, in *ReqCompact, opts ...grpc.CallOption) (*RespCompact, error)
+	// RebuildIndex 重建索引
+	RebuildIndex(ctx context.Context, in *ReqRebuildIndex, opts ...grpc.CallOption) (*RespRebuildIndex, error)
+	// GetIndexStatus 获取索引构建状态
+	GetIndexStatus(ctx context.Context, in *ReqIndexStatus, opts ...grpc.CallOption) (*RespIndexStatus, error)
+	// GetFormStats 获取表统计信息
+	GetFormStats(ctx context.Context, in *ReqFormStats, opts ...grpc.CallOption) (*RespFormStats, error)
+	// RotateKey 轮换数据密钥并重新加密库数据
+	RotateKey(ctx context.Context, in *ReqRotateKey, opts ...grpc.CallOption) (*RespRotateKey, error)
+	// SetSyncMode 设置数据库落盘策略
+	SetSyncMode(ctx context.Context, in *ReqSetSyncMode, opts ...grpc.CallOption) (*Resp, error)
+	// Backup 备份库
+	Backup(ctx context.Context, in *ReqBackup, opts ...grpc.CallOption) (*RespBackup, error)
+	// Restore 依据归档文件恢复库
+	Restore(ctx context.Context, in *ReqRestore, opts ...grpc.CallOption) (*RespBackup, error)
+	// Export 导出表数据
+	Export(ctx context.Context, in *ReqExport, opts ...grpc.CallOption) (LilyAPI_ExportClient, error)
+	// Import 导入表数据
+	Import(ctx context.Context, opts ...grpc.CallOption) (LilyAPI_ImportClient, error)
+	// PutBlob 分块存储大数据
+	PutBlob(ctx context.Context, opts ...grpc.CallOption) (LilyAPI_PutBlobClient, error)
+	// GetBlob 读取大数据
+	GetBlob(ctx context.Context, in *ReqBlob, opts ...grpc.CallOption) (LilyAPI_GetBlobClient, error)
+	// RemoveBlob 删除大数据
+	RemoveBlob(ctx context.Context, in *ReqBlob, opts ...grpc.CallOption) (*Resp, error)
 }
 
 type lilyAPIClient struct {
@@ -168,6 +226,51 @@ func (c *lilyAPIClient) CreateIndex(ctx context.Context, in *ReqCreateIndex, opt
 	return out, nil
 }
 
+func (c *lilyAPIClient) DropDatabase(ctx context.Context, in *ReqDropDatabase, opts ...grpc.CallOption) (*Resp, error) {
+	out := new(Resp)
+	err := c.cc.Invoke(ctx, "/api.LilyAPI/DropDatabase", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lilyAPIClient) DropForm(ctx context.Context, in *ReqDropForm, opts ...grpc.CallOption) (*Resp, error) {
+	out := new(Resp)
+	err := c.cc.Invoke(ctx, "/api.LilyAPI/DropForm", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lilyAPIClient) DropIndex(ctx context.Context, in *ReqDropIndex, opts ...grpc.CallOption) (*Resp, error) {
+	out := new(Resp)
+	err := c.cc.Invoke(ctx, "/api.LilyAPI/DropIndex", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lilyAPIClient) RenameDatabase(ctx context.Context, in *ReqRenameDatabase, opts ...grpc.CallOption) (*Resp, error) {
+	out := new(Resp)
+	err := c.cc.Invoke(ctx, "/api.LilyAPI/RenameDatabase", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lilyAPIClient) RenameForm(ctx context.Context, in *ReqRenameForm, opts ...grpc.CallOption) (*Resp, error) {
+	out := new(Resp)
+	err := c.cc.Invoke(ctx, "/api.LilyAPI/RenameForm", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lilyAPIClient) PutD(ctx context.Context, in *ReqPutD, opts ...grpc.CallOption) (*RespPutD, error) {
 	out := new(RespPutD)
 	err := c.cc.Invoke(ctx, "/api.LilyAPI/PutD", in, out, opts...)
@@ -222,6 +325,24 @@ func (c *lilyAPIClient) Get(ctx context.Context, in *ReqGet, opts ...grpc.CallOp
 	return out, nil
 }
 
+func (c *lilyAPIClient) GetVersion(ctx context.Context, in *ReqGetVersion, opts ...grpc.CallOption) (*RespGet, error) {
+	out := new(RespGet)
+	err := c.cc.Invoke(ctx, "/api.LilyAPI/GetVersion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lilyAPIClient) History(ctx context.Context, in *ReqHistory, opts ...grpc.CallOption) (*RespHistory, error) {
+	out := new(RespHistory)
+	err := c.cc.Invoke(ctx, "/api.LilyAPI/History", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lilyAPIClient) Select(ctx context.Context, in *ReqSelect, opts ...grpc.CallOption) (*RespSelect, error) {
 	out := new(RespSelect)
 	err := c.cc.Invoke(ctx, "/api.LilyAPI/Select", in, out, opts...)
@@ -249,6 +370,216 @@ func (c *lilyAPIClient) Delete(ctx context.Context, in *ReqDelete, opts ...grpc.
 	return out, nil
 }
 
+func (c *lilyAPIClient) Compact(ctx context.Context, in *ReqCompact, opts ...grpc.CallOption) (*RespCompact, error) {
+	out := new(RespCompact)
+	err := c.cc.Invoke(ctx, "/api.LilyAPI/Compact", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lilyAPIClient) RebuildIndex(ctx context.Context, in *ReqRebuildIndex, opts ...grpc.CallOption) (*RespRebuildIndex, error) {
+	out := new(RespRebuildIndex)
+	err := c.cc.Invoke(ctx, "/api.LilyAPI/RebuildIndex", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lilyAPIClient) GetIndexStatus(ctx context.Context, in *ReqIndexStatus, opts ...grpc.CallOption) (*RespIndexStatus, error) {
+	out := new(RespIndexStatus)
+	err := c.cc.Invoke(ctx, "/api.LilyAPI/GetIndexStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lilyAPIClient) GetFormStats(ctx context.Context, in *ReqFormStats, opts ...grpc.CallOption) (*RespFormStats, error) {
+	out := new(RespFormStats)
+	err := c.cc.Invoke(ctx, "/api.LilyAPI/GetFormStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lilyAPIClient) RotateKey(ctx context.Context, in *ReqRotateKey, opts ...grpc.CallOption) (*RespRotateKey, error) {
+	out := new(RespRotateKey)
+	err := c.cc.Invoke(ctx, "/api.LilyAPI/RotateKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lilyAPIClient) SetSyncMode(ctx context.Context, in *ReqSetSyncMode, opts ...grpc.CallOption) (*Resp, error) {
+	out := new(Resp)
+	err := c.cc.Invoke(ctx, "/api.LilyAPI/SetSyncMode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lilyAPIClient) Backup(ctx context.Context, in *ReqBackup, opts ...grpc.CallOption) (*RespBackup, error) {
+	out := new(RespBackup)
+	err := c.cc.Invoke(ctx, "/api.LilyAPI/Backup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lilyAPIClient) Restore(ctx context.Context, in *ReqRestore, opts ...grpc.CallOption) (*RespBackup, error) {
+	out := new(RespBackup)
+	err := c.cc.Invoke(ctx, "/api.LilyAPI/Restore", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lilyAPIClient) Export(ctx context.Context, in *ReqExport, opts ...grpc.CallOption) (LilyAPI_ExportClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_LilyAPI_serviceDesc.Streams[0], "/api.LilyAPI/Export", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lilyAPIExportClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LilyAPI_ExportClient interface {
+	Recv() (*RespExport, error)
+	grpc.ClientStream
+}
+
+type lilyAPIExportClient struct {
+	grpc.ClientStream
+}
+
+func (x *lilyAPIExportClient) Recv() (*RespExport, error) {
+	m := new(RespExport)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lilyAPIClient) Import(ctx context.Context, opts ...grpc.CallOption) (LilyAPI_ImportClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_LilyAPI_serviceDesc.Streams[1], "/api.LilyAPI/Import", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lilyAPIImportClient{stream}
+	return x, nil
+}
+
+type LilyAPI_ImportClient interface {
+	Send(*ReqImport) error
+	Recv() (*RespImport, error)
+	grpc.ClientStream
+}
+
+type lilyAPIImportClient struct {
+	grpc.ClientStream
+}
+
+func (x *lilyAPIImportClient) Send(m *ReqImport) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *lilyAPIImportClient) Recv() (*RespImport, error) {
+	m := new(RespImport)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lilyAPIClient) PutBlob(ctx context.Context, opts ...grpc.CallOption) (LilyAPI_PutBlobClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_LilyAPI_serviceDesc.Streams[2], "/api.LilyAPI/PutBlob", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lilyAPIPutBlobClient{stream}
+	return x, nil
+}
+
+type LilyAPI_PutBlobClient interface {
+	Send(*ReqPutBlob) error
+	CloseAndRecv() (*RespBlob, error)
+	grpc.ClientStream
+}
+
+type lilyAPIPutBlobClient struct {
+	grpc.ClientStream
+}
+
+func (x *lilyAPIPutBlobClient) Send(m *ReqPutBlob) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *lilyAPIPutBlobClient) CloseAndRecv() (*RespBlob, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(RespBlob)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lilyAPIClient) GetBlob(ctx context.Context, in *ReqBlob, opts ...grpc.CallOption) (LilyAPI_GetBlobClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_LilyAPI_serviceDesc.Streams[3], "/api.LilyAPI/GetBlob", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lilyAPIGetBlobClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LilyAPI_GetBlobClient interface {
+	Recv() (*RespGetBlob, error)
+	grpc.ClientStream
+}
+
+type lilyAPIGetBlobClient struct {
+	grpc.ClientStream
+}
+
+func (x *lilyAPIGetBlobClient) Recv() (*RespGetBlob, error) {
+	m := new(RespGetBlob)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lilyAPIClient) RemoveBlob(ctx context.Context, in *ReqBlob, opts ...grpc.CallOption) (*Resp, error) {
+	out := new(Resp)
+	err := c.cc.Invoke(ctx, "/api.LilyAPI/RemoveBlob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LilyAPIServer is the server API for LilyAPI service.
 type LilyAPIServer interface {
 	// GetConf 获取数据库引擎对象
@@ -261,10 +592,20 @@ type LilyAPIServer interface {
 	CreateDatabase(context.Context, *ReqCreateDatabase) (*RespDatabase, error)
 	// CreateForm 创建表
 	CreateForm(context.Context, *ReqCreateForm) (*Resp, error)
-	// CreateKey 新建主键
+	// CreateKey 新建唯一索引，写入数据的索引值与其它主键的记录重复时返回 AlreadyExists
 	CreateKey(context.Context, *ReqCreateKey) (*Resp, error)
 	// CreateIndex 新建索引
 	CreateIndex(context.Context, *ReqCreateIndex) (*Resp, error)
+	// DropDatabase 删除库
+	DropDatabase(context.Context, *ReqDropDatabase) (*Resp, error)
+	// DropForm 删除表
+	DropForm(context.Context, *ReqDropForm) (*Resp, error)
+	// DropIndex 删除索引
+	DropIndex(context.Context, *ReqDropIndex) (*Resp, error)
+	// RenameDatabase 重命名库
+	RenameDatabase(context.Context, *ReqRenameDatabase) (*Resp, error)
+	// RenameForm 重命名表
+	RenameForm(context.Context, *ReqRenameForm) (*Resp, error)
 	// PutD 新增数据
 	PutD(context.Context, *ReqPutD) (*RespPutD, error)
 	// SetD 新增数据
@@ -277,12 +618,42 @@ type LilyAPIServer interface {
 	Set(context.Context, *ReqSet) (*RespSet, error)
 	// Get 获取数据
 	Get(context.Context, *ReqGet) (*RespGet, error)
+	// GetVersion 获取数据指定版本
+	GetVersion(context.Context, *ReqGetVersion) (*RespGet, error)
+	// History 获取数据历史版本
+	History(context.Context, *ReqHistory) (*RespHistory, error)
 	// Select 获取数据
 	Select(context.Context, *ReqSelect) (*RespSelect, error)
 	// Remove 删除数据
 	Remove(context.Context, *ReqRemove) (*Resp, error)
 	// Delete 删除数据
 	Delete(context.Context, *ReqDelete) (*RespDelete, error)
+	// Compact 压缩表数据文件
+	Compact(context.Context, *ReqCompact) (*RespCompact, error)
+	// RebuildIndex 重建索引
+	RebuildIndex(context.Context, *ReqRebuildIndex) (*RespRebuildIndex, error)
+	// GetIndexStatus 获取索引构建状态
+	GetIndexStatus(context.Context, *ReqIndexStatus) (*RespIndexStatus, error)
+	// GetFormStats 获取表统计信息
+	GetFormStats(context.Context, *ReqFormStats) (*RespFormStats, error)
+	// RotateKey 轮换数据密钥并重新加密库数据
+	RotateKey(context.Context, *ReqRotateKey) (*RespRotateKey, error)
+	// SetSyncMode 设置数据库落盘策略
+	SetSyncMode(context.Context, *ReqSetSyncMode) (*Resp, error)
+	// Backup 备份库
+	Backup(context.Context, *ReqBackup) (*RespBackup, error)
+	// Restore 依据归档文件恢复库
+	Restore(context.Context, *ReqRestore) (*RespBackup, error)
+	// Export 导出表数据
+	Export(*ReqExport, LilyAPI_ExportServer) error
+	// Import 导入表数据
+	Import(LilyAPI_ImportServer) error
+	// PutBlob 分块存储大数据
+	PutBlob(LilyAPI_PutBlobServer) error
+	// GetBlob 读取大数据
+	GetBlob(*ReqBlob, LilyAPI_GetBlobServer) error
+	// RemoveBlob 删除大数据
+	RemoveBlob(context.Context, *ReqBlob) (*Resp, error)
 }
 
 func RegisterLilyAPIServer(s *grpc.Server, srv LilyAPIServer) {
@@ -415,6 +786,96 @@ func _LilyAPI_CreateIndex_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LilyAPI_DropDatabase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReqDropDatabase)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LilyAPIServer).DropDatabase(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.LilyAPI/DropDatabase",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LilyAPIServer).DropDatabase(ctx, req.(*ReqDropDatabase))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LilyAPI_DropForm_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReqDropForm)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LilyAPIServer).DropForm(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.LilyAPI/DropForm",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LilyAPIServer).DropForm(ctx, req.(*ReqDropForm))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LilyAPI_DropIndex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReqDropIndex)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LilyAPIServer).DropIndex(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.LilyAPI/DropIndex",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LilyAPIServer).DropIndex(ctx, req.(*ReqDropIndex))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LilyAPI_RenameDatabase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReqRenameDatabase)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LilyAPIServer).RenameDatabase(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.LilyAPI/RenameDatabase",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LilyAPIServer).RenameDatabase(ctx, req.(*ReqRenameDatabase))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LilyAPI_RenameForm_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReqRenameForm)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LilyAPIServer).RenameForm(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.LilyAPI/RenameForm",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LilyAPIServer).RenameForm(ctx, req.(*ReqRenameForm))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _LilyAPI_PutD_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ReqPutD)
 	if err := dec(in); err != nil {
@@ -523,6 +984,42 @@ func _LilyAPI_Get_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LilyAPI_GetVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReqGetVersion)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LilyAPIServer).GetVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.LilyAPI/GetVersion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LilyAPIServer).GetVersion(ctx, req.(*ReqGetVersion))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LilyAPI_History_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReqHistory)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LilyAPIServer).History(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.LilyAPI/History",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LilyAPIServer).History(ctx, req.(*ReqHistory))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _LilyAPI_Select_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ReqSelect)
 	if err := dec(in); err != nil {
@@ -577,6 +1074,262 @@ func _LilyAPI_Delete_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LilyAPI_Compact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReqCompact)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LilyAPIServer).Compact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.LilyAPI/Compact",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LilyAPIServer).Compact(ctx, req.(*ReqCompact))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LilyAPI_RebuildIndex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReqRebuildIndex)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LilyAPIServer).RebuildIndex(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.LilyAPI/RebuildIndex",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LilyAPIServer).RebuildIndex(ctx, req.(*ReqRebuildIndex))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LilyAPI_GetIndexStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReqIndexStatus)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LilyAPIServer).GetIndexStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.LilyAPI/GetIndexStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LilyAPIServer).GetIndexStatus(ctx, req.(*ReqIndexStatus))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LilyAPI_GetFormStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReqFormStats)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LilyAPIServer).GetFormStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.LilyAPI/GetFormStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LilyAPIServer).GetFormStats(ctx, req.(*ReqFormStats))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LilyAPI_RotateKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReqRotateKey)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LilyAPIServer).RotateKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.LilyAPI/RotateKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LilyAPIServer).RotateKey(ctx, req.(*ReqRotateKey))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LilyAPI_SetSyncMode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReqSetSyncMode)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LilyAPIServer).SetSyncMode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.LilyAPI/SetSyncMode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LilyAPIServer).SetSyncMode(ctx, req.(*ReqSetSyncMode))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LilyAPI_Backup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReqBackup)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LilyAPIServer).Backup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.LilyAPI/Backup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LilyAPIServer).Backup(ctx, req.(*ReqBackup))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LilyAPI_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReqRestore)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LilyAPIServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.LilyAPI/Restore",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LilyAPIServer).Restore(ctx, req.(*ReqRestore))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LilyAPI_Export_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReqExport)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LilyAPIServer).Export(m, &lilyAPIExportServer{stream})
+}
+
+type LilyAPI_ExportServer interface {
+	Send(*RespExport) error
+	grpc.ServerStream
+}
+
+type lilyAPIExportServer struct {
+	grpc.ServerStream
+}
+
+func (x *lilyAPIExportServer) Send(m *RespExport) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _LilyAPI_Import_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LilyAPIServer).Import(&lilyAPIImportServer{stream})
+}
+
+type LilyAPI_ImportServer interface {
+	Send(*RespImport) error
+	Recv() (*ReqImport, error)
+	grpc.ServerStream
+}
+
+type lilyAPIImportServer struct {
+	grpc.ServerStream
+}
+
+func (x *lilyAPIImportServer) Send(m *RespImport) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *lilyAPIImportServer) Recv() (*ReqImport, error) {
+	m := new(ReqImport)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _LilyAPI_PutBlob_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LilyAPIServer).PutBlob(&lilyAPIPutBlobServer{stream})
+}
+
+type LilyAPI_PutBlobServer interface {
+	SendAndClose(*RespBlob) error
+	Recv() (*ReqPutBlob, error)
+	grpc.ServerStream
+}
+
+type lilyAPIPutBlobServer struct {
+	grpc.ServerStream
+}
+
+func (x *lilyAPIPutBlobServer) SendAndClose(m *RespBlob) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *lilyAPIPutBlobServer) Recv() (*ReqPutBlob, error) {
+	m := new(ReqPutBlob)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _LilyAPI_GetBlob_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReqBlob)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LilyAPIServer).GetBlob(m, &lilyAPIGetBlobServer{stream})
+}
+
+type LilyAPI_GetBlobServer interface {
+	Send(*RespGetBlob) error
+	grpc.ServerStream
+}
+
+type lilyAPIGetBlobServer struct {
+	grpc.ServerStream
+}
+
+func (x *lilyAPIGetBlobServer) Send(m *RespGetBlob) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _LilyAPI_RemoveBlob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReqBlob)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LilyAPIServer).RemoveBlob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.LilyAPI/RemoveBlob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LilyAPIServer).RemoveBlob(ctx, req.(*ReqBlob))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _LilyAPI_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "api.LilyAPI",
 	HandlerType: (*LilyAPIServer)(nil),
@@ -609,6 +1362,26 @@ var _LilyAPI_serviceDesc = grpc.ServiceDesc{
 			MethodName: "CreateIndex",
 			Handler:    _LilyAPI_CreateIndex_Handler,
 		},
+		{
+			MethodName: "DropDatabase",
+			Handler:    _LilyAPI_DropDatabase_Handler,
+		},
+		{
+			MethodName: "DropForm",
+			Handler:    _LilyAPI_DropForm_Handler,
+		},
+		{
+			MethodName: "DropIndex",
+			Handler:    _LilyAPI_DropIndex_Handler,
+		},
+		{
+			MethodName: "RenameDatabase",
+			Handler:    _LilyAPI_RenameDatabase_Handler,
+		},
+		{
+			MethodName: "RenameForm",
+			Handler:    _LilyAPI_RenameForm_Handler,
+		},
 		{
 			MethodName: "PutD",
 			Handler:    _LilyAPI_PutD_Handler,
@@ -633,6 +1406,14 @@ var _LilyAPI_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Get",
 			Handler:    _LilyAPI_Get_Handler,
 		},
+		{
+			MethodName: "GetVersion",
+			Handler:    _LilyAPI_GetVersion_Handler,
+		},
+		{
+			MethodName: "History",
+			Handler:    _LilyAPI_History_Handler,
+		},
 		{
 			MethodName: "Select",
 			Handler:    _LilyAPI_Select_Handler,
@@ -645,7 +1426,65 @@ var _LilyAPI_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Delete",
 			Handler:    _LilyAPI_Delete_Handler,
 		},
+		{
+			MethodName: "Compact",
+			Handler:    _LilyAPI_Compact_Handler,
+		},
+		{
+			MethodName: "RebuildIndex",
+			Handler:    _LilyAPI_RebuildIndex_Handler,
+		},
+		{
+			MethodName: "GetIndexStatus",
+			Handler:    _LilyAPI_GetIndexStatus_Handler,
+		},
+		{
+			MethodName: "GetFormStats",
+			Handler:    _LilyAPI_GetFormStats_Handler,
+		},
+		{
+			MethodName: "RotateKey",
+			Handler:    _LilyAPI_RotateKey_Handler,
+		},
+		{
+			MethodName: "SetSyncMode",
+			Handler:    _LilyAPI_SetSyncMode_Handler,
+		},
+		{
+			MethodName: "Backup",
+			Handler:    _LilyAPI_Backup_Handler,
+		},
+		{
+			MethodName: "Restore",
+			Handler:    _LilyAPI_Restore_Handler,
+		},
+		{
+			MethodName: "RemoveBlob",
+			Handler:    _LilyAPI_RemoveBlob_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Export",
+			Handler:       _LilyAPI_Export_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Import",
+			Handler:       _LilyAPI_Import_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "PutBlob",
+			Handler:       _LilyAPI_PutBlob_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "GetBlob",
+			Handler:       _LilyAPI_GetBlob_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/server.proto",
 }
@@ -0,0 +1,183 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"errors"
+	"github.com/aberic/gnomon/log"
+	"strings"
+)
+
+const (
+	// IndexBuilding 索引正在依据表中已有数据回填
+	IndexBuilding = "building"
+	// IndexReady 索引已构建完成
+	IndexReady = "ready"
+	// IndexFailed 索引回填失败，如已有数据违反唯一索引，需删除后重新新建
+	IndexFailed = "failed"
+	// backfillBatch 回填时每批次处理的记录数，每批次持有一次表写锁
+	backfillBatch = 1000
+)
+
+// errIndexDetached 回填期间索引、所属表或库已被删除
+var errIndexDetached = errors.New("index has been dropped")
+
+// IndexStatus 索引构建状态
+type IndexStatus struct {
+	State string // State 构建状态，IndexBuilding、IndexReady 或 IndexFailed
+	Total int64  // Total 待回填的记录数
+	Done  int64  // Done 已回填的记录数
+	Err   string // Err 构建失败原因
+}
+
+// backfillLinks 获取回填来源的表主键索引全部链表，表无主键索引时以自增ID索引作为回填来源，调用方持有表锁
+func backfillLinks(form Form) []Link {
+	if form.getEngine() != EngineFile {
+		return nil
+	}
+	var source Index
+	for _, index := range form.getIndexes() {
+		if index.getKeyStructure() == indexDefaultID {
+			source = index
+			break
+		}
+		if index.getKeyStructure() == indexAutoID {
+			source = index
+		}
+	}
+	var links []Link
+	if nil != source {
+		rangeLinks(source.getNode(), func(ln Link) {
+			links = append(links, ln)
+		})
+	}
+	return links
+}
+
+// backfill 依据表主键索引链表回填新建索引，完成后索引方可用于检索
+//
+// links 为索引加入表时的主键索引链表，此后写入的数据已由写入流程记录
+//
+// 回填分批持有表写锁，读取主键索引当前指向的记录并写入新索引，与并发写入互斥，完成后自 lily.sync 中清除构建标记
+func (d *database) backfill(form Form, idx Index, links []Link) {
+	status := &IndexStatus{State: IndexBuilding, Total: int64(len(links))}
+	idx.setStatus(status)
+	for start := 0; start < len(links); start += backfillBatch {
+		end := start + backfillBatch
+		if end > len(links) {
+			end = len(links)
+		}
+		if err := d.backfillBatch(form, idx, links[start:end]); nil != err {
+			if errIndexDetached == err {
+				log.Info("backfill index stopped", log.Field("form", form.getName()), log.Field("index", idx.getKeyStructure()), log.Err(err))
+				return
+			}
+			log.Error("backfill index failed", log.Field("form", form.getName()), log.Field("index", idx.getKeyStructure()), log.Err(err))
+			status.State, status.Err = IndexFailed, err.Error()
+			idx.setStatus(status)
+			return
+		}
+		status.Done = int64(end)
+		idx.setStatus(status)
+	}
+	// 先清除 lily.sync 中的构建标记再开放检索
+	d.lily.lock.Lock()
+	if dv := d.lily.lilyData.Databases[d.getName()]; nil != dv {
+		if fv := dv.Forms[form.getName()]; nil != fv && nil != fv.Indexes[idx.getID()] {
+			fv.Indexes[idx.getID()].Building = false
+			d.lily.storeRPC()
+		}
+	}
+	d.lily.lock.Unlock()
+	status.State = IndexReady
+	idx.setStatus(status)
+	log.Info("backfill index", log.Field("form", form.getName()), log.Field("index", idx.getKeyStructure()), log.Field("records", status.Total))
+}
+
+// backfillBatch 回填一批主键索引链表指向的记录，期间持有表写锁
+//
+// 已失效或已过期的记录、不再是主键当前记录的旧记录以及缺少索引字段的记录不写入索引，唯一索引中已存在其它主键的相同索引值时返回 *UniqueViolationError
+func (d *database) backfillBatch(form Form, idx Index, links []Link) error {
+	defer form.unLock()
+	form.lock()
+	if !d.attached(form, idx) {
+		return errIndexDetached
+	}
+	unique := map[string]Index{idx.getID(): idx}
+	for _, ln := range links {
+		if ln.getSeekStartIndex() == -1 {
+			continue
+		}
+		rs := ln.get()
+		if nil != rs.err || !currentLink(form, rs.key, ln) { // 快照后已更新或删除的记录，当前记录已由写入流程写入索引
+			continue
+		}
+		customKeys, err := customIndexKeys(idx, rs.value)
+		if nil != err {
+			continue
+		}
+		if idx.isUnique() {
+			if err = d.checkUnique(rs.key, unique, rs.value); nil != err {
+				return err
+			}
+		}
+		for _, ck := range customKeys {
+			ib := idx.put(postingKey(idx, ck.key, rs.key), ck.hashKey, true)
+			if nil != ib.getErr() {
+				return ib.getErr()
+			}
+			wr := store().storeIndex(ib, &writeResult{segment: ln.getSegment(), seekStart: ln.getSeekStart(), seekLast: ln.getSeekLast()}, d.getSyncMode())
+			if nil != wr.err {
+				return wr.err
+			}
+		}
+	}
+	return nil
+}
+
+// attached 索引及其所属表均未被删除，调用方持有表写锁
+//
+// 删除库时库中全部表均被标记为已删除
+func (d *database) attached(form Form, idx Index) bool {
+	return !form.isDropped() && form.getIndexes()[idx.getID()] == idx
+}
+
+// resumeBackfill 重新回填重启或恢复前未完成回填的索引，索引文件已在恢复表时清空
+func (d *database) resumeBackfill() {
+	for _, form := range d.getForms() {
+		for _, idx := range form.getIndexes() {
+			if idx.getStatus().State == IndexBuilding {
+				form.rLock()
+				links := backfillLinks(form)
+				form.rUnLock()
+				go d.backfill(form, idx, links)
+			}
+		}
+	}
+}
+
+// indexStatus 获取索引构建状态
+func (d *database) indexStatus(formName, keyStructure string) (*IndexStatus, error) {
+	form := d.getForms()[formName]
+	if nil == form {
+		return nil, formIsInvalid(formName)
+	}
+	for _, idx := range form.getIndexes() {
+		if idx.getKeyStructure() == keyStructure {
+			return idx.getStatus(), nil
+		}
+	}
+	return nil, errors.New(strings.Join([]string{"index", keyStructure, "not found"}, " "))
+}
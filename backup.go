@@ -0,0 +1,486 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"github.com/aberic/gnomon"
+	"github.com/aberic/gnomon/log"
+	"github.com/aberic/lily/api"
+	"github.com/aberic/lily/connector"
+	"github.com/golang/protobuf/proto"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	sort2 "sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// backupBootstrap 归档中库表元数据的文件名，内容格式与 lily.sync 一致，代数为0
+	backupBootstrap = "lily.sync"
+	// restoreDir 恢复时解压归档的临时目录名，位于数据目录下
+	restoreDir = "restore.tmp"
+)
+
+// ErrBackupInvalid 归档文件格式错误或内容不完整
+var ErrBackupInvalid = errors.New("backup archive is invalid")
+
+// BackupResult 备份或恢复结果
+type BackupResult struct {
+	Databases int64 // Databases 库数
+	Forms     int64 // Forms 表数
+	Files     int64 // Files 数据分段文件及索引文件数
+	Size      int64 // Size 数据分段文件及索引文件总大小
+}
+
+// formSnapshot 表快照
+//
+// 数据分段文件仅追加写入，快照时刻文件长度以内的内容不再变化；压缩替换文件后，已打开的句柄仍可读取原文件
+type formSnapshot struct {
+	dataID   string            // 所属库ID
+	formID   string            // 表ID
+	segments []uint32          // 数据分段文件序号
+	files    []*os.File        // 快照时刻打开的数据分段文件
+	sizes    []int64           // 快照时刻数据分段文件长度
+	indexes  map[string][]byte // 索引ID对应由内存索引树生成的索引文件内容
+}
+
+// snapshotForms 同时持有待备份全部表的写锁，逐表生成快照，各表快照处于同一时刻
+//
+// 表按库名及表名顺序生成快照，按 holdForms 顺序加锁，写入仅在快照期间等待，默认存储引擎以外的表仅计数
+func snapshotForms(meta *api.Lily, dbs map[string]Database, result *BackupResult) ([]*formSnapshot, error) {
+	var (
+		dbNames []string
+		forms   []Form
+		fvs     []*api.Form
+	)
+	for name := range meta.Databases {
+		dbNames = append(dbNames, name)
+	}
+	sort2.Strings(dbNames)
+	for _, name := range dbNames {
+		dv := meta.Databases[name]
+		result.Databases++
+		var formNames []string
+		for formName := range dv.Forms {
+			formNames = append(formNames, formName)
+		}
+		sort2.Strings(formNames)
+		for _, formName := range formNames {
+			form := dbs[name].getForms()[formName]
+			if nil == form {
+				continue
+			}
+			result.Forms++
+			if form.getEngine() != EngineFile {
+				continue
+			}
+			forms = append(forms, form)
+			fvs = append(fvs, dv.Forms[formName])
+		}
+	}
+	defer holdForms(forms, func(form Form) func() {
+		form.lock()
+		return form.unLock
+	})()
+	for _, form := range forms {
+		if form.isDropped() {
+			return nil, ErrFormDropped
+		}
+	}
+	var snaps []*formSnapshot
+	for i, form := range forms {
+		snap, err := snapshotForm(form.getDatabase().getID(), form, fvs[i])
+		if nil != err {
+			for _, sn := range snaps {
+				sn.close()
+			}
+			return nil, err
+		}
+		snaps = append(snaps, snap)
+	}
+	return snaps, nil
+}
+
+// snapshotForm 生成表快照，调用方持有表写锁
+func snapshotForm(dataID string, form Form, fv *api.Form) (*formSnapshot, error) {
+	snap := &formSnapshot{dataID: dataID, formID: form.getID(), indexes: map[string][]byte{}}
+	for _, segment := range formSegments(dataID, form.getID()) {
+		file, err := os.Open(pathFormDataFile(dataID, form.getID(), segment))
+		if nil != err {
+			snap.close()
+			return nil, err
+		}
+		info, err := file.Stat()
+		if nil != err {
+			_ = file.Close()
+			snap.close()
+			return nil, err
+		}
+		snap.segments = append(snap.segments, segment)
+		snap.files = append(snap.files, file)
+		snap.sizes = append(snap.sizes, info.Size())
+	}
+	for indexID := range fv.Indexes {
+		idx := form.getIndexes()[indexID]
+		if nil == idx {
+			continue
+		}
+		buf := newIndexBuffer()
+		rangeLinks(idx.getNode(), func(ln Link) {
+			if ln.getSeekStartIndex() == -1 { // 索引从未成功落盘
+				return
+			}
+			buf.Write(indexEntry(linkHashKey(ln), ln.getMD516Key(), ln.getSegment(), ln.getSeekStart(), ln.getSeekLast()))
+		})
+		snap.indexes[indexID] = buf.Bytes()
+	}
+	return snap, nil
+}
+
+func (fs *formSnapshot) close() {
+	for _, file := range fs.files {
+		_ = file.Close()
+	}
+}
+
+// backup 将库或全部库的快照写入归档文件
+//
+// 归档为 tar.gz，包含与 lily.sync 格式一致的库表元数据，以及各表的数据分段文件及索引文件
+//
+// 全部表在同一快照时刻一致，快照期间表写入短暂等待，其余时间正常读写；默认存储引擎以外的表仅归档元数据
+func (l *Lily) backup(databaseName, path string) (*BackupResult, error) {
+	meta, dbs, err := l.backupMeta(databaseName)
+	if nil != err {
+		return nil, err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), os.ModePerm); nil != err {
+		return nil, err
+	}
+	tmpPath := strings.Join([]string{path, ".tmp"}, "")
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if nil != err {
+		return nil, err
+	}
+	result, err := l.writeBackup(file, meta, dbs)
+	if nil == err {
+		err = file.Sync()
+	}
+	_ = file.Close()
+	if nil != err {
+		_ = os.Remove(tmpPath)
+		return nil, err
+	}
+	if err = os.Rename(tmpPath, path); nil != err {
+		_ = os.Remove(tmpPath)
+		return nil, err
+	}
+	log.Info("backup",
+		log.Field("path", path),
+		log.Field("databases", result.Databases),
+		log.Field("forms", result.Forms),
+		log.Field("files", result.Files),
+		log.Field("size", result.Size))
+	return result, nil
+}
+
+// backupMeta 复制待备份库的元数据，databaseName 为空时复制全部库
+func (l *Lily) backupMeta(databaseName string) (*api.Lily, map[string]Database, error) {
+	defer l.lock.Unlock()
+	l.lock.Lock()
+	meta := &api.Lily{Databases: map[string]*api.Database{}}
+	dbs := map[string]Database{}
+	for name, dv := range l.lilyData.Databases {
+		if gnomon.StringIsNotEmpty(databaseName) && name != databaseName {
+			continue
+		}
+		if db := l.obtainDatabase(name); nil != db {
+			meta.Databases[name] = proto.Clone(dv).(*api.Database)
+			dbs[name] = db
+		}
+	}
+	if len(meta.Databases) == 0 {
+		return nil, nil, ErrDataIsNil
+	}
+	return meta, dbs, nil
+}
+
+// writeBackup 生成全部表快照后逐表写入归档，最后写入元数据
+func (l *Lily) writeBackup(w io.Writer, meta *api.Lily, dbs map[string]Database) (*BackupResult, error) {
+	var (
+		gw     = gzip.NewWriter(w)
+		tw     = tar.NewWriter(gw)
+		result = &BackupResult{}
+	)
+	snaps, err := snapshotForms(meta, dbs, result)
+	if nil != err {
+		return nil, err
+	}
+	defer func() {
+		for _, snap := range snaps {
+			snap.close()
+		}
+	}()
+	for _, snap := range snaps {
+		if err = backupForm(tw, snap, result); nil != err {
+			return nil, err
+		}
+	}
+	// 备份期间轮换数据密钥时，归档中的记录可能由新旧任一版本数据密钥加密，合并两者
+	l.lock.Lock()
+	for name, dv := range meta.Databases {
+		current := l.lilyData.Databases[name]
+		if nil == current {
+			continue
+		}
+		for version, wrapped := range current.DataKeys {
+			if nil == dv.DataKeys {
+				dv.DataKeys = map[uint32][]byte{}
+			}
+			if _, ok := dv.DataKeys[version]; !ok {
+				dv.DataKeys[version] = wrapped
+			}
+		}
+		if current.KeyVersion > dv.KeyVersion {
+			dv.KeyVersion = current.KeyVersion
+		}
+	}
+	l.lock.Unlock()
+	data, err := encodeCatalog(meta, 0)
+	if nil != err {
+		return nil, err
+	}
+	if err = writeBackupEntry(tw, backupBootstrap, int64(len(data)), bytes.NewReader(data)); nil != err {
+		return nil, err
+	}
+	if err = tw.Close(); nil != err {
+		return nil, err
+	}
+	return result, gw.Close()
+}
+
+// backupForm 将表快照中的数据分段文件及索引文件写入归档
+func backupForm(tw *tar.Writer, snap *formSnapshot, result *BackupResult) error {
+	for i, segment := range snap.segments {
+		name := strings.Join([]string{snap.dataID, snap.formID, strconv.FormatUint(uint64(segment), 10) + ".dat"}, "/")
+		if err := writeBackupEntry(tw, name, snap.sizes[i], io.NewSectionReader(snap.files[i], 0, snap.sizes[i])); nil != err {
+			return err
+		}
+		result.Files++
+		result.Size += snap.sizes[i]
+	}
+	for indexID, data := range snap.indexes {
+		name := strings.Join([]string{snap.dataID, snap.formID, indexID + ".idx"}, "/")
+		if err := writeBackupEntry(tw, name, int64(len(data)), bytes.NewReader(data)); nil != err {
+			return err
+		}
+		result.Files++
+		result.Size += int64(len(data))
+	}
+	return nil
+}
+
+func writeBackupEntry(tw *tar.Writer, name string, size int64, reader io.Reader) error {
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: size, ModTime: time.Now()}); nil != err {
+		return err
+	}
+	_, err := io.CopyN(tw, reader, size)
+	return err
+}
+
+// restore 依据归档文件恢复库
+//
+// 归档中的库将替换同名库，其余库不受影响；替换期间仍在操作被替换库的请求将失败
+//
+// 归档中的数据密钥以备份时的主密钥加密，恢复时须配置相同的主密钥
+func (l *Lily) restore(path string) (*BackupResult, error) {
+	staging := filepath.Join(obtainConf().DataDir, restoreDir)
+	if err := os.RemoveAll(staging); nil != err {
+		return nil, err
+	}
+	defer func() { _ = os.RemoveAll(staging) }()
+	meta, result, err := extractBackup(path, staging)
+	if nil != err {
+		return nil, err
+	}
+	master, err := obtainMasterKey()
+	if nil != err {
+		return nil, err
+	}
+	// 替换前校验全部库，避免替换中途失败
+	keyrings := map[string]*keyring{}
+	for name, dv := range meta.Databases {
+		for _, fv := range dv.Forms {
+			if gnomon.StringIsNotEmpty(fv.Engine) && nil == connector.Obtain(fv.Engine) {
+				return nil, ErrEngineInvalid
+			}
+		}
+		if keyrings[name], _, err = openKeyring(dv, master); nil != err {
+			return nil, err
+		}
+	}
+	defer l.lock.Unlock()
+	l.lock.Lock()
+	for name, dv := range meta.Databases {
+		for other, db := range l.getDatabaseMap() {
+			if other != name && db.getID() == dv.ID {
+				return nil, ErrDatabaseExist
+			}
+		}
+	}
+	var (
+		wg       sync.WaitGroup
+		restored []Database
+	)
+	for name, dv := range meta.Databases {
+		if err = l.replaceDatabase(name, dv, staging); nil != err {
+			return nil, err
+		}
+		l.lilyData.Databases[name] = dv
+		restored = append(restored, l.openDatabase(name, dv, keyrings[name], &wg))
+	}
+	wg.Wait()
+	for _, db := range restored {
+		if err = db.recover(); nil != err {
+			return nil, err
+		}
+		db.resumeBackfill()
+	}
+	l.storeRPC()
+	log.Info("restore",
+		log.Field("path", path),
+		log.Field("databases", result.Databases),
+		log.Field("forms", result.Forms),
+		log.Field("files", result.Files),
+		log.Field("size", result.Size))
+	return result, nil
+}
+
+// replaceDatabase 关闭并删除同名库，将解压后的库目录移入数据目录，调用方持有 l.lock
+func (l *Lily) replaceDatabase(name string, dv *api.Database, staging string) error {
+	dataDir := obtainConf().DataDir
+	if old := l.obtainDatabase(name); nil != old {
+		l.detachDatabase(name)
+		release := retireForms(old)
+		if err := old.close(); nil != err {
+			log.Warn("restore close database failed", log.Field("database", name), log.Err(err))
+		}
+		oldDir := filepath.Join(dataDir, old.getID())
+		store().invalidateDir(oldDir)
+		err := os.RemoveAll(oldDir)
+		release()
+		if nil != err {
+			return err
+		}
+	}
+	dir := filepath.Join(dataDir, dv.ID)
+	store().invalidateDir(dir)
+	if err := os.RemoveAll(dir); nil != err {
+		return err
+	}
+	if src := filepath.Join(staging, dv.ID); gnomon.FilePathExists(src) {
+		return os.Rename(src, dir)
+	}
+	return os.MkdirAll(dir, os.ModePerm)
+}
+
+// extractBackup 将归档解压至临时目录，返回归档中的库表元数据
+func extractBackup(path, staging string) (*api.Lily, *BackupResult, error) {
+	file, err := os.Open(path)
+	if nil != err {
+		return nil, nil, err
+	}
+	defer func() { _ = file.Close() }()
+	gr, err := gzip.NewReader(file)
+	if nil != err {
+		return nil, nil, ErrBackupInvalid
+	}
+	var (
+		tr     = tar.NewReader(gr)
+		meta   *api.Lily
+		result = &BackupResult{}
+	)
+	for {
+		header, err := tr.Next()
+		if io.EOF == err {
+			break
+		}
+		if nil != err {
+			return nil, nil, ErrBackupInvalid
+		}
+		if header.Name == backupBootstrap {
+			data, err := ioutil.ReadAll(tr)
+			if nil != err {
+				return nil, nil, ErrBackupInvalid
+			}
+			if meta, _, err = decodeCatalog(data); nil != err {
+				return nil, nil, ErrBackupInvalid
+			}
+			continue
+		}
+		// 仅接受 库ID/表ID/文件名 形式的条目，避免写出临时目录
+		parts := strings.Split(header.Name, "/")
+		if len(parts) != 3 {
+			return nil, nil, ErrBackupInvalid
+		}
+		for _, part := range parts {
+			if gnomon.StringIsEmpty(part) || part == "." || part == ".." || strings.ContainsRune(part, filepath.Separator) {
+				return nil, nil, ErrBackupInvalid
+			}
+		}
+		target := filepath.Join(staging, parts[0], parts[1], parts[2])
+		if err = os.MkdirAll(filepath.Dir(target), os.ModePerm); nil != err {
+			return nil, nil, err
+		}
+		if err = copyFileSync(target, tr); nil != err {
+			return nil, nil, err
+		}
+		result.Files++
+		result.Size += header.Size
+	}
+	if nil == meta {
+		return nil, nil, ErrBackupInvalid
+	}
+	for _, dv := range meta.Databases {
+		result.Databases++
+		result.Forms += int64(len(dv.Forms))
+	}
+	return meta, result, nil
+}
+
+// copyFileSync 将 reader 的内容写入文件并落盘
+func copyFileSync(path string, reader io.Reader) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if nil != err {
+		return err
+	}
+	if _, err = io.Copy(file, reader); nil != err {
+		_ = file.Close()
+		return err
+	}
+	if err = file.Sync(); nil != err {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,260 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLily_Backup(t *testing.T) {
+	var (
+		dbName   = "backup"
+		formName = "record"
+	)
+	dir, err := ioutil.TempDir("", "lily-backup")
+	if nil != err {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	l := ObtainLily()
+	l.Start()
+	if _, err = l.CreateDatabase(dbName, "备份测试"); nil != err {
+		t.Log(err)
+	}
+	if err = l.CreateForm(dbName, formName, "", FormTypeDoc); nil != err {
+		t.Log(err)
+	}
+	for i := 1; i <= 10; i++ {
+		if _, err = l.Set(dbName, formName, strconv.Itoa(i), int64(i)); nil != err {
+			t.Fatal(err)
+		}
+	}
+	path := filepath.Join(dir, "backup.tar.gz")
+	result, err := l.Backup(dbName, path)
+	if nil != err {
+		t.Fatal(err)
+	}
+	t.Log("backup databases =", result.Databases, "forms =", result.Forms, "files =", result.Files, "size =", result.Size)
+	// 备份后的写入不在归档中
+	if _, err = l.Set(dbName, formName, "1", int64(100)); nil != err {
+		t.Fatal(err)
+	}
+	if _, err = l.Set(dbName, formName, "11", int64(11)); nil != err {
+		t.Fatal(err)
+	}
+	if result, err = l.Restore(path); nil != err {
+		t.Fatal(err)
+	}
+	t.Log("restore databases =", result.Databases, "forms =", result.Forms, "files =", result.Files)
+	for i := 1; i <= 10; i++ {
+		if v, err := l.Get(dbName, formName, strconv.Itoa(i)); nil != err || v.(int64) != int64(i) {
+			t.Error("get", i, "after restore failed", v, err)
+		}
+	}
+	if _, err = l.Get(dbName, formName, "11"); nil == err {
+		t.Error("key written after backup should not be restored")
+	}
+	if _, err = l.Set(dbName, formName, "12", int64(12)); nil != err {
+		t.Error("restored database should accept writes", err)
+	}
+	// 全部库备份后恢复，默认库同样被替换
+	if _, err = l.SetD("backup", "lily"); nil != err {
+		t.Fatal(err)
+	}
+	if result, err = l.Backup("", filepath.Join(dir, "all.tar.gz")); nil != err {
+		t.Fatal(err)
+	}
+	if _, err = l.Restore(filepath.Join(dir, "all.tar.gz")); nil != err {
+		t.Fatal(err)
+	}
+	if v, err := l.GetD("backup"); nil != err || v != "lily" {
+		t.Error("default database should be restored", v, err)
+	}
+	if _, err = l.Backup("none", path); ErrDataIsNil != err {
+		t.Error("backup of unknown database should fail", err)
+	}
+	if err = l.DropDatabase(dbName); nil != err {
+		t.Fatal(err)
+	}
+}
+
+func TestLily_BackupConsistent(t *testing.T) {
+	var (
+		dbName = "backup_pit"
+		stop   = make(chan struct{})
+		done   = make(chan struct{})
+	)
+	dir, err := ioutil.TempDir("", "lily-backup")
+	if nil != err {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	l := ObtainLily()
+	l.Start()
+	if _, err = l.CreateDatabase(dbName, "备份一致性测试"); nil != err {
+		t.Log(err)
+	}
+	for _, formName := range []string{"a", "b"} {
+		if err = l.CreateForm(dbName, formName, "", FormTypeDoc); nil != err {
+			t.Log(err)
+		}
+	}
+	// 表a归档耗时较长，逐表快照时归档表a期间表b持续写入
+	padding := strings.Repeat("lily", 1024)
+	for i := 0; i < 500; i++ {
+		if _, err = l.Set(dbName, "a", strconv.Itoa(i), padding); nil != err {
+			t.Fatal(err)
+		}
+	}
+	// 先写表a再写表b，任一时刻表a的值不小于表b
+	go func() {
+		defer close(done)
+		for i := int64(1); ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			_, _ = l.Set(dbName, "a", "n", i)
+			_, _ = l.Set(dbName, "b", "n", i)
+		}
+	}()
+	var paths []string
+	time.Sleep(20 * time.Millisecond)
+	for i := 0; i < 3; i++ {
+		path := filepath.Join(dir, strconv.Itoa(i)+".tar.gz")
+		if _, err = l.Backup(dbName, path); nil != err {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	close(stop)
+	<-done
+	for _, path := range paths {
+		if _, err = l.Restore(path); nil != err {
+			t.Fatal(err)
+		}
+		a, errA := l.Get(dbName, "a", "n")
+		b, errB := l.Get(dbName, "b", "n")
+		if nil != errA || nil != errB {
+			t.Log("backup taken before first write", errA, errB)
+			continue
+		}
+		t.Log("restore", path, "a =", a, "b =", b)
+		if n := a.(int64) - b.(int64); n != 0 && n != 1 {
+			t.Error("forms in backup should be snapshotted at the same time", a, b)
+		}
+	}
+	if err = l.DropDatabase(dbName); nil != err {
+		t.Fatal(err)
+	}
+}
+
+func TestLily_BackupConcurrentRestore(t *testing.T) {
+	dbName := "backup_lock"
+	dir, err := ioutil.TempDir("", "lily-backup")
+	if nil != err {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	l := ObtainLily()
+	l.Start()
+	if _, err = l.CreateDatabase(dbName, "备份与恢复并发测试"); nil != err {
+		t.Log(err)
+	}
+	for i := 0; i < 8; i++ {
+		if err = l.CreateForm(dbName, strconv.Itoa(i), "", FormTypeDoc); nil != err {
+			t.Log(err)
+		}
+		if _, err = l.Set(dbName, strconv.Itoa(i), "n", int64(i)); nil != err {
+			t.Fatal(err)
+		}
+	}
+	// 表名顺序与表唯一ID顺序不同，同时持有多个表锁时均按库唯一ID及表唯一ID顺序加锁
+	var (
+		forms []Form
+		held  []string
+	)
+	for _, form := range l.GetDatabase(dbName).getForms() {
+		forms = append(forms, form)
+	}
+	holdForms(forms, func(form Form) func() {
+		held = append(held, form.getID())
+		return func() {}
+	})()
+	t.Log("held =", held)
+	for i := 1; i < len(held); i++ {
+		if held[i-1] > held[i] {
+			t.Fatal("forms should be held in id order", held)
+		}
+	}
+	// 备份与恢复、删除库同时持有多个表锁时不应相互等待
+	path := filepath.Join(dir, "lock.tar.gz")
+	if _, err = l.Backup(dbName, path); nil != err {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			_, _ = l.Backup(dbName, filepath.Join(dir, strconv.Itoa(i)+".tar.gz"))
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		if _, err = l.Restore(path); nil != err {
+			t.Fatal(err)
+		}
+	}
+	if err = l.DropDatabase(dbName); nil != err {
+		t.Fatal(err)
+	}
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatal("backup should not deadlock with restore or drop")
+	}
+}
+
+func TestExtractBackup_Invalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lily-backup")
+	if nil != err {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	path := filepath.Join(dir, "escape.tar.gz")
+	file, err := os.Create(path)
+	if nil != err {
+		t.Fatal(err)
+	}
+	gw := gzip.NewWriter(file)
+	tw := tar.NewWriter(gw)
+	data := []byte("escape")
+	_ = tw.WriteHeader(&tar.Header{Name: "../../escape.dat", Mode: 0644, Size: int64(len(data))})
+	_, _ = tw.Write(data)
+	_ = tw.Close()
+	_ = gw.Close()
+	_ = file.Close()
+	if _, _, err = extractBackup(path, filepath.Join(dir, "staging")); ErrBackupInvalid != err {
+		t.Error("entry outside the staging dir should be rejected", err)
+	}
+}
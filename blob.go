@@ -0,0 +1,216 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/aberic/gnomon"
+	"github.com/aberic/gnomon/log"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// blobChunkSize 大数据分块大小，每块作为一条数据记录存储
+	blobChunkSize = 255 * 1024
+	// blobChunkPrefix 分块记录key前缀，分块记录不出现在遍历及检索结果中
+	blobChunkPrefix = "\x00blob\x00"
+	// blobMark 描述记录中标识大数据唯一ID的字段
+	blobMark = "_blob"
+)
+
+var (
+	// ErrBlobNotFound key不存在或对应数据不是大数据
+	ErrBlobNotFound = errors.New("blob not found")
+	// ErrBlobCorrupt 大数据分块缺失或校验失败
+	ErrBlobCorrupt = errors.New("blob is corrupt")
+)
+
+// BlobInfo 大数据描述
+//
+// 大数据按 blobChunkSize 分块存储，key对应的记录为描述，分块以独立的记录存储
+type BlobInfo struct {
+	Key       string // Key 大数据key
+	ID        string // ID 大数据唯一ID，每次写入生成新的ID
+	Size      int64  // Size 大数据总字节数
+	Chunks    int64  // Chunks 分块数
+	ChunkSize int64  // ChunkSize 分块大小
+	SHA256    string // SHA256 大数据内容的16进制sha256
+}
+
+// value 描述记录存储数据
+func (b *BlobInfo) value() map[string]interface{} {
+	return map[string]interface{}{
+		blobMark:    b.ID,
+		"size":      b.Size,
+		"chunks":    b.Chunks,
+		"chunkSize": b.ChunkSize,
+		"sha256":    b.SHA256,
+	}
+}
+
+// newBlobInfo 依据描述记录存储数据还原大数据描述，不是大数据描述时返回 ErrBlobNotFound
+func newBlobInfo(key string, value interface{}) (*BlobInfo, error) {
+	item, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, ErrBlobNotFound
+	}
+	id, ok := item[blobMark].(string)
+	if !ok || gnomon.StringIsEmpty(id) {
+		return nil, ErrBlobNotFound
+	}
+	info := &BlobInfo{Key: key, ID: id}
+	info.SHA256, _ = item["sha256"].(string)
+	for field, pointer := range map[string]*int64{"size": &info.Size, "chunks": &info.Chunks, "chunkSize": &info.ChunkSize} {
+		switch number := item[field].(type) {
+		default:
+			return nil, ErrBlobCorrupt
+		case int64:
+			*pointer = number
+		case uint64:
+			*pointer = int64(number)
+		}
+	}
+	return info, nil
+}
+
+// blobChunkKey 大数据分块记录key
+func blobChunkKey(id string, chunk int64) string {
+	return strings.Join([]string{blobChunkPrefix, id, "\x00", strconv.FormatInt(chunk, 10)}, "")
+}
+
+// isBlobChunk key是否为大数据分块记录
+func isBlobChunk(key string) bool {
+	return strings.HasPrefix(key, blobChunkPrefix)
+}
+
+// putBlob 分块读取 r 并存储，全部分块写入后再写入描述记录，最后移除key原有大数据的分块
+//
+// 写入失败时移除已写入的分块，key原有数据不受影响
+func (d *database) putBlob(formName, key string, r io.Reader) (*BlobInfo, error) {
+	form := d.getForms()[formName]
+	if nil == form {
+		return nil, formIsInvalid(formName)
+	}
+	if form.getEngine() != EngineFile {
+		return nil, ErrEngineUnsupported
+	}
+	if gnomon.StringIsEmpty(key) || isBlobChunk(key) {
+		return nil, ErrKeyIsNil
+	}
+	var (
+		info   = &BlobInfo{Key: key, ID: gnomon.HashMD516(strings.Join([]string{key, strconv.FormatInt(time.Now().UnixNano(), 10)}, "")), ChunkSize: blobChunkSize}
+		hasher = sha256.New()
+		buf    = make([]byte, blobChunkSize)
+	)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			chunk := make([]byte, n)
+			copy(chunk, buf[:n])
+			if _, err := d.put(formName, blobChunkKey(info.ID, info.Chunks), chunk, true, 0); nil != err {
+				d.removeBlobChunks(formName, info)
+				return nil, err
+			}
+			_, _ = hasher.Write(chunk)
+			info.Chunks++
+			info.Size += int64(n)
+		}
+		if io.EOF == err || io.ErrUnexpectedEOF == err {
+			break
+		}
+		if nil != err {
+			d.removeBlobChunks(formName, info)
+			return nil, err
+		}
+	}
+	info.SHA256 = hex.EncodeToString(hasher.Sum(nil))
+	prev, _ := d.statBlob(formName, key)
+	if _, err := d.put(formName, key, info.value(), true, 0); nil != err {
+		d.removeBlobChunks(formName, info)
+		return nil, err
+	}
+	if nil != prev {
+		d.removeBlobChunks(formName, prev)
+	}
+	return info, nil
+}
+
+// statBlob 获取大数据描述
+func (d *database) statBlob(formName, key string) (*BlobInfo, error) {
+	value, err := d.get(formName, key)
+	if nil != err {
+		return nil, err
+	}
+	return newBlobInfo(key, value)
+}
+
+// getBlob 按顺序读取大数据分块写入 w，读取完成后校验总长度及sha256
+func (d *database) getBlob(formName, key string, w io.Writer) (*BlobInfo, error) {
+	info, err := d.statBlob(formName, key)
+	if nil != err {
+		return nil, err
+	}
+	var (
+		hasher = sha256.New()
+		size   int64
+	)
+	for chunk := int64(0); chunk < info.Chunks; chunk++ {
+		value, err := d.get(formName, blobChunkKey(info.ID, chunk))
+		if nil != err {
+			log.Warn("blob chunk is unreadable", log.Field("key", key), log.Field("chunk", chunk), log.Err(err))
+			return info, ErrBlobCorrupt
+		}
+		data, ok := value.([]byte)
+		if !ok {
+			return info, ErrBlobCorrupt
+		}
+		if _, err = w.Write(data); nil != err {
+			return info, err
+		}
+		_, _ = hasher.Write(data)
+		size += int64(len(data))
+	}
+	if size != info.Size || hex.EncodeToString(hasher.Sum(nil)) != info.SHA256 {
+		return info, ErrBlobCorrupt
+	}
+	return info, nil
+}
+
+// removeBlob 删除大数据描述记录及其全部分块
+func (d *database) removeBlob(formName, key string) error {
+	info, err := d.statBlob(formName, key)
+	if nil != err {
+		return err
+	}
+	if err = d.remove(formName, key); nil != err {
+		return err
+	}
+	d.removeBlobChunks(formName, info)
+	return nil
+}
+
+// removeBlobChunks 删除大数据分块，失败的分块作为孤立记录保留
+func (d *database) removeBlobChunks(formName string, info *BlobInfo) {
+	for chunk := int64(0); chunk < info.Chunks; chunk++ {
+		if err := d.remove(formName, blobChunkKey(info.ID, chunk)); nil != err {
+			log.Warn("blob chunk remove failed", log.Field("key", info.Key), log.Field("chunk", chunk), log.Err(err))
+		}
+	}
+}
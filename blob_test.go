@@ -0,0 +1,93 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"bytes"
+	"crypto/rand"
+	"github.com/aberic/lily/api"
+	"testing"
+)
+
+func TestLily_Blob(t *testing.T) {
+	var (
+		dbName   = "blob"
+		formName = "file"
+	)
+	l := ObtainLily()
+	l.Start()
+	if _, err := l.CreateDatabase(dbName, "大数据测试"); nil != err {
+		t.Log(err)
+	}
+	if err := l.CreateForm(dbName, formName, "", FormTypeDoc); nil != err {
+		t.Log(err)
+	}
+	data := make([]byte, blobChunkSize*2+100)
+	_, _ = rand.Read(data)
+	info, err := l.PutBlob(dbName, formName, "video", bytes.NewReader(data))
+	if nil != err {
+		t.Fatal(err)
+	}
+	t.Log("blob id =", info.ID, "size =", info.Size, "chunks =", info.Chunks, "sha256 =", info.SHA256)
+	if info.Size != int64(len(data)) || info.Chunks != 3 {
+		t.Error("blob info is wrong", info)
+	}
+	buf := &bytes.Buffer{}
+	if _, err = l.GetBlob(dbName, formName, "video", buf); nil != err || !bytes.Equal(buf.Bytes(), data) {
+		t.Error("get blob failed", buf.Len(), err)
+	}
+	// 分块记录不出现在遍历结果中
+	count := 0
+	if err = l.GetDatabase(dbName).getForms()[formName].getStorage().Scan(func(key string, value interface{}) bool {
+		count++
+		return true
+	}); nil != err || count != 1 {
+		t.Error("scan should only see the blob description", count, err)
+	}
+	// 覆盖后旧分块被移除
+	small := []byte("small blob")
+	if _, err = l.PutBlob(dbName, formName, "video", bytes.NewReader(small)); nil != err {
+		t.Fatal(err)
+	}
+	if _, err = l.Get(dbName, formName, blobChunkKey(info.ID, 0)); nil == err {
+		t.Error("chunks of the replaced blob should be removed")
+	}
+	// 超过4位持续seek上限的单条记录
+	large := bytes.Repeat([]byte("lily"), 5<<20)
+	if _, err = l.Set(dbName, formName, "large", large); nil != err {
+		t.Fatal(err)
+	}
+	if _, err = l.Compact(dbName, formName); nil != err {
+		t.Fatal(err)
+	}
+	restarted := &Lily{lilyData: &api.Lily{Databases: map[string]*api.Database{}}, databases: map[string]Database{}}
+	restarted.Restart()
+	buf.Reset()
+	if _, err = restarted.GetBlob(dbName, formName, "video", buf); nil != err || buf.String() != string(small) {
+		t.Error("blob should survive compaction and restart", buf.String(), err)
+	}
+	if v, err := restarted.Get(dbName, formName, "large"); nil != err || !bytes.Equal(v.([]byte), large) {
+		t.Error("large record should survive compaction and restart", err)
+	}
+	if err = restarted.RemoveBlob(dbName, formName, "video"); nil != err {
+		t.Fatal(err)
+	}
+	if _, err = restarted.GetBlob(dbName, formName, "video", buf); nil == err {
+		t.Error("removed blob should not be found")
+	}
+	if err = restarted.DropDatabase(dbName); nil != err {
+		t.Fatal(err)
+	}
+}
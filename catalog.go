@@ -0,0 +1,201 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"github.com/aberic/gnomon"
+	"github.com/aberic/gnomon/log"
+	"github.com/aberic/lily/api"
+	"github.com/golang/protobuf/proto"
+	"hash/crc32"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	sort2 "sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// catalogMagic 库表元数据文件头标识，不以该标识开头的文件为无文件头的旧版格式
+	catalogMagic = "LCAT"
+	// catalogFormatVersion 库表元数据文件格式版本
+	catalogFormatVersion uint16 = 1
+	// catalogHeaderSize 文件头长度，依次为标识、格式版本、代数、数据长度及数据 crc32
+	catalogHeaderSize = 4 + 2 + 8 + 4 + 4
+	// catalogCorruptSuffix 回滚时损坏的库表元数据文件重命名后缀
+	catalogCorruptSuffix = ".corrupt"
+)
+
+// ErrCatalogInvalid 库表元数据文件损坏或不完整
+var ErrCatalogInvalid = errors.New("catalog is corrupt or incomplete")
+
+// encodeCatalog 为库表元数据添加文件头，generation 为写入代数，每次写入递增
+func encodeCatalog(lily *api.Lily, generation uint64) ([]byte, error) {
+	data, err := proto.Marshal(lily)
+	if nil != err {
+		return nil, err
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, catalogHeaderSize+len(data)))
+	buf.WriteString(catalogMagic)
+	header := make([]byte, catalogHeaderSize-len(catalogMagic))
+	binary.BigEndian.PutUint16(header[0:2], catalogFormatVersion)
+	binary.BigEndian.PutUint64(header[2:10], generation)
+	binary.BigEndian.PutUint32(header[10:14], uint32(len(data)))
+	binary.BigEndian.PutUint32(header[14:18], crc32.ChecksumIEEE(data))
+	buf.Write(header)
+	buf.Write(data)
+	return buf.Bytes(), nil
+}
+
+// decodeCatalog 校验并解析库表元数据，兼容无文件头的旧版格式，旧版格式代数为0
+func decodeCatalog(data []byte) (*api.Lily, uint64, error) {
+	var generation uint64
+	if bytes.HasPrefix(data, []byte(catalogMagic)) {
+		if len(data) < catalogHeaderSize {
+			return nil, 0, ErrCatalogInvalid
+		}
+		header := data[len(catalogMagic):catalogHeaderSize]
+		if version := binary.BigEndian.Uint16(header[0:2]); version != catalogFormatVersion {
+			return nil, 0, errors.New(strings.Join([]string{"catalog format version", strconv.Itoa(int(version)), "is unsupported"}, " "))
+		}
+		generation = binary.BigEndian.Uint64(header[2:10])
+		length := binary.BigEndian.Uint32(header[10:14])
+		data = data[catalogHeaderSize:]
+		if uint32(len(data)) != length || crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(header[14:18]) {
+			return nil, 0, ErrCatalogInvalid
+		}
+	}
+	lily := &api.Lily{}
+	if err := proto.Unmarshal(data, lily); nil != err {
+		return nil, 0, err
+	}
+	return lily, generation, nil
+}
+
+// readCatalog 读取并解析库表元数据文件
+func readCatalog(path string) (*api.Lily, uint64, error) {
+	data, err := ioutil.ReadFile(path)
+	if nil != err {
+		return nil, 0, err
+	}
+	return decodeCatalog(data)
+}
+
+// pathCatalogGeneration 库表元数据历史代文件地址，如 lily.sync.12
+func pathCatalogGeneration(path string, generation uint64) string {
+	return strings.Join([]string{path, strconv.FormatUint(generation, 10)}, ".")
+}
+
+// catalogGenerations 获取库表元数据历史代集合，由新至旧
+func catalogGenerations(path string) []uint64 {
+	matches, err := filepath.Glob(path + ".*")
+	if nil != err {
+		return nil
+	}
+	var generations []uint64
+	for _, match := range matches {
+		if generation, err := strconv.ParseUint(strings.TrimPrefix(match, path+"."), 10, 64); nil == err {
+			generations = append(generations, generation)
+		}
+	}
+	sort2.Slice(generations, func(i, j int) bool { return generations[i] > generations[j] })
+	return generations
+}
+
+// loadCatalog 读取库表元数据，当前文件损坏时依次尝试历史代，返回可用的最新一代
+//
+// rollback 为 true 表示当前文件损坏，返回的是历史代
+func loadCatalog(path string) (lily *api.Lily, generation uint64, rollback bool, err error) {
+	if lily, generation, err = readCatalog(path); nil == err {
+		return lily, generation, false, nil
+	}
+	log.Warn("catalog is unreadable, try to roll back to previous generation", log.Field("path", path), log.Err(err))
+	for _, gen := range catalogGenerations(path) {
+		history, _, historyErr := readCatalog(pathCatalogGeneration(path, gen))
+		if nil != historyErr {
+			log.Warn("catalog generation is unreadable", log.Field("generation", gen), log.Err(historyErr))
+			continue
+		}
+		log.Warn("catalog rolled back", log.Field("generation", gen))
+		return history, gen, true, nil
+	}
+	return nil, 0, false, err
+}
+
+// storeCatalog 以写临时文件后重命名的方式替换库表元数据文件，替换前将当前文件保留为历史代
+//
+// generation 本次写入代数，当前文件代数为 generation-1
+//
+// sync 为 true 时临时文件及所在目录均落盘后才返回，keep 为保留的历史代数量，超出的旧代被删除
+func storeCatalog(path string, lily *api.Lily, generation uint64, sync bool, keep int) error {
+	if keep < 0 {
+		keep = 0
+	}
+	data, err := encodeCatalog(lily, generation)
+	if nil != err {
+		return err
+	}
+	dir := filepath.Dir(path)
+	if err = os.MkdirAll(dir, os.ModePerm); nil != err {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if sync {
+		err = writeFileSync(tmpPath, data)
+	} else {
+		err = ioutil.WriteFile(tmpPath, data, 0644)
+	}
+	if nil != err {
+		return err
+	}
+	if keep > 0 && generation > 0 && gnomon.FilePathExists(path) {
+		// 硬链接保留当前文件，重命名时不存在元数据文件缺失的间隙
+		prevPath := pathCatalogGeneration(path, generation-1)
+		_ = os.Remove(prevPath)
+		if err = os.Link(path, prevPath); nil != err {
+			log.Warn("catalog generation keep failed", log.Field("path", prevPath), log.Err(err))
+		}
+	}
+	if err = os.Rename(tmpPath, path); nil != err {
+		return err
+	}
+	if sync {
+		if err = syncDir(dir); nil != err {
+			return err
+		}
+	}
+	generations := catalogGenerations(path)
+	for i := keep; i < len(generations); i++ {
+		_ = os.Remove(pathCatalogGeneration(path, generations[i]))
+	}
+	return nil
+}
+
+// syncDir 落盘目录，确保其中文件的创建及重命名持久化
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if nil != err {
+		return err
+	}
+	if err = d.Sync(); nil != err {
+		_ = d.Close()
+		return err
+	}
+	return d.Close()
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"github.com/aberic/gnomon"
+	"github.com/aberic/lily/api"
+	"github.com/golang/protobuf/proto"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestCatalog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lily-catalog")
+	if nil != err {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	path := filepath.Join(dir, "lily.sync")
+	// 无文件头的旧版格式
+	legacy, err := proto.Marshal(&api.Lily{Databases: map[string]*api.Database{"legacy": {Name: "legacy"}}})
+	if nil != err {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(path, legacy, 0644); nil != err {
+		t.Fatal(err)
+	}
+	lily, generation, rollback, err := loadCatalog(path)
+	if nil != err || rollback || generation != 0 || nil == lily.Databases["legacy"] {
+		t.Fatal("legacy catalog should be readable", generation, rollback, err)
+	}
+	for generation = 1; generation <= 5; generation++ {
+		name := "db" + strconv.FormatUint(generation, 10)
+		if err = storeCatalog(path, &api.Lily{Databases: map[string]*api.Database{name: {Name: name}}}, generation, true, 3); nil != err {
+			t.Fatal(err)
+		}
+	}
+	t.Log("generations =", catalogGenerations(path))
+	if gens := catalogGenerations(path); len(gens) != 3 || gens[0] != 4 || gens[2] != 2 {
+		t.Error("should keep 3 previous generations", gens)
+	}
+	if gnomon.FilePathExists(path + ".tmp") {
+		t.Error("temp catalog should be renamed")
+	}
+	if _, generation, _, err = loadCatalog(path); nil != err || generation != 5 {
+		t.Error("current generation should be 5", generation, err)
+	}
+	// 截断当前文件模拟写入中途崩溃，回滚至最新的历史代
+	data, err := ioutil.ReadFile(path)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(path, data[:len(data)-3], 0644); nil != err {
+		t.Fatal(err)
+	}
+	if _, _, err = readCatalog(path); err != ErrCatalogInvalid {
+		t.Error("truncated catalog should be invalid", err)
+	}
+	lily, generation, rollback, err = loadCatalog(path)
+	if nil != err || !rollback || generation != 4 || nil == lily.Databases["db4"] {
+		t.Error("catalog should roll back to generation 4", generation, rollback, err)
+	}
+}
+
+func TestLily_CatalogRollback(t *testing.T) {
+	dbName := "catalog"
+	l := ObtainLily()
+	l.Start()
+	if _, err := l.CreateDatabase(dbName, "元数据回滚测试"); nil != err {
+		t.Log(err)
+	}
+	if err := l.CreateForm(dbName, "record", "", FormTypeDoc); nil != err {
+		t.Log(err)
+	}
+	path := obtainConf().LilyBootstrapFilePath
+	if err := ioutil.WriteFile(path, []byte(catalogMagic+"broken"), 0644); nil != err {
+		t.Fatal(err)
+	}
+	restarted := &Lily{lilyData: &api.Lily{Databases: map[string]*api.Database{}}, databases: map[string]Database{}}
+	restarted.Restart()
+	if nil == restarted.GetDatabase(dbName) {
+		t.Error("database should be recovered from previous generation")
+	}
+	if !gnomon.FilePathExists(path + catalogCorruptSuffix) {
+		t.Error("corrupt catalog should be kept")
+	}
+	if _, _, err := readCatalog(path); nil != err {
+		t.Error("catalog should be rewritten after rollback", err)
+	}
+}
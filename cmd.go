@@ -20,11 +20,13 @@ import (
 	"flag"
 	"fmt"
 	"github.com/aberic/gnomon"
+	"github.com/aberic/lily/api"
 	"github.com/getwe/figlet4go"
 	"github.com/spf13/cobra"
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -36,6 +38,16 @@ var (
 	address     string // address lily服务地址
 	username    string // username lily服务用户名
 	password    string // password lily服务密码
+	dbName      string // dbName 数据库名称
+	formName    string // formName 表名称
+	background  bool   // background 是否后台执行
+	repair      bool   // repair 是否修复完整性检查发现的问题
+	dryRun      bool   // dryRun 是否仅检查需要迁移的索引文件
+	keyName     string // keyName 索引结构名
+	archivePath string // archivePath 服务端备份归档文件路径
+	dataFormat  string // dataFormat 导入导出格式
+	dataPath    string // dataPath 本地导入导出文件路径
+	rejectPath  string // rejectPath 本地无法导入行的写入文件路径
 )
 
 var versionCmd = &cobra.Command{
@@ -101,6 +113,159 @@ var connCmd = &cobra.Command{
 	},
 }
 
+var compactCmd = &cobra.Command{
+	Use:   "compact",
+	Short: "压缩lily指定表的数据文件，仅保留有效记录",
+	Long:  `rewrite the data file of the specified form, keeping only the live records`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if gnomon.StringIsEmpty(dbName) || gnomon.StringIsEmpty(formName) {
+			return errors.New("database and form are required , Use lily compact -h to get more information ")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		compactForm()
+	},
+}
+
+var rotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "轮换lily指定库的数据密钥，并以新数据密钥重新加密库数据",
+	Long:  `generate a new data key for the specified database and re-encrypt its data files`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if gnomon.StringIsEmpty(dbName) {
+			return errors.New("database is required , Use lily rotate -h to get more information ")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		rotateKeyCmd()
+	},
+}
+
+var fsckCmd = &cobra.Command{
+	Use:   "fsck",
+	Short: "离线检查lily所有库表数据与索引的完整性，须在lily停止时执行",
+	Long:  `check every index entry against the data file offline, report and optionally repair dangling, duplicate or corrupt entries`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fsck()
+	},
+}
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "离线将lily旧版文本索引文件原地转换为当前的二进制格式，须在lily停止时执行",
+	Long:  `convert every legacy text index file to the current binary index format in place`,
+	Run: func(cmd *cobra.Command, args []string) {
+		migrate()
+	},
+}
+
+var rebuildCmd = &cobra.Command{
+	Use:   "rebuild",
+	Short: "依据数据文件重建lily指定表的索引",
+	Long:  `scan the data file of the specified form and regenerate the index file and in-memory tree`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if gnomon.StringIsEmpty(dbName) || gnomon.StringIsEmpty(formName) || gnomon.StringIsEmpty(keyName) {
+			return errors.New("database, form and index are required , Use lily rebuild -h to get more information ")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		rebuildIndexCmd()
+	},
+}
+
+var indexCmd = &cobra.Command{
+	Use:   "index",
+	Short: "查看lily指定索引的构建状态",
+	Long:  `show the backfill state and progress of the specified index`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if gnomon.StringIsEmpty(dbName) || gnomon.StringIsEmpty(formName) || gnomon.StringIsEmpty(keyName) {
+			return errors.New("database, form and index are required , Use lily index -h to get more information ")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		indexStatus()
+	},
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "查看lily指定表的统计信息",
+	Long:  `show records, data size and compression ratio of the specified form`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if gnomon.StringIsEmpty(dbName) || gnomon.StringIsEmpty(formName) {
+			return errors.New("database and form are required , Use lily stats -h to get more information ")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		formStats()
+	},
+}
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "在线备份lily指定库或全部库至服务端归档文件",
+	Long:  `write a consistent snapshot archive of the specified database, or all databases, while lily keeps serving`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if gnomon.StringIsEmpty(archivePath) {
+			return errors.New("archive path is required , Use lily backup -h to get more information ")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		backupCmdRun()
+	},
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "依据服务端归档文件恢复lily库，归档中的库将替换同名库",
+	Long:  `restore databases from the archive on lily server, databases in the archive replace the ones with the same name`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if gnomon.StringIsEmpty(archivePath) {
+			return errors.New("archive path is required , Use lily restore -h to get more information ")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		restoreCmdRun()
+	},
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "导出lily指定表数据至本地 JSON Lines 或 CSV 文件",
+	Long:  `export records of the specified form to a local JSON Lines or CSV file, nested objects are flattened with '.' paths in CSV`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if gnomon.StringIsEmpty(dbName) || gnomon.StringIsEmpty(formName) || gnomon.StringIsEmpty(dataPath) {
+			return errors.New("database, form and output are required , Use lily export -h to get more information ")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		exportCmdRun()
+	},
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "将本地 JSON Lines 或 CSV 文件导入lily指定表",
+	Long:  `import records from a local JSON Lines or CSV file into the specified form, rows that fail are written to the reject file`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if gnomon.StringIsEmpty(dbName) || gnomon.StringIsEmpty(formName) || gnomon.StringIsEmpty(dataPath) {
+			return errors.New("database, form and input are required , Use lily import -h to get more information ")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		importCmdRun()
+	},
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "lily",
 	Short: "lily是命令的抬头符",
@@ -112,7 +277,7 @@ var rootCmd = &cobra.Command{
 		switch args[0] {
 		default:
 			return errors.New("command is required , Use lily -h to get more information ")
-		case "conn", "help", "restart", "start", "stop", "version":
+		case "backup", "compact", "conn", "export", "fsck", "help", "import", "migrate", "rebuild", "restart", "restore", "rotate", "start", "stats", "stop", "version":
 			return nil
 		}
 	},
@@ -254,17 +419,257 @@ func conn() {
 	}
 }
 
+// compactForm 压缩表数据文件
+func compactForm() {
+	resp, err := Compact(address, dbName, formName, background)
+	if nil != err {
+		fmt.Println(err.Error())
+		return
+	}
+	if background {
+		fmt.Println("compact is running in background")
+		return
+	}
+	fmt.Printf("compact success, records: %d, size: %d -> %d\n", resp.Records, resp.SizeBefore, resp.SizeAfter)
+}
+
+// rotateKeyCmd 轮换数据密钥
+func rotateKeyCmd() {
+	resp, err := RotateKey(address, dbName, background)
+	if nil != err {
+		fmt.Println(err.Error())
+		return
+	}
+	if background {
+		fmt.Println("rotate is running in background")
+		return
+	}
+	fmt.Printf("rotate success, key version: %d, records: %d\n", resp.KeyVersion, resp.Records)
+}
+
+// fsck 离线检查库表数据与索引的完整性
+func fsck() {
+	if gnomon.FilePathExists("lily.lock") {
+		fmt.Println("lily is running, stop it before fsck")
+		return
+	}
+	ObtainConf(confYmlPath)
+	report, err := Fsck(repair)
+	if nil != err {
+		fmt.Println(err.Error())
+		return
+	}
+	for _, issue := range report.Issues {
+		fmt.Printf("[%s] database: %s, form: %s, index: %s, position: %d, %s\n",
+			issue.Kind, issue.Database, issue.Form, issue.Index, issue.Position, issue.Detail)
+	}
+	fmt.Printf("fsck checked %d indexes, %d entries, found %d issues", report.Indexes, report.Entries, len(report.Issues))
+	if repair {
+		fmt.Printf(", removed %d entries", report.Repaired)
+	}
+	fmt.Println()
+}
+
+// migrate 离线迁移索引文件格式
+func migrate() {
+	if gnomon.FilePathExists("lily.lock") {
+		fmt.Println("lily is running, stop it before migrate")
+		return
+	}
+	ObtainConf(confYmlPath)
+	report, err := Migrate(dryRun)
+	if nil != err {
+		fmt.Println(err.Error())
+		return
+	}
+	if dryRun {
+		fmt.Printf("migrate checked %d indexes, %d need to be migrated, %d entries, %d corrupt entries will be skipped\n",
+			report.Indexes, report.Migrated, report.Entries, report.Skipped)
+		return
+	}
+	fmt.Printf("migrate checked %d indexes, migrated %d indexes, %d entries, skipped %d corrupt entries\n",
+		report.Indexes, report.Migrated, report.Entries, report.Skipped)
+}
+
+// rebuildIndexCmd 重建索引
+func rebuildIndexCmd() {
+	resp, err := RebuildIndex(address, dbName, formName, keyName)
+	if nil != err {
+		fmt.Println(err.Error())
+		return
+	}
+	fmt.Printf("rebuild index success, entries: %d\n", resp.Count)
+}
+
+// indexStatus 查看索引构建状态
+func indexStatus() {
+	resp, err := GetIndexStatus(address, dbName, formName, keyName)
+	if nil != err {
+		fmt.Println(err.Error())
+		return
+	}
+	if gnomon.StringIsNotEmpty(resp.ErrMsg) {
+		fmt.Printf("index state: %s, records: %d/%d, error: %s\n", resp.State, resp.Done, resp.Total, resp.ErrMsg)
+		return
+	}
+	fmt.Printf("index state: %s, records: %d/%d\n", resp.State, resp.Done, resp.Total)
+}
+
+// formStats 查看表统计信息
+func formStats() {
+	resp, err := GetFormStats(address, dbName, formName)
+	if nil != err {
+		fmt.Println(err.Error())
+		return
+	}
+	fmt.Printf("compression: %s, segments: %d, records: %d, data size: %d, raw size: %d, ratio: %.4f\n",
+		FormatCompression(resp.Compression), resp.Segments, resp.Records, resp.DataSize, resp.RawSize, resp.Ratio)
+}
+
+// backupCmdRun 备份库
+func backupCmdRun() {
+	resp, err := Backup(address, dbName, archivePath)
+	if nil != err {
+		fmt.Println(err.Error())
+		return
+	}
+	fmt.Printf("backup success, databases: %d, forms: %d, files: %d, size: %d\n", resp.Databases, resp.Forms, resp.Files, resp.Size)
+}
+
+// restoreCmdRun 依据归档文件恢复库
+func restoreCmdRun() {
+	resp, err := Restore(address, archivePath)
+	if nil != err {
+		fmt.Println(err.Error())
+		return
+	}
+	fmt.Printf("restore success, databases: %d, forms: %d, files: %d, size: %d\n", resp.Databases, resp.Forms, resp.Files, resp.Size)
+}
+
+// dataFileFormat 未指定格式时依据文件扩展名判断，.csv 为 CSV，其余为 JSON Lines
+func dataFileFormat() string {
+	if gnomon.StringIsNotEmpty(dataFormat) {
+		return dataFormat
+	}
+	if strings.EqualFold(filepath.Ext(dataPath), ".csv") {
+		return ExportCSV
+	}
+	return ExportJSONL
+}
+
+// exportCmdRun 导出表数据
+func exportCmdRun() {
+	file, err := os.Create(dataPath)
+	if nil != err {
+		fmt.Println(err.Error())
+		return
+	}
+	defer func() { _ = file.Close() }()
+	w := bufio.NewWriter(file)
+	rows, err := Export(address, dbName, formName, dataFileFormat(), w)
+	if nil == err {
+		err = w.Flush()
+	}
+	if nil != err {
+		fmt.Println(err.Error())
+		return
+	}
+	fmt.Printf("export success, rows: %d\n", rows)
+}
+
+// importCmdRun 导入表数据，未指定拒绝文件时写入导入文件同目录下的 .reject 文件，无拒绝行时删除
+func importCmdRun() {
+	file, err := os.Open(dataPath)
+	if nil != err {
+		fmt.Println(err.Error())
+		return
+	}
+	defer func() { _ = file.Close() }()
+	if gnomon.StringIsEmpty(rejectPath) {
+		rejectPath = strings.Join([]string{dataPath, "reject"}, ".")
+	}
+	reject, err := os.Create(rejectPath)
+	if nil != err {
+		fmt.Println(err.Error())
+		return
+	}
+	resp, err := Import(address, dbName, formName, dataFileFormat(), bufio.NewReader(file), reject, func(resp *api.RespImport) {
+		fmt.Printf("\rrows: %d, imported: %d, rejected: %d", resp.Rows, resp.Imported, resp.Rejected)
+	})
+	_ = reject.Close()
+	if nil != err {
+		fmt.Println()
+		fmt.Println(err.Error())
+		return
+	}
+	fmt.Printf("\rimport success, rows: %d, imported: %d, rejected: %d\n", resp.Rows, resp.Imported, resp.Rejected)
+	if resp.Rejected == 0 {
+		_ = os.Remove(rejectPath)
+	} else {
+		fmt.Println("rejected rows are written to", rejectPath)
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(startCmd)
 	rootCmd.AddCommand(restartCmd)
 	rootCmd.AddCommand(stopCmd)
 	rootCmd.AddCommand(connCmd)
+	rootCmd.AddCommand(compactCmd)
+	rootCmd.AddCommand(fsckCmd)
+	rootCmd.AddCommand(migrateCmd)
+	rootCmd.AddCommand(rebuildCmd)
+	rootCmd.AddCommand(indexCmd)
+	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(rotateCmd)
+	rootCmd.AddCommand(backupCmd)
+	rootCmd.AddCommand(restoreCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
 	startCmd.Flags().StringVarP(&confYmlPath, "path", "p", "", "也许你希望通过指定‘conf.yml’文件来使用自己的配置.")
 	startCmd.Flags().BoolVarP(&daemon, "daemon", "d", false, "是否启动后台运行")
 	connCmd.Flags().StringVarP(&address, "address", "a", "localhost:19877", "lily服务端地址，默认localhost")
 	connCmd.Flags().StringVarP(&username, "username", "u", "", "lily服务端登录用户")
 	connCmd.Flags().StringVarP(&password, "password", "p", "", "lily服务端登录密码")
+	compactCmd.Flags().StringVarP(&address, "address", "a", "localhost:19877", "lily服务端地址，默认localhost")
+	compactCmd.Flags().StringVarP(&dbName, "database", "d", "", "数据库名称")
+	compactCmd.Flags().StringVarP(&formName, "form", "f", "", "表名称")
+	rebuildCmd.Flags().StringVarP(&address, "address", "a", "localhost:19877", "lily服务端地址，默认localhost")
+	rebuildCmd.Flags().StringVarP(&dbName, "database", "d", "", "数据库名称")
+	rebuildCmd.Flags().StringVarP(&formName, "form", "f", "", "表名称")
+	rebuildCmd.Flags().StringVarP(&keyName, "index", "i", "", "索引结构名")
+	indexCmd.Flags().StringVarP(&address, "address", "a", "localhost:19877", "lily服务端地址，默认localhost")
+	indexCmd.Flags().StringVarP(&dbName, "database", "d", "", "数据库名称")
+	indexCmd.Flags().StringVarP(&formName, "form", "f", "", "表名称")
+	indexCmd.Flags().StringVarP(&keyName, "index", "i", "", "索引结构名")
+	statsCmd.Flags().StringVarP(&address, "address", "a", "localhost:19877", "lily服务端地址，默认localhost")
+	statsCmd.Flags().StringVarP(&dbName, "database", "d", "", "数据库名称")
+	statsCmd.Flags().StringVarP(&formName, "form", "f", "", "表名称")
+	rotateCmd.Flags().StringVarP(&address, "address", "a", "localhost:19877", "lily服务端地址，默认localhost")
+	rotateCmd.Flags().StringVarP(&dbName, "database", "d", "", "数据库名称")
+	rotateCmd.Flags().BoolVarP(&background, "background", "b", false, "是否后台执行，后台执行时立即返回")
+	fsckCmd.Flags().StringVarP(&confYmlPath, "path", "p", "", "也许你希望通过指定‘conf.yml’文件来使用自己的配置.")
+	fsckCmd.Flags().BoolVarP(&repair, "repair", "r", false, "是否修复发现的问题，修复时重写索引文件")
+	migrateCmd.Flags().StringVarP(&confYmlPath, "path", "p", "", "也许你希望通过指定‘conf.yml’文件来使用自己的配置.")
+	migrateCmd.Flags().BoolVarP(&dryRun, "check", "c", false, "是否仅检查需要迁移的索引文件，不做修改")
+	compactCmd.Flags().BoolVarP(&background, "background", "b", false, "是否后台执行，后台执行时立即返回")
+	backupCmd.Flags().StringVarP(&address, "address", "a", "localhost:19877", "lily服务端地址，默认localhost")
+	backupCmd.Flags().StringVarP(&dbName, "database", "d", "", "数据库名称，为空时备份全部库")
+	backupCmd.Flags().StringVarP(&archivePath, "output", "o", "", "服务端归档文件路径")
+	restoreCmd.Flags().StringVarP(&address, "address", "a", "localhost:19877", "lily服务端地址，默认localhost")
+	restoreCmd.Flags().StringVarP(&archivePath, "input", "i", "", "服务端归档文件路径")
+	exportCmd.Flags().StringVarP(&address, "address", "a", "localhost:19877", "lily服务端地址，默认localhost")
+	exportCmd.Flags().StringVarP(&dbName, "database", "d", "", "数据库名称")
+	exportCmd.Flags().StringVarP(&formName, "form", "f", "", "表名称")
+	exportCmd.Flags().StringVarP(&dataFormat, "format", "t", "", "导出格式 jsonl/csv，为空时依据文件扩展名判断")
+	exportCmd.Flags().StringVarP(&dataPath, "output", "o", "", "本地导出文件路径")
+	importCmd.Flags().StringVarP(&address, "address", "a", "localhost:19877", "lily服务端地址，默认localhost")
+	importCmd.Flags().StringVarP(&dbName, "database", "d", "", "数据库名称")
+	importCmd.Flags().StringVarP(&formName, "form", "f", "", "表名称")
+	importCmd.Flags().StringVarP(&dataFormat, "format", "t", "", "导入格式 jsonl/csv，为空时依据文件扩展名判断")
+	importCmd.Flags().StringVarP(&dataPath, "input", "i", "", "本地导入文件路径")
+	importCmd.Flags().StringVarP(&rejectPath, "reject", "r", "", "本地无法导入行的写入文件路径，默认为导入文件路径加 .reject 后缀")
 }
 
 // Execute cmd start
@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"github.com/golang/snappy"
+	"io/ioutil"
+)
+
+// 数据记录头标记中的压缩方式，占用标记低4位
+const (
+	codecNone   byte = iota // codecNone 不压缩
+	codecGzip               // codecGzip gzip压缩
+	codecFlate              // codecFlate flate压缩
+	codecSnappy             // codecSnappy snappy压缩
+	// codecMask 压缩方式在记录头标记中的掩码
+	codecMask byte = 0x0f
+)
+
+// compression2codec 压缩方式转记录头标记中的压缩方式，未知压缩方式视为不压缩
+func compression2codec(compression string) byte {
+	switch compression {
+	default:
+		return codecNone
+	case CompressionGzip:
+		return codecGzip
+	case CompressionFlate:
+		return codecFlate
+	case CompressionSnappy:
+		return codecSnappy
+	}
+}
+
+// compress 按压缩方式压缩数据
+func compress(codec byte, data []byte) ([]byte, error) {
+	var (
+		buf bytes.Buffer
+		err error
+	)
+	switch codec {
+	default:
+		return data, nil
+	case codecSnappy:
+		return snappy.Encode(nil, data), nil
+	case codecGzip:
+		writer := gzip.NewWriter(&buf)
+		if _, err = writer.Write(data); nil != err {
+			return nil, err
+		}
+		if err = writer.Close(); nil != err {
+			return nil, err
+		}
+	case codecFlate:
+		var writer *flate.Writer
+		if writer, err = flate.NewWriter(&buf, flate.DefaultCompression); nil != err {
+			return nil, err
+		}
+		if _, err = writer.Write(data); nil != err {
+			return nil, err
+		}
+		if err = writer.Close(); nil != err {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// decompress 按压缩方式解压数据
+func decompress(codec byte, data []byte) ([]byte, error) {
+	switch codec {
+	default:
+		return nil, ErrRecordCorrupt
+	case codecNone:
+		return data, nil
+	case codecSnappy:
+		return snappy.Decode(nil, data)
+	case codecGzip:
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if nil != err {
+			return nil, err
+		}
+		defer func() { _ = reader.Close() }()
+		return ioutil.ReadAll(reader)
+	case codecFlate:
+		reader := flate.NewReader(bytes.NewReader(data))
+		defer func() { _ = reader.Close() }()
+		return ioutil.ReadAll(reader)
+	}
+}
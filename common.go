@@ -15,16 +15,30 @@
 package lily
 
 import (
+	"encoding/binary"
 	"errors"
 	"github.com/aberic/gnomon"
 	"hash/crc32"
+	"io"
 	"os"
 	"path/filepath"
 	"reflect"
+	sort2 "sort"
 	"strconv"
 	"strings"
 )
 
+// ioError 是否为读写文件等I/O错误，重做此类错误的操作需中止恢复
+func ioError(err error) bool {
+	var (
+		pathErr    *os.PathError
+		linkErr    *os.LinkError
+		syscallErr *os.SyscallError
+	)
+	return errors.As(err, &pathErr) || errors.As(err, &linkErr) || errors.As(err, &syscallErr) ||
+		errors.Is(err, io.ErrShortWrite) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
 // levelDistance 根据节点所在层级获取当前节点内部子节点之间的差
 func levelDistance(level uint8) uint64 {
 	switch level {
@@ -45,6 +59,79 @@ func hash(key string) uint64 {
 	return uint64(crc32.ChecksumIEEE([]byte(key)))
 }
 
+// orderedKey 字符串索引hashKey，取字符串前8个字节按大端组成，不足8字节以0补齐
+//
+// hashKey 的大小顺序与字符串字典序一致，前8个字节相同的字符串hashKey相同，需比较完整字符串区分
+func orderedKey(key string) uint64 {
+	var prefix [8]byte
+	copy(prefix[:], key)
+	return binary.BigEndian.Uint64(prefix[:])
+}
+
+// orderedKeyCeil 与 orderedKey 相同，不足8字节以0xff补齐，作为以 key 为前缀的全部key中最大的hashKey
+func orderedKeyCeil(key string) uint64 {
+	prefix := [8]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	copy(prefix[:], key)
+	return binary.BigEndian.Uint64(prefix[:])
+}
+
+// compareIndexValue 按索引顺序比较两个索引字段值，hashKey相同的字符串继续比较完整字符串
+//
+// a小于b返回-1，相等返回0，大于返回1，不支持索引的值视为hashKey为0
+func compareIndexValue(a, b interface{}) int {
+	_, hashKeyA, _ := type2index(a)
+	_, hashKeyB, _ := type2index(b)
+	switch {
+	case hashKeyA < hashKeyB:
+		return -1
+	case hashKeyA > hashKeyB:
+		return 1
+	}
+	stringA, okA := a.(string)
+	stringB, okB := b.(string)
+	if okA && okB {
+		return strings.Compare(stringA, stringB)
+	}
+	return 0
+}
+
+// rangeLinks 遍历节点下所有叶子节点的链表对象
+func rangeLinks(nodal Nodal, fn func(ln Link)) {
+	nodal.rLock()
+	nodes := nodal.getNodes()
+	nodal.rUnLock()
+	if nil != nodes {
+		for _, nd := range nodes {
+			rangeLinks(nd, fn)
+		}
+		return
+	}
+	leaf := nodal.(Leaf)
+	leaf.rLock()
+	links := leaf.getLinks()
+	leaf.rUnLock()
+	for _, ln := range links {
+		fn(ln)
+	}
+}
+
+// linkHashKey 根据链表对象在树中的位置还原其索引hashKey
+func linkHashKey(ln Link) uint64 {
+	min, _ := nodalKeyRange(ln.getNodal())
+	return min
+}
+
+// nodalKeyRange 根据节点在树中的位置获取其下全部索引hashKey的取值范围，叶子节点的最小值与最大值相同
+func nodalKeyRange(nodal Nodal) (min, max uint64) {
+	if nil == nodal || nil == nodal.getPreNode() {
+		return 0, ^uint64(0)
+	}
+	for nd := nodal; nil != nd && nd.getPreNode() != nil; nd = nd.getPreNode() {
+		min += uint64(nd.getDegreeIndex()) * levelDistance(nd.getLevel()-1)
+	}
+	return min, min + levelDistance(nodal.getLevel()-1) - 1
+}
+
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 // matchableData 'Nodal'内子节点数组二分查找是否存在指定值
@@ -146,6 +233,13 @@ func rmDataDir(dataName string) (err error) {
 	return nil
 }
 
+// pathDatabaseWALFile 库预写日志文件路径
+//
+// dataID 数据库唯一id
+func pathDatabaseWALFile(dataID string) string {
+	return filepath.Join(obtainConf().DataDir, dataID, "database.wal")
+}
+
 // mkFormResource 创建表资源
 //
 // dataID 数据库唯一id
@@ -194,8 +288,11 @@ func rmFormDir(dataID, formID string) (err error) {
 //
 // formID 表唯一id
 func mkFormDataFile(dataID, formID string) (err error) {
-	_, err = os.Create(pathFormDataFile(dataID, formID))
-	return
+	var file *os.File
+	if file, err = os.Create(pathFormDataFile(dataID, formID, 0)); nil != err {
+		return
+	}
+	return file.Close()
 }
 
 // pathFormDir 表目录
@@ -218,9 +315,65 @@ func pathFormIndexFile(dataID, formID, indexID string) string {
 	return strings.Join([]string{obtainConf().DataDir, string(filepath.Separator), dataID, string(filepath.Separator), formID, string(filepath.Separator), indexID, ".idx"}, "")
 }
 
-func pathFormDataFile(dataID, formID string) string {
+// pathFormCompactManifest 表压缩替换清单文件路径
+//
+// dataID 数据库唯一id
+//
+// formID 表唯一id
+func pathFormCompactManifest(dataID, formID string) string {
+	return filepath.Join(obtainConf().DataDir, dataID, formID, "compact.manifest")
+}
+
+// pathFormDataFile 表数据分段文件路径
+//
+// dataID 数据库唯一id
+//
+// formID 表唯一id
+//
+// segment 数据分段文件序号
+func pathFormDataFile(dataID, formID string, segment uint32) string {
+	return strings.Join([]string{obtainConf().DataDir, string(filepath.Separator), dataID, string(filepath.Separator), formID, string(filepath.Separator), strconv.FormatUint(uint64(segment), 10), ".dat"}, "")
+}
+
+// pathFormLegacyDataFile 未分段的旧版表数据文件路径
+func pathFormLegacyDataFile(dataID, formID string) string {
 	return filepath.Join(obtainConf().DataDir, dataID, formID, "form.dat")
-	//return strings.Join([]string{dataDir, string(filepath.Separator), dataID, string(filepath.Separator), formID, string(filepath.Separator), strconv.Itoa(fileIndex), ".dat"}, "")
+}
+
+// formSegments 表目录下现有数据分段文件序号集合，升序排列
+//
+// dataID 数据库唯一id
+//
+// formID 表唯一id
+func formSegments(dataID, formID string) []uint32 {
+	var segments []uint32
+	paths, _ := filepath.Glob(filepath.Join(pathFormDir(dataID, formID), "*.dat"))
+	for _, path := range paths {
+		segment, err := strconv.ParseUint(strings.TrimSuffix(filepath.Base(path), ".dat"), 10, 32)
+		if nil != err {
+			continue
+		}
+		segments = append(segments, uint32(segment))
+	}
+	sort2.Slice(segments, func(i, j int) bool { return segments[i] < segments[j] })
+	return segments
+}
+
+// migrateFormDataFile 将未分段的旧版表数据文件迁移为第0个数据分段文件
+//
+// 旧版索引记录中没有分段序号，恢复时均视为第0个数据分段
+func migrateFormDataFile(dataID, formID string) error {
+	legacyPath := pathFormLegacyDataFile(dataID, formID)
+	if !gnomon.FilePathExists(legacyPath) {
+		return nil
+	}
+	segmentPath := pathFormDataFile(dataID, formID, 0)
+	if info, err := os.Stat(segmentPath); nil == err && info.Size() > 0 {
+		return errors.New(strings.Join([]string{"both", legacyPath, "and", segmentPath, "exist"}, " "))
+	}
+	// 空的第0个数据分段文件可能已被缓存
+	defer store().invalidate(segmentPath)
+	return os.Rename(legacyPath, segmentPath)
 }
 
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -253,7 +406,8 @@ func type2index(value interface{}) (key string, hashKey uint64, support bool) {
 		key = strconv.FormatInt(i64, 10)
 		hashKey = uint64(i64 + 9223372036854775807 + 1)
 	case string:
-		hashKey = hash(value)
+		key = value
+		hashKey = orderedKey(value)
 	case bool:
 		if value {
 			key = "true"
@@ -288,7 +442,7 @@ func valueType2index(value *reflect.Value) (key string, hashKey uint64, support
 		hashKey = uint64(i64 + 9223372036854775807 + 1)
 	case reflect.String:
 		key = value.String()
-		hashKey = hash(key)
+		hashKey = orderedKey(key)
 	case reflect.Bool:
 		if value.Bool() {
 			key = value.String()
@@ -301,32 +455,4 @@ func valueType2index(value *reflect.Value) (key string, hashKey uint64, support
 	return
 }
 
-func value2hashKey(value *reflect.Value) (hashKey uint64, support bool) {
-	support = true
-	switch value.Kind() {
-	default:
-		return 0, false
-	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int, reflect.Int64:
-		i64 := value.Int()
-		hashKey = uint64(i64 + 9223372036854775807 + 1)
-	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint, reflect.Uint64, reflect.Uintptr:
-		ui64 := value.Uint()
-		if ui64 > 9223372036854775808 { // 9223372036854775808 = 1 << 63
-			return 0, false
-		}
-		hashKey = ui64 + 9223372036854775807 + 1
-	case reflect.Float32, reflect.Float64:
-		hashKey = uint64(gnomon.ScaleFloat64toInt64(value.Float(), 4) + 9223372036854775807 + 1)
-	case reflect.String:
-		hashKey = hash(value.String())
-	case reflect.Bool:
-		if value.Bool() {
-			hashKey = 1
-		} else {
-			hashKey = 2
-		}
-	}
-	return
-}
-
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
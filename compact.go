@@ -0,0 +1,456 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"errors"
+	"github.com/aberic/gnomon"
+	"github.com/aberic/gnomon/log"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	sort2 "sort"
+	"strings"
+	"time"
+)
+
+// compactSuffix 压缩过程中生成的临时文件后缀
+const compactSuffix = ".compact"
+
+// CompactResult 表数据文件压缩结果
+type CompactResult struct {
+	Records    int64 // Records 压缩后保留的有效记录数，含保留的历史版本
+	SizeBefore int64 // SizeBefore 压缩前数据文件大小
+	SizeAfter  int64 // SizeAfter 压缩后数据文件大小
+	Resealed   int64 // Resealed 以当前数据密钥重新加密的记录数
+}
+
+const (
+	// compactRename 替换清单操作，将临时文件替换为正式文件
+	compactRename = "R"
+	// compactRemove 替换清单操作，删除压缩后不再使用的数据分段文件
+	compactRemove = "D"
+)
+
+// recordLocation 数据记录位置
+type recordLocation struct {
+	segment   uint32 // 数据分段文件序号
+	seekStart int64  // 起始seek
+}
+
+// movedRecord 压缩后的数据记录位置
+type movedRecord struct {
+	recordLocation
+	seekLast int // 持续seek，重新加密后可能变化
+}
+
+// compactor 表数据文件压缩器
+//
+// 压缩流程：收集索引引用的有效记录 -> 写入临时数据分段文件及临时索引文件 -> 写入替换清单 -> 替换文件并更新内存索引 -> 删除清单
+//
+// 替换清单用于崩溃恢复，清单存在则表示替换已开始，重启时继续完成替换；清单不存在则丢弃所有临时文件
+type compactor struct {
+	dataID   string
+	form     Form
+	live     map[string][]Link              // 索引ID对应的有效链表集合
+	dead     []Link                         // 指向无效记录的链表集合，替换完成后从索引树中移除
+	spans    map[recordLocation]int         // 有效记录位置及持续seek
+	moved    map[recordLocation]movedRecord // 有效记录原位置对应新位置
+	relink   map[recordLocation]bool        // 记录了上一版本位置的有效记录，写入时改写为上一版本的新位置
+	keys     *keyring                       // 库数据密钥环，启用加密时有效记录以当前数据密钥重新加密
+	resealed int64                          // 重新加密的记录数
+	segments uint32                         // 压缩后数据分段文件数量
+}
+
+// compact 压缩表数据文件，仅保留仍被索引引用的有效记录
+//
+// 压缩期间持有表写锁，写入将等待压缩完成，读取不受影响，仅在替换文件的瞬间等待
+func (d *database) compact(formName string) (*CompactResult, error) {
+	form := d.getForms()[formName]
+	if nil == form {
+		return nil, formIsInvalid(formName)
+	}
+	if form.getEngine() != EngineFile {
+		return nil, ErrEngineUnsupported
+	}
+	defer form.unLock()
+	form.lock()
+	if form.isDropped() {
+		return nil, ErrFormDropped
+	}
+	c := &compactor{
+		dataID: d.id,
+		form:   form,
+		live:   map[string][]Link{},
+		spans:  map[recordLocation]int{},
+		moved:  map[recordLocation]movedRecord{},
+		relink: map[recordLocation]bool{},
+		keys:   d.keys,
+	}
+	return c.run()
+}
+
+func (c *compactor) run() (*CompactResult, error) {
+	var (
+		result = &CompactResult{}
+		err    error
+	)
+	formID := c.form.getID()
+	oldSegments := formSegments(c.dataID, formID)
+	for _, segment := range oldSegments {
+		if info, err := os.Stat(pathFormDataFile(c.dataID, formID, segment)); nil == err {
+			result.SizeBefore += info.Size()
+		}
+	}
+	c.collect()
+	if result.SizeAfter, err = c.writeData(); nil != err {
+		c.clean()
+		return nil, err
+	}
+	var ops []string
+	for segment := uint32(0); segment < c.segments; segment++ {
+		ops = append(ops, strings.Join([]string{compactRename, pathFormDataFile(c.dataID, formID, segment)}, " "))
+	}
+	for _, idx := range c.form.getIndexes() {
+		indexPath := pathFormIndexFile(c.dataID, formID, idx.getID())
+		if err = c.writeIndex(indexPath, c.live[idx.getID()]); nil != err {
+			c.clean()
+			return nil, err
+		}
+		ops = append(ops, strings.Join([]string{compactRename, indexPath}, " "))
+	}
+	for _, segment := range oldSegments {
+		if segment >= c.segments {
+			ops = append(ops, strings.Join([]string{compactRemove, pathFormDataFile(c.dataID, formID, segment)}, " "))
+		}
+	}
+	if err = writeFileSync(pathFormCompactManifest(c.dataID, formID), []byte(strings.Join(ops, "\n"))); nil != err {
+		c.clean()
+		return nil, err
+	}
+	if err = c.swap(ops); nil != err {
+		return nil, err
+	}
+	result.Records = int64(len(c.spans))
+	result.Resealed = c.resealed
+	log.Info("compact",
+		log.Field("form", c.form.getName()),
+		log.Field("records", result.Records),
+		log.Field("segments", c.segments),
+		log.Field("resealed", c.resealed),
+		log.Field("sizeBefore", result.SizeBefore),
+		log.Field("sizeAfter", result.SizeAfter))
+	return result, nil
+}
+
+// collect 遍历全部索引，区分有效链表与失效链表
+//
+// 表保留多个版本时，沿主键索引有效记录的版本链一并保留上一版本记录
+//
+// 非主键索引链表仅在指向key当前记录时有效，记录更新索引值或删除后遗留的链表一并回收
+func (c *compactor) collect() {
+	var (
+		valid   = map[recordLocation]bool{}
+		current = map[recordLocation]bool{} // 各key当前记录位置
+		now     = time.Now().UnixNano()
+	)
+	// 没有主键索引的表以 recordHeads 确认当前记录
+	if !hasDefaultIndex(c.form) {
+		current = c.form.getHeads().snapshot()
+	}
+	// 主键索引优先遍历，使有效记录首次校验时即可沿版本链保留上一版本
+	indexes := make([]Index, 0, len(c.form.getIndexes()))
+	for _, idx := range c.form.getIndexes() {
+		if idx.getKeyStructure() == indexDefaultID {
+			indexes = append([]Index{idx}, indexes...)
+		} else {
+			indexes = append(indexes, idx)
+		}
+	}
+	for _, idx := range indexes {
+		indexID := idx.getID()
+		rangeLinks(idx.getNode(), func(ln Link) {
+			if ln.getSeekStartIndex() == -1 { // 索引从未成功落盘
+				c.dead = append(c.dead, ln)
+				return
+			}
+			location := recordLocation{segment: ln.getSegment(), seekStart: ln.getSeekStart()}
+			ok, checked := valid[location]
+			if !checked {
+				vd, err := ln.getRecord()
+				ok = nil == err && vd.I && !vd.expired(now)
+				valid[location] = ok
+				if ok {
+					c.retain(location, vd, idx.getKeyStructure() == indexDefaultID)
+				}
+			}
+			if idx.getKeyStructure() == indexDefaultID {
+				current[location] = true
+			} else if !idx.isPrimary() && !current[location] {
+				ok = false
+			}
+			if !ok {
+				c.dead = append(c.dead, ln)
+				return
+			}
+			c.spans[location] = ln.getSeekLast()
+			c.live[indexID] = append(c.live[indexID], ln)
+		})
+	}
+}
+
+// retain 记录有效记录的版本链，primary 为主键索引引用的记录时保留表保留版本数以内的上一版本记录
+func (c *compactor) retain(location recordLocation, vd *valueData, primary bool) {
+	if vd.L == 0 {
+		return
+	}
+	c.relink[location] = true
+	if !primary {
+		return
+	}
+	for count := uint32(1); count < c.form.getVersions() && vd.L > 0; count++ {
+		prevLocation := recordLocation{segment: vd.S, seekStart: vd.O}
+		prev, err := store().readRecord(pathFormDataFile(c.dataID, c.form.getID(), vd.S), vd.O, vd.L, c.keys)
+		if nil != err || prev.K != vd.K || prev.version()+1 != vd.version() {
+			return
+		}
+		c.spans[prevLocation] = vd.L
+		if prev.L > 0 {
+			c.relink[prevLocation] = true
+		}
+		vd = prev
+	}
+}
+
+// writeData 按原有顺序将有效记录写入临时数据分段文件，返回临时数据分段文件总大小
+func (c *compactor) writeData() (int64, error) {
+	var (
+		sources = map[uint32]*os.File{}
+		dst     *os.File
+		offset  int64
+		total   int64
+		err     error
+	)
+	locations := make([]recordLocation, 0, len(c.spans))
+	for location := range c.spans {
+		locations = append(locations, location)
+	}
+	sort2.Slice(locations, func(i, j int) bool {
+		if locations[i].segment != locations[j].segment {
+			return locations[i].segment < locations[j].segment
+		}
+		return locations[i].seekStart < locations[j].seekStart
+	})
+	defer func() {
+		for _, src := range sources {
+			_ = src.Close()
+		}
+		if nil != dst {
+			_ = dst.Close()
+		}
+	}()
+	roll := func() error {
+		if nil != dst {
+			if err := dst.Sync(); nil != err {
+				return err
+			}
+			_ = dst.Close()
+		}
+		var err error
+		dst, err = os.OpenFile(pathFormDataFile(c.dataID, c.form.getID(), c.segments)+compactSuffix, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+		c.segments++
+		offset = 0
+		return err
+	}
+	if err = roll(); nil != err {
+		return 0, err
+	}
+	for _, location := range locations {
+		src, ok := sources[location.segment]
+		if !ok {
+			if src, err = os.OpenFile(pathFormDataFile(c.dataID, c.form.getID(), location.segment), os.O_RDONLY, 0644); nil != err {
+				return 0, err
+			}
+			sources[location.segment] = src
+		}
+		data := make([]byte, c.spans[location])
+		if _, err = src.ReadAt(data, location.seekStart); nil != err {
+			return 0, err
+		}
+		var resealed bool
+		if data, resealed, err = resealRecord(data, c.keys); nil != err {
+			return 0, err
+		}
+		if resealed {
+			c.resealed++
+		}
+		if c.relink[location] {
+			if data, err = c.relinkRecord(data); nil != err {
+				return 0, err
+			}
+		}
+		if offset > 0 && offset+int64(len(data)) > obtainConf().segmentSize() {
+			if err = roll(); nil != err {
+				return 0, err
+			}
+		}
+		if _, err = dst.Write(data); nil != err {
+			return 0, err
+		}
+		c.moved[location] = movedRecord{recordLocation: recordLocation{segment: c.segments - 1, seekStart: offset}, seekLast: len(data)}
+		offset += int64(len(data))
+		total += int64(len(data))
+	}
+	return total, dst.Sync()
+}
+
+// relinkRecord 将记录中的上一版本位置改写为压缩后的新位置，上一版本未保留时清除
+//
+// 上一版本记录位置在前，按原有顺序写入时已先于本记录写入
+func (c *compactor) relinkRecord(data []byte) ([]byte, error) {
+	vd, err := decodeRecord(data, c.keys)
+	if nil != err {
+		return nil, err
+	}
+	if moved, ok := c.moved[recordLocation{segment: vd.S, seekStart: vd.O}]; ok && c.spans[recordLocation{segment: vd.S, seekStart: vd.O}] == vd.L {
+		vd.S, vd.O, vd.L = moved.segment, moved.seekStart, moved.seekLast
+	} else {
+		vd.S, vd.O, vd.L = 0, 0, 0
+	}
+	return encodeRecord(vd, compression2codec(c.form.getCompression()), c.keys)
+}
+
+// writeIndex 将有效链表以新的数据位置写入临时索引文件
+func (c *compactor) writeIndex(indexPath string, links []Link) error {
+	buf := newIndexBuffer()
+	for _, ln := range links {
+		location := c.moved[recordLocation{segment: ln.getSegment(), seekStart: ln.getSeekStart()}]
+		buf.Write(indexEntry(linkHashKey(ln), ln.getMD516Key(), location.segment, location.seekStart, location.seekLast))
+	}
+	return writeFileSync(indexPath+compactSuffix, buf.Bytes())
+}
+
+// swap 按替换清单替换数据分段文件及索引文件，并同步更新内存中的链表位置
+func (c *compactor) swap(ops []string) error {
+	swap := c.form.getSwapLocker()
+	defer swap.unLock()
+	swap.lock()
+	if err := applyCompactOps(ops); nil != err {
+		// 清单仍在，重启时会继续完成替换
+		log.Error("compact swap failed", log.Err(err))
+		return err
+	}
+	for _, links := range c.live {
+		for n, ln := range links {
+			location := c.moved[recordLocation{segment: ln.getSegment(), seekStart: ln.getSeekStart()}]
+			ln.setSegment(location.segment)
+			ln.setSeekStart(location.seekStart)
+			ln.setSeekLast(location.seekLast)
+			ln.setSeekStartIndex(indexEntryPosition(n))
+		}
+	}
+	for _, ln := range c.dead {
+		ln.getNodal().(Leaf).removeLink(ln)
+	}
+	if !hasDefaultIndex(c.form) {
+		c.form.getHeads().relocate(c.moved)
+	}
+	c.form.setSegment(c.segments - 1)
+	return os.Remove(pathFormCompactManifest(c.dataID, c.form.getID()))
+}
+
+// clean 压缩失败时清理临时文件
+func (c *compactor) clean() {
+	cleanCompact(pathFormDir(c.dataID, c.form.getID()))
+}
+
+// applyCompactOps 执行替换清单中的操作，已执行过的操作将被跳过
+func applyCompactOps(ops []string) error {
+	for _, op := range ops {
+		if gnomon.StringIsEmpty(op) {
+			continue
+		}
+		if len(op) < 3 || op[1] != ' ' {
+			return errCompactOp(op)
+		}
+		switch kind, path := op[:1], op[2:]; kind {
+		default:
+			return errCompactOp(op)
+		case compactRename:
+			if !gnomon.FilePathExists(path + compactSuffix) {
+				continue
+			}
+			if err := os.Rename(path+compactSuffix, path); nil != err {
+				return err
+			}
+			store().invalidate(path)
+		case compactRemove:
+			if err := os.Remove(path); nil != err && !os.IsNotExist(err) {
+				return err
+			}
+			store().invalidate(path)
+		}
+	}
+	return nil
+}
+
+// errCompactOp 替换清单中的操作无法识别
+func errCompactOp(op string) error {
+	return errors.New(strings.Join([]string{"compact manifest op", op, "is invalid"}, " "))
+}
+
+// recoverCompact 恢复表时处理上次未完成的压缩
+//
+// 替换清单存在则继续完成替换，否则丢弃所有临时文件
+func recoverCompact(dataID, formID string) error {
+	manifest := pathFormCompactManifest(dataID, formID)
+	if gnomon.FilePathExists(manifest) {
+		data, err := ioutil.ReadFile(manifest)
+		if nil != err {
+			return err
+		}
+		if err = applyCompactOps(strings.Split(string(data), "\n")); nil != err {
+			return err
+		}
+		return os.Remove(manifest)
+	}
+	cleanCompact(pathFormDir(dataID, formID))
+	return nil
+}
+
+// cleanCompact 删除表目录下所有压缩临时文件
+func cleanCompact(formDir string) {
+	paths, _ := filepath.Glob(filepath.Join(formDir, strings.Join([]string{"*", compactSuffix}, "")))
+	for _, path := range paths {
+		_ = os.Remove(path)
+	}
+}
+
+// writeFileSync 写入文件并落盘
+func writeFileSync(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if nil != err {
+		return err
+	}
+	if _, err = file.Write(data); nil != err {
+		_ = file.Close()
+		return err
+	}
+	if err = file.Sync(); nil != err {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,199 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/aberic/gnomon"
+	"reflect"
+	"strings"
+)
+
+// compositeSeparator 复合索引字段名称分隔符，复合索引结构名由各字段名称按顺序以该分隔符组成，如'tenant,status'
+const compositeSeparator = ","
+
+// ErrCompositeInvalid 复合索引字段名称为空、重复、包含分隔符或少于2个
+var ErrCompositeInvalid = errors.New("composite index needs at least two distinct and non-empty key structures")
+
+// compositeKeyStructure 校验复合索引字段名称集合并组成复合索引结构名
+func compositeKeyStructure(keyStructures []string) (string, error) {
+	if len(keyStructures) < 2 {
+		return "", ErrCompositeInvalid
+	}
+	exist := map[string]bool{}
+	for _, keyStructure := range keyStructures {
+		if gnomon.StringIsEmpty(keyStructure) || strings.Contains(keyStructure, compositeSeparator) || exist[keyStructure] {
+			return "", ErrCompositeInvalid
+		}
+		exist[keyStructure] = true
+	}
+	return strings.Join(keyStructures, compositeSeparator), nil
+}
+
+// compositeFields 复合索引结构名对应的字段名称集合，单字段索引返回nil
+func compositeFields(keyStructure string) []string {
+	if !strings.Contains(keyStructure, compositeSeparator) {
+		return nil
+	}
+	return strings.Split(keyStructure, compositeSeparator)
+}
+
+// compositeIndexKey 根据复合索引各字段值组成复合索引key
+//
+// key 为各字段按顺序编码后的拼接，hashKey 为 key 前8个字节，二者的大小顺序与各字段依次比较的顺序一致
+func compositeIndexKey(fields []string, value interface{}) (string, uint64, error) {
+	var key []byte
+	for _, field := range fields {
+		item, ok := indexField(field, value)
+		if !ok {
+			return "", 0, errors.New(strings.Join([]string{"composite index field", field, "is invalid"}, " "))
+		}
+		encoded, ok := encodeIndexField(item)
+		if !ok {
+			return "", 0, errors.New(strings.Join([]string{"composite index field", field, "with value is invalid"}, " "))
+		}
+		key = append(key, encoded...)
+	}
+	return string(key), orderedKey(string(key)), nil
+}
+
+// indexField 根据由'.'组成的字段名称获取存储数据中对应字段值
+func indexField(keyStructure string, value interface{}) (reflect.Value, bool) {
+	item := reflect.ValueOf(value)
+	for _, param := range strings.Split(keyStructure, ".") {
+		switch item.Kind() {
+		default:
+			return reflect.Value{}, false
+		case reflect.Interface:
+			item = item.Elem()
+			if item.Kind() != reflect.Map {
+				return reflect.Value{}, false
+			}
+			fallthrough
+		case reflect.Map:
+			if item.Type().Key().Kind() != reflect.String {
+				return reflect.Value{}, false
+			}
+			item = item.MapIndex(reflect.ValueOf(param))
+		case reflect.Ptr:
+			if item = item.Elem(); item.Kind() != reflect.Struct {
+				return reflect.Value{}, false
+			}
+			item = item.FieldByName(param)
+		}
+		if !item.IsValid() {
+			return reflect.Value{}, false
+		}
+	}
+	if item.Kind() == reflect.Interface {
+		item = item.Elem()
+	}
+	return item, item.IsValid()
+}
+
+// encodeIndexField 将单个字段值编码为保持大小顺序的字节
+//
+// 整数及浮点数为与单字段索引hashKey一致的8字节大端整数，字符串以0结尾，布尔值为1个字节
+func encodeIndexField(value reflect.Value) ([]byte, bool) {
+	var number uint64
+	switch value.Kind() {
+	default:
+		return nil, false
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int, reflect.Int64:
+		number = uint64(value.Int() + 9223372036854775807 + 1)
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		if value.Uint() > 9223372036854775807 {
+			return nil, false
+		}
+		number = value.Uint() + 9223372036854775807 + 1
+	case reflect.Float32, reflect.Float64:
+		number = uint64(gnomon.ScaleFloat64toInt64(value.Float(), 4) + 9223372036854775807 + 1)
+	case reflect.String:
+		return append([]byte(value.String()), 0), true
+	case reflect.Bool:
+		if value.Bool() {
+			return []byte{1}, true
+		}
+		return []byte{2}, true
+	}
+	encoded := make([]byte, 8)
+	binary.BigEndian.PutUint64(encoded, number)
+	return encoded, true
+}
+
+// getCompositeCondition 尝试采用复合索引检索，返回覆盖字段最多的复合索引
+//
+// 复合索引自首个字段起依次被等于条件覆盖的字段，以及其后一个被大于或小于条件覆盖的字段，共同确定索引hashKey范围
+//
+// covered 为被条件覆盖的字段数，未覆盖首个字段的复合索引不被采用
+func (s *Selector) getCompositeCondition() (index Index, nc *nodeCondition, covered int) {
+	for _, idx := range s.database.getForms()[s.formName].getIndexes() {
+		fields := compositeFields(idx.getKeyStructure())
+		if nil == fields || !idx.isReady() {
+			continue
+		}
+		var (
+			prefix, lower, upper []byte
+			count                int
+		)
+		for _, field := range fields {
+			if encoded, ok := s.compositeConditionValue(field, "eq"); ok {
+				prefix = append(prefix, encoded...)
+				count++
+				continue
+			}
+			gt, okGT := s.compositeConditionValue(field, "gt")
+			lt, okLT := s.compositeConditionValue(field, "lt")
+			if okGT {
+				lower = append(append([]byte{}, prefix...), gt...)
+			}
+			if okLT {
+				upper = append(append([]byte{}, prefix...), lt...)
+			}
+			if okGT || okLT {
+				count++
+			}
+			break
+		}
+		if count <= covered {
+			continue
+		}
+		if nil == lower {
+			lower = prefix
+		}
+		if nil == upper {
+			upper = prefix
+		}
+		ns := &nodeSelector{keyRange: true, min: orderedKey(string(lower)), max: orderedKeyCeil(string(upper))}
+		index, covered = idx, count
+		nc = &nodeCondition{nss: []*nodeSelector{ns}}
+		for level, next := 2, nc; level <= 5; level++ {
+			next.nextNode = &nodeCondition{nss: []*nodeSelector{ns}}
+			next = next.nextNode
+		}
+	}
+	return
+}
+
+// compositeConditionValue 获取指定字段首个指定条件的编码后比较对象
+func (s *Selector) compositeConditionValue(param, cond string) ([]byte, bool) {
+	for _, condition := range s.Conditions {
+		if condition.Param == param && condition.Cond == cond {
+			return encodeIndexField(reflect.ValueOf(condition.Value))
+		}
+	}
+	return nil, false
+}
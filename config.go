@@ -53,7 +53,14 @@ type Conf struct {
 	Port                     string `yaml:"Port"`                     // Port 开放端口，便于其它应用访问
 	RootDir                  string `yaml:"RootDir"`                  // RootDir Lily服务默认存储路径
 	DataDir                  string `yaml:"DataDir"`                  // DataDir Lily服务数据默认存储路径
-	LimitOpenFile            int32  `yaml:"LimitOpenFile"`            // LimitOpenFile 限制打开文件描述符次数
+	LimitOpenFile            int32  `yaml:"LimitOpenFile"`            // LimitOpenFile 限制同时缓存的数据及索引文件句柄数量
+	FormSegmentSize          int32  `yaml:"FormSegmentSize"`          // FormSegmentSize 每个表数据分段文件的最大尺寸，超过后写入新的分段文件 单位：M
+	MasterKeyFile            string `yaml:"MasterKeyFile"`            // MasterKeyFile 主密钥文件地址，配置后启用数据加密
+	SyncMode                 string `yaml:"SyncMode"`                 // SyncMode 落盘策略(none/interval/always)，可按库覆盖
+	SyncInterval             int32  `yaml:"SyncInterval"`             // SyncInterval interval 落盘策略的落盘间隔 单位：毫秒
+	Engine                   string `yaml:"Engine"`                   // Engine 新建表未指定存储引擎时使用的存储引擎(file/memory)
+	SweepInterval            int32  `yaml:"SweepInterval"`            // SweepInterval 过期数据回收间隔 单位：秒
+	CatalogGenerations       int32  `yaml:"CatalogGenerations"`       // CatalogGenerations lily.sync 保留的历史代数量，当前文件损坏时自动回滚
 	TLS                      bool   `yaml:"TLS"`                      // TLS 是否开启 TLS
 	TLSServerKeyFile         string `yaml:"TLSServerKeyFile"`         // TLSServerKeyFile lily服务私钥
 	TLSServerCertFile        string `yaml:"TLSServerCertFile"`        // TLSServerCertFile lily服务数字证书
@@ -132,6 +139,28 @@ func (c *Conf) scanDefault() (*Conf, error) {
 	if c.LimitOpenFile < 1000 {
 		c.LimitOpenFile = 10000
 	}
+	if c.FormSegmentSize < 1 {
+		c.FormSegmentSize = 512
+	}
+	switch c.SyncMode {
+	default:
+		return nil, errors.New("sync mode must be one of none, interval and always")
+	case "":
+		c.SyncMode = SyncModeInterval
+	case SyncModeNone, SyncModeInterval, SyncModeAlways:
+	}
+	if c.SyncInterval < 1 {
+		c.SyncInterval = 1000
+	}
+	if gnomon.StringIsEmpty(c.Engine) {
+		c.Engine = EngineFile
+	}
+	if c.SweepInterval < 1 {
+		c.SweepInterval = 60
+	}
+	if c.CatalogGenerations < 1 {
+		c.CatalogGenerations = 3
+	}
 	if c.TLS {
 		if gnomon.StringIsEmpty(c.TLSServerKeyFile) || gnomon.StringIsEmpty(c.TLSServerCertFile) {
 			return nil, errors.New("tls server key file or cert file is nil")
@@ -147,6 +176,11 @@ func (c *Conf) scanDefault() (*Conf, error) {
 	return c, nil
 }
 
+// segmentSize 表数据分段文件的最大字节数
+func (c *Conf) segmentSize() int64 {
+	return int64(c.FormSegmentSize) << 20
+}
+
 // yaml2Conf YML转配置对象
 func (c *Conf) yaml2Conf(filePath string) error {
 	data, err := ioutil.ReadFile(filePath)
@@ -170,6 +204,13 @@ func (c *Conf) conf2RPC() *api.Conf {
 		DataDir:                  c.DataDir,
 		LogDir:                   c.LogDir,
 		LimitOpenFile:            c.LimitOpenFile,
+		FormSegmentSize:          c.FormSegmentSize,
+		MasterKeyFile:            c.MasterKeyFile,
+		SyncMode:                 c.SyncMode,
+		SyncInterval:             c.SyncInterval,
+		Engine:                   c.Engine,
+		SweepInterval:            c.SweepInterval,
+		CatalogGenerations:       c.CatalogGenerations,
 		TLS:                      c.TLS,
 		TLSServerKeyFile:         c.TLSServerKeyFile,
 		TLSServerCertFile:        c.TLSServerCertFile,
@@ -189,6 +230,13 @@ func (c *Conf) rpc2Conf(conf *api.Conf) {
 	c.DataDir = conf.DataDir
 	c.LogDir = conf.LogDir
 	c.LimitOpenFile = conf.LimitOpenFile
+	c.FormSegmentSize = conf.FormSegmentSize
+	c.MasterKeyFile = conf.MasterKeyFile
+	c.SyncMode = conf.SyncMode
+	c.SyncInterval = conf.SyncInterval
+	c.Engine = conf.Engine
+	c.SweepInterval = conf.SweepInterval
+	c.CatalogGenerations = conf.CatalogGenerations
 	c.TLS = conf.TLS
 	c.TLSServerKeyFile = conf.TLSServerKeyFile
 	c.TLSServerCertFile = conf.TLSServerCertFile
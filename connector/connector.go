@@ -0,0 +1,122 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package connector
+
+import (
+	"errors"
+	"github.com/aberic/gnomon"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrEngineExist 自定义error信息
+	ErrEngineExist = errors.New("engine already registered")
+	// ErrEngineNameIsNil 自定义error信息
+	ErrEngineNameIsNil = errors.New("engine name can not be nil")
+)
+
+var (
+	engines    = map[string]Engine{}
+	enginesMux sync.RWMutex
+)
+
+// Engine 存储引擎
+//
+// 表创建时依据引擎名称选择存储引擎，表的全部数据读写均经由引擎打开的 Storage 完成
+type Engine interface {
+	// Name 存储引擎名称，全局唯一
+	Name() string
+	// Open 打开表，返回表存储对象
+	//
+	// 新建表及服务重启恢复表时均会调用
+	Open(meta *FormMeta) (Storage, error)
+}
+
+// FormMeta 表描述
+type FormMeta struct {
+	DatabaseID   string      // DatabaseID 数据库唯一ID
+	DatabaseName string      // DatabaseName 数据库名称
+	FormID       string      // FormID 表唯一ID
+	FormName     string      // FormName 表名称
+	FormType     string      // FormType 表类型 SQL/Doc
+	Compression  string      // Compression 表数据压缩方式，引擎可忽略
+	Context      interface{} // Context 宿主附带的表上下文，由对应引擎解释，引擎可忽略
+}
+
+// Storage 表存储对象
+//
+// 同一表的方法会被并发调用，由实现方保证并发安全
+type Storage interface {
+	// Put 存储数据
+	//
+	// update 本次是否为更新操作，false 时key已存在则返回错误
+	//
+	// expire 过期时间，unix纳秒，0表示永不过期
+	//
+	// 返回表当前自增ID值
+	Put(key string, value interface{}, update bool, expire int64) (uint64, error)
+	// Get 获取数据，已过期的数据视为不存在
+	Get(key string) (interface{}, error)
+	// Delete 删除数据
+	Delete(key string) error
+	// Scan 遍历表中全部有效且未过期的数据，fn 返回 false 时停止遍历
+	//
+	// fn 中不可调用同一表的 Put/Delete
+	Scan(fn func(key string, value interface{}) bool) error
+	// Close 关闭表，释放表持有的资源
+	Close() error
+}
+
+// Expirer 可回收过期数据的表存储对象
+//
+// 表存储对象实现该接口时，服务将定时调用以回收过期数据
+type Expirer interface {
+	// Expire 回收已过期的数据，返回回收数量
+	Expire() (int64, error)
+}
+
+// Register 注册存储引擎
+func Register(engine Engine) error {
+	if gnomon.StringIsEmpty(engine.Name()) {
+		return ErrEngineNameIsNil
+	}
+	defer enginesMux.Unlock()
+	enginesMux.Lock()
+	if _, ok := engines[engine.Name()]; ok {
+		return ErrEngineExist
+	}
+	engines[engine.Name()] = engine
+	return nil
+}
+
+// Obtain 获取已注册的存储引擎，未注册时返回nil
+func Obtain(name string) Engine {
+	defer enginesMux.RUnlock()
+	enginesMux.RLock()
+	return engines[name]
+}
+
+// Names 已注册的存储引擎名称集合，按名称排序
+func Names() []string {
+	defer enginesMux.RUnlock()
+	enginesMux.RLock()
+	names := make([]string, 0, len(engines))
+	for name := range engines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
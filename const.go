@@ -19,6 +19,8 @@ const (
 	firstShow   = "show"
 	firstUse    = "use"
 	firstCreate = "create"
+	firstDrop   = "drop"
+	firstRename = "rename"
 	firstPutD   = "putD"
 	firstSetD   = "setD"
 	firstGetD   = "getD"
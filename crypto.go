@@ -0,0 +1,331 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"github.com/aberic/gnomon"
+	"github.com/aberic/gnomon/log"
+	"github.com/aberic/lily/api"
+	"io"
+	"io/ioutil"
+	"sync"
+)
+
+const (
+	// dataKeyLen 数据密钥及主密钥长度，AES-256
+	dataKeyLen = 32
+	// sealedHeadLen 密文头长度，4字节大端数据密钥版本 + 12字节随机数
+	sealedHeadLen = 4 + 12
+)
+
+var (
+	// ErrMasterKeyInvalid 自定义error信息
+	ErrMasterKeyInvalid = errors.New("master key file must hold 32 bytes or 64 hex chars")
+	// ErrMasterKeyRequired 自定义error信息
+	ErrMasterKeyRequired = errors.New("database is encrypted but master key file is not configured")
+	// ErrEncryptionDisabled 自定义error信息
+	ErrEncryptionDisabled = errors.New("encryption at rest is not enabled")
+	// ErrDataKeyNotFound 自定义error信息
+	ErrDataKeyNotFound = errors.New("data key of the sealed data is not found")
+)
+
+// keyring 库数据密钥环
+//
+// 数据记录及预写日志以当前版本数据密钥加密，轮换期间旧版本数据密钥仍用于解密
+//
+// 索引文件仅包含key的hash及数据位置，不做加密
+type keyring struct {
+	version uint32                 // 当前数据密钥版本，0表示未启用加密
+	aeads   map[uint32]cipher.AEAD // 数据密钥版本对应加解密对象
+	lock    sync.RWMutex
+}
+
+func newKeyring() *keyring {
+	return &keyring{aeads: map[uint32]cipher.AEAD{}}
+}
+
+// enabled 是否启用加密
+func (k *keyring) enabled() bool {
+	return k.current() > 0
+}
+
+// current 当前数据密钥版本
+func (k *keyring) current() uint32 {
+	if nil == k {
+		return 0
+	}
+	defer k.lock.RUnlock()
+	k.lock.RLock()
+	return k.version
+}
+
+// add 添加数据密钥，版本高于当前版本时作为当前数据密钥
+func (k *keyring) add(version uint32, key []byte) error {
+	aead, err := newAEAD(key)
+	if nil != err {
+		return err
+	}
+	defer k.lock.Unlock()
+	k.lock.Lock()
+	k.aeads[version] = aead
+	if version > k.version {
+		k.version = version
+	}
+	return nil
+}
+
+// retire 移除当前版本以外的数据密钥，返回被移除的版本集合
+func (k *keyring) retire() []uint32 {
+	defer k.lock.Unlock()
+	k.lock.Lock()
+	var versions []uint32
+	for version := range k.aeads {
+		if version != k.version {
+			delete(k.aeads, version)
+			versions = append(versions, version)
+		}
+	}
+	return versions
+}
+
+// seal 以当前数据密钥加密，密文格式：4字节大端数据密钥版本 + 12字节随机数 + 密文
+func (k *keyring) seal(plain []byte) ([]byte, error) {
+	defer k.lock.RUnlock()
+	k.lock.RLock()
+	aead, ok := k.aeads[k.version]
+	if !ok {
+		return nil, ErrDataKeyNotFound
+	}
+	sealed := make([]byte, sealedHeadLen, sealedHeadLen+len(plain)+aead.Overhead())
+	binary.BigEndian.PutUint32(sealed[0:4], k.version)
+	if _, err := io.ReadFull(rand.Reader, sealed[4:sealedHeadLen]); nil != err {
+		return nil, err
+	}
+	return aead.Seal(sealed, sealed[4:sealedHeadLen], plain, sealed[0:4]), nil
+}
+
+// open 解密 seal 生成的密文
+func (k *keyring) open(sealed []byte) ([]byte, error) {
+	if len(sealed) < sealedHeadLen {
+		return nil, ErrRecordCorrupt
+	}
+	if nil == k {
+		return nil, ErrDataKeyNotFound
+	}
+	k.lock.RLock()
+	aead, ok := k.aeads[binary.BigEndian.Uint32(sealed[0:4])]
+	k.lock.RUnlock()
+	if !ok {
+		return nil, ErrDataKeyNotFound
+	}
+	return aead.Open(nil, sealed[4:sealedHeadLen], sealed[sealedHeadLen:], sealed[0:4])
+}
+
+// sealedVersion 获取密文的数据密钥版本
+func sealedVersion(sealed []byte) uint32 {
+	if len(sealed) < sealedHeadLen {
+		return 0
+	}
+	return binary.BigEndian.Uint32(sealed[0:4])
+}
+
+// RotateResult 数据密钥轮换结果
+type RotateResult struct {
+	KeyVersion uint32 // KeyVersion 轮换后的数据密钥版本
+	Records    int64  // Records 重新加密的记录数
+}
+
+// rotateKey 轮换数据密钥
+//
+// 生成新版本数据密钥并同步至 lily.sync，随后逐表压缩，以新数据密钥重新加密全部有效记录
+//
+// 全部表完成且预写日志中无未完成操作时移除旧版本数据密钥，否则保留至下次轮换
+func (d *database) rotateKey() (*RotateResult, error) {
+	master, err := obtainMasterKey()
+	if nil != err {
+		return nil, err
+	}
+	if nil == master {
+		return nil, ErrEncryptionDisabled
+	}
+	var (
+		version = d.keys.current() + 1
+		key     []byte
+	)
+	// 新数据密钥落盘后才可用于加密
+	if err = d.syncDatabase(func(dv *api.Database) error {
+		var err error
+		if key, err = newDataKey(dv, version, master); nil == err {
+			d.lily.storeRPC()
+		}
+		return err
+	}); nil != err {
+		return nil, err
+	}
+	if err = d.keys.add(version, key); nil != err {
+		return nil, err
+	}
+	result := &RotateResult{KeyVersion: version}
+	for formName, form := range d.getForms() {
+		// 默认存储引擎以外的表数据不落盘
+		if form.getEngine() != EngineFile {
+			continue
+		}
+		compact, err := d.compact(formName)
+		if nil != err {
+			return nil, err
+		}
+		result.Records += compact.Resealed
+	}
+	done, err := d.wal.checkpoint()
+	if nil != err {
+		return nil, err
+	}
+	if !done {
+		log.Warn("wal has pending operations, keep retired data keys", log.Field("database", d.getName()))
+		return result, nil
+	}
+	retired := d.keys.retire()
+	_ = d.syncDatabase(func(dv *api.Database) error {
+		for _, v := range retired {
+			delete(dv.DataKeys, v)
+		}
+		d.lily.storeRPC()
+		return nil
+	})
+	log.Info("rotate key",
+		log.Field("database", d.getName()),
+		log.Field("version", version),
+		log.Field("records", result.Records),
+		log.Field("retired", retired))
+	return result, nil
+}
+
+// openKeyring 以主密钥解密库的数据密钥并组装密钥环
+//
+// 启用加密而库尚无数据密钥时生成新的数据密钥并记录到库对象中，返回 true 表示库对象有变更
+func openKeyring(db *api.Database, master []byte) (*keyring, bool, error) {
+	keys := newKeyring()
+	if len(db.DataKeys) == 0 {
+		if nil == master {
+			return keys, false, nil
+		}
+		key, err := newDataKey(db, 1, master)
+		if nil != err {
+			return nil, false, err
+		}
+		if err = keys.add(1, key); nil != err {
+			return nil, false, err
+		}
+		return keys, true, nil
+	}
+	if nil == master {
+		return nil, false, ErrMasterKeyRequired
+	}
+	for version, wrapped := range db.DataKeys {
+		key, err := unwrapKey(master, wrapped)
+		if nil != err {
+			return nil, false, err
+		}
+		if err = keys.add(version, key); nil != err {
+			return nil, false, err
+		}
+	}
+	return keys, false, nil
+}
+
+// newDataKey 生成指定版本的数据密钥，以主密钥加密后记录到库对象中并作为库当前数据密钥版本
+func newDataKey(db *api.Database, version uint32, master []byte) ([]byte, error) {
+	key := make([]byte, dataKeyLen)
+	if _, err := io.ReadFull(rand.Reader, key); nil != err {
+		return nil, err
+	}
+	wrapped, err := wrapKey(master, key)
+	if nil != err {
+		return nil, err
+	}
+	if nil == db.DataKeys {
+		db.DataKeys = map[uint32][]byte{}
+	}
+	db.DataKeys[version] = wrapped
+	db.KeyVersion = version
+	return key, nil
+}
+
+// obtainMasterKey 读取配置的主密钥文件，未配置时返回nil
+//
+// 主密钥文件内容为32字节原始密钥或64位16进制字符串
+func obtainMasterKey() ([]byte, error) {
+	if gnomon.StringIsEmpty(obtainConf().MasterKeyFile) {
+		return nil, nil
+	}
+	data, err := ioutil.ReadFile(obtainConf().MasterKeyFile)
+	if nil != err {
+		return nil, err
+	}
+	if len(data) == dataKeyLen {
+		return data, nil
+	}
+	if data = bytes.TrimSpace(data); len(data) == dataKeyLen*2 {
+		if key, err := hex.DecodeString(string(data)); nil == err {
+			return key, nil
+		}
+	}
+	return nil, ErrMasterKeyInvalid
+}
+
+// wrapKey 以主密钥加密数据密钥，格式：12字节随机数 + 密文
+func wrapKey(master, key []byte) ([]byte, error) {
+	aead, err := newAEAD(master)
+	if nil != err {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); nil != err {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, key, nil), nil
+}
+
+// unwrapKey 以主密钥解密数据密钥
+func unwrapKey(master, wrapped []byte) ([]byte, error) {
+	aead, err := newAEAD(master)
+	if nil != err {
+		return nil, err
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, ErrMasterKeyInvalid
+	}
+	key, err := aead.Open(nil, wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():], nil)
+	if nil != err {
+		return nil, ErrMasterKeyInvalid
+	}
+	return key, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if nil != err {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
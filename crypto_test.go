@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"bytes"
+	"encoding/hex"
+	"github.com/aberic/lily/api"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOpenKeyring(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lily-key")
+	if nil != err {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	keyFile := filepath.Join(dir, "master.key")
+	if err = ioutil.WriteFile(keyFile, []byte(hex.EncodeToString(bytes.Repeat([]byte{7}, dataKeyLen))+"\n"), 0600); nil != err {
+		t.Fatal(err)
+	}
+	masterKeyFile := obtainConf().MasterKeyFile
+	obtainConf().MasterKeyFile = keyFile
+	defer func() { obtainConf().MasterKeyFile = masterKeyFile }()
+	master, err := obtainMasterKey()
+	if nil != err {
+		t.Fatal(err)
+	}
+	db := &api.Database{}
+	keys, generated, err := openKeyring(db, master)
+	if nil != err || !generated || keys.current() != 1 || db.KeyVersion != 1 {
+		t.Fatal("data key should be generated for plaintext database", err)
+	}
+	reopened, generated, err := openKeyring(db, master)
+	if nil != err || generated || reopened.current() != 1 {
+		t.Fatal("data key should be unwrapped", err)
+	}
+	sealed, _ := keys.seal([]byte("lily"))
+	if plain, err := reopened.open(sealed); nil != err || string(plain) != "lily" {
+		t.Error("unwrapped data key mismatch", err)
+	}
+	if _, _, err = openKeyring(db, bytes.Repeat([]byte{8}, dataKeyLen)); ErrMasterKeyInvalid != err {
+		t.Error("wrong master key should fail", err)
+	}
+	if _, _, err = openKeyring(db, nil); ErrMasterKeyRequired != err {
+		t.Error("encrypted database requires master key", err)
+	}
+}
+
+func TestResealRecord(t *testing.T) {
+	keys := newKeyring()
+	_ = keys.add(1, bytes.Repeat([]byte{1}, dataKeyLen))
+	value := strings.Repeat("secret", 16)
+	data, err := encodeRecord(&valueData{K: "1", I: true, V: value}, codecSnappy, keys)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("secret")) || data[1]&recordSealed == 0 {
+		t.Fatal("record should be sealed")
+	}
+	if _, err = decodeRecord(data, nil); ErrDataKeyNotFound != err {
+		t.Error("sealed record should not decode without data key", err)
+	}
+	_ = keys.add(2, bytes.Repeat([]byte{2}, dataKeyLen))
+	resealed, changed, err := resealRecord(data, keys)
+	if nil != err || !changed || sealedVersion(resealed[recordHeadLen:]) != 2 {
+		t.Fatal("record should be resealed with data key 2", err)
+	}
+	if _, changed, _ = resealRecord(resealed, keys); changed {
+		t.Error("record sealed with current data key should be kept")
+	}
+	if retired := keys.retire(); len(retired) != 1 || retired[0] != 1 {
+		t.Error("data key 1 should be retired", retired)
+	}
+	if vd, err := decodeRecord(resealed, keys); nil != err || vd.V != value {
+		t.Error("decode resealed record failed", err)
+	}
+	if _, err = decodeRecord(data, keys); ErrDataKeyNotFound != err {
+		t.Error("record sealed with retired data key should not decode", err)
+	}
+}
+
+func TestWAL_Sealed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lily-wal")
+	if nil != err {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	keys := newKeyring()
+	_ = keys.add(1, bytes.Repeat([]byte{1}, dataKeyLen))
+	w := &wal{path: filepath.Join(dir, "database.wal"), keys: keys, pending: map[uint64]struct{}{}}
+	if _, err = w.begin("form", "1", "secret", true, 0); nil != err {
+		t.Fatal(err)
+	}
+	_ = w.close()
+	data, _ := ioutil.ReadFile(w.path)
+	if bytes.Contains(data, []byte("secret")) {
+		t.Error("wal frame should be sealed")
+	}
+	if _, err = (&wal{path: w.path, pending: map[uint64]struct{}{}}).incomplete(); ErrDataKeyNotFound != err {
+		t.Error("sealed wal should not be read without data key", err)
+	}
+	entries, err := (&wal{path: w.path, keys: keys, pending: map[uint64]struct{}{}}).incomplete()
+	if nil != err || len(entries) != 1 || entries[0].V != "secret" {
+		t.Error("read sealed wal failed", entries, err)
+	}
+}
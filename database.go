@@ -17,12 +17,15 @@ package lily
 import (
 	"errors"
 	"github.com/aberic/gnomon"
+	"github.com/aberic/gnomon/log"
 	"github.com/aberic/lily/api"
+	"github.com/aberic/lily/connector"
 	"reflect"
 	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 const (
@@ -38,7 +41,11 @@ type database struct {
 	name    string          // 数据库名称，根据需求可以随时变化
 	comment string          // 描述
 	forms   map[string]Form // 表集合
+	wal     *wal            // 预写日志
+	keys    *keyring        // 数据密钥环
+	sync    string          // 落盘策略，为空时使用配置 SyncMode
 	lily    *Lily           // 数据库引擎
+	dLock   sync.RWMutex    // 库名、表集合及落盘策略读写锁
 }
 
 func (d *database) getID() string {
@@ -46,41 +53,118 @@ func (d *database) getID() string {
 }
 
 func (d *database) getName() string {
+	defer d.dLock.RUnlock()
+	d.dLock.RLock()
 	return d.name
 }
 
+func (d *database) setName(name string) {
+	defer d.dLock.Unlock()
+	d.dLock.Lock()
+	d.name = name
+}
+
 func (d *database) getComment() string {
 	return d.comment
 }
 
-// getForms 获取数据库表集合
+// getForms 获取数据库表集合，表集合只以新集合替换，返回后可直接遍历
 func (d *database) getForms() map[string]Form {
+	defer d.dLock.RUnlock()
+	d.dLock.RLock()
 	return d.forms
 }
 
-func (d *database) createDoc(formName, comment string) error {
-	if err := d.createForm(formName, comment, FormTypeDoc); nil != err {
+// attachForm 以加入表后的新集合替换表集合
+func (d *database) attachForm(formName string, form Form) {
+	defer d.dLock.Unlock()
+	d.dLock.Lock()
+	forms := make(map[string]Form, len(d.forms)+1)
+	for k, v := range d.forms {
+		forms[k] = v
+	}
+	forms[formName] = form
+	d.forms = forms
+}
+
+// detachForm 自表集合中移除表，表集合在读写中被并发访问，以新集合替换，调用方持有 Lily 锁
+func (d *database) detachForm(formName string) {
+	defer d.dLock.Unlock()
+	d.dLock.Lock()
+	forms := make(map[string]Form, len(d.forms))
+	for k, v := range d.forms {
+		if k != formName {
+			forms[k] = v
+		}
+	}
+	d.forms = forms
+}
+
+// renameForm 以新表名替换表集合中的表名，调用方持有 Lily 锁
+func (d *database) renameForm(formName, newName string) {
+	defer d.dLock.Unlock()
+	d.dLock.Lock()
+	forms := make(map[string]Form, len(d.forms))
+	for k, v := range d.forms {
+		if k == formName {
+			v.setName(newName)
+			k = newName
+		}
+		forms[k] = v
+	}
+	d.forms = forms
+}
+
+// getKeyring 获取数据库数据密钥环
+func (d *database) getKeyring() *keyring {
+	return d.keys
+}
+
+// getSyncMode 获取数据库当前生效的落盘策略
+func (d *database) getSyncMode() string {
+	d.dLock.RLock()
+	syncMode := d.sync
+	d.dLock.RUnlock()
+	if gnomon.StringIsEmpty(syncMode) {
+		return obtainConf().SyncMode
+	}
+	return syncMode
+}
+
+// setSyncMode 设置数据库落盘策略，为空时使用配置 SyncMode
+func (d *database) setSyncMode(syncMode string) {
+	d.dLock.Lock()
+	d.sync = syncMode
+	d.dLock.Unlock()
+	d.wal.setSyncMode(syncMode)
+}
+
+// flush 落盘预写日志中尚未落盘的日志帧
+func (d *database) flush() error {
+	return d.wal.flush()
+}
+
+func (d *database) createDoc(formName, comment string, options *FormOptions) error {
+	if err := d.createForm(formName, comment, FormTypeDoc, options); nil != err {
 		return err
 	}
 	// 默认自定义Key生成ID
 	_ = d.createKey(formName, indexDefaultID)
-	d.lily.lilyData.Databases[d.name].Forms[formName].FormType = api.FormType_Doc
 	return nil
 }
 
-func (d *database) createSQL(formName, comment string) error {
-	if err := d.createForm(formName, comment, FormTypeSQL); nil != err {
+func (d *database) createSQL(formName, comment string, options *FormOptions) error {
+	if err := d.createForm(formName, comment, FormTypeSQL, options); nil != err {
 		return err
 	}
 	// 自增索引ID
 	_ = d.createKey(formName, indexAutoID)
-	d.lily.lilyData.Databases[d.name].Forms[formName].FormType = api.FormType_SQL
 	return nil
 }
 
-func (d *database) createForm(formName, comment, formType string) error {
+func (d *database) createForm(formName, comment, formType string, options *FormOptions) error {
 	// 确定库名不重复
-	for k := range d.forms {
+	for k := range d.getForms() {
 		if k == formName {
 			return ErrFormExist
 		}
@@ -88,122 +172,211 @@ func (d *database) createForm(formName, comment, formType string) error {
 	// 确保表唯一ID不重复
 	formID := d.name2id(formName)
 	form := &form{
-		autoID:   0,
-		name:     formName,
-		id:       formID,
-		comment:  comment,
-		database: d,
-		indexes:  map[string]Index{},
-		formType: formType,
-	}
-	err := mkFormResource(d.id, formID)
-	if nil != err {
+		autoID:      0,
+		name:        formName,
+		id:          formID,
+		comment:     comment,
+		database:    d,
+		indexes:     map[string]Index{},
+		formType:    formType,
+		compression: options.Compression,
+		engine:      options.Engine,
+		versions:    options.Versions,
+	}
+	// 默认存储引擎以外的表由引擎自行管理存储资源
+	if options.Engine == EngineFile {
+		if err := mkFormResource(d.id, formID); nil != err {
+			return err
+		}
+	}
+	if err := d.openForm(form); nil != err {
 		return err
 	}
-	d.forms[formName] = form
-	// 同步数据到 pb.Lily
-	d.lily.lilyData.Databases[d.name].Forms[formName] = &api.Form{
-		ID:      formID,
-		Name:    formName,
-		Comment: comment,
-		Indexes: map[string]*api.Index{},
+	fv := &api.Form{
+		ID:          formID,
+		Name:        formName,
+		Comment:     comment,
+		Indexes:     map[string]*api.Index{},
+		FormType:    api.FormType_SQL,
+		Compression: FormatCompression2API(options.Compression),
+		Engine:      options.Engine,
+		Versions:    options.Versions,
+	}
+	if formType == FormTypeDoc {
+		fv.FormType = api.FormType_Doc
+	}
+	// 加入表集合与同步数据到 pb.Lily 同时完成，重命名表时二者一致
+	return d.syncDatabase(func(dv *api.Database) error {
+		if nil != dv.Forms[formName] {
+			return ErrFormExist
+		}
+		d.attachForm(formName, form)
+		dv.Forms[formName] = fv
+		return nil
+	})
+}
+
+// syncDatabase 持有 Lily 锁修改 pb.Lily 中的库对象，库已被删除时返回 ErrDataIsNil
+//
+// pb.Lily 在重命名及删除时由 Lily 锁保护，库名在持有 Lily 锁期间不会变化
+func (d *database) syncDatabase(fn func(dv *api.Database) error) error {
+	defer d.lily.lock.Unlock()
+	d.lily.lock.Lock()
+	dv := d.lily.lilyData.Databases[d.getName()]
+	if nil == dv || dv.ID != d.id {
+		return ErrDataIsNil
+	}
+	return fn(dv)
+}
+
+// syncForm 持有 Lily 锁修改 pb.Lily 中的表对象，库或表已被删除时忽略
+func (d *database) syncForm(form Form, fn func(fv *api.Form)) {
+	_ = d.syncDatabase(func(dv *api.Database) error {
+		if fv := dv.Forms[form.getName()]; nil != fv && fv.ID == form.getID() {
+			fn(fv)
+		}
+		return nil
+	})
+}
+
+// openForm 以表存储引擎打开表，打开成功后调用方再将表加入表集合
+func (d *database) openForm(form *form) error {
+	engine := connector.Obtain(form.engine)
+	if nil == engine {
+		return ErrEngineInvalid
+	}
+	storage, err := engine.Open(&connector.FormMeta{
+		DatabaseID:   d.id,
+		DatabaseName: d.getName(),
+		FormID:       form.id,
+		FormName:     form.name,
+		FormType:     form.formType,
+		Compression:  form.compression,
+		Context:      form,
+	})
+	if nil != err {
+		return err
 	}
+	form.storage = storage
 	return nil
 }
 
 func (d *database) createKey(formName string, keyStructure string) error {
 	// 确定key名不重复
-	for _, v := range d.forms[formName].getIndexes() {
+	for _, v := range d.getForms()[formName].getIndexes() {
 		if v.getKeyStructure() == keyStructure {
 			return ErrKeyExist
 		}
 	}
-	form := d.forms[formName]
+	form := d.getForms()[formName]
 	// 自定义Key生成ID
 	customID := d.name2id(strings.Join([]string{formName, keyStructure}, "_"))
 	//gnomon.Log().Debug("createIndex", gnomon.Log().Field("customID", customID))
 	index := &index{id: customID, primary: true, keyStructure: keyStructure, form: form}
 	node := &node{level: 1, degreeIndex: 0, preNode: nil, nodes: []Nodal{}, index: index}
 	index.node = node
-	form.getIndexes()[customID] = index
-	// 同步数据到 pb.Lily
-	d.lily.lilyData.Databases[d.name].Forms[formName].Indexes[customID] = &api.Index{
-		ID:           customID,
-		Primary:      true,
-		KeyStructure: keyStructure,
+	// 索引集合在读写中被并发遍历，以新集合替换
+	form.lock()
+	indexes := make(map[string]Index, len(form.getIndexes())+1)
+	for id, idx := range form.getIndexes() {
+		indexes[id] = idx
 	}
+	indexes[customID] = index
+	form.setIndexes(indexes)
+	form.unLock()
+	// 同步数据到 pb.Lily
+	d.syncForm(form, func(fv *api.Form) {
+		fv.Indexes[customID] = &api.Index{
+			ID:           customID,
+			Primary:      true,
+			KeyStructure: keyStructure,
+			Ordered:      true,
+		}
+	})
 	return nil
 }
 
-func (d *database) createIndex(formName string, keyStructure string) error {
+// createIndex 新建索引
+//
+// unique 是否唯一索引，写入数据的索引值与其它主键的当前记录重复时拒绝写入
+func (d *database) createIndex(formName string, keyStructure string, unique bool) error {
+	// 复合索引结构名须由不重复的字段名称组成
+	if fields := compositeFields(keyStructure); nil != fields {
+		if _, err := compositeKeyStructure(fields); nil != err {
+			return err
+		}
+	}
 	// 确定index名不重复
-	for _, v := range d.forms[formName].getIndexes() {
+	for _, v := range d.getForms()[formName].getIndexes() {
 		if v.getKeyStructure() == keyStructure {
 			return ErrIndexExist
 		}
 	}
-	form := d.forms[formName]
+	form := d.getForms()[formName]
 	// 自定义Key生成ID
 	customID := d.name2id(strings.Join([]string{formName, keyStructure}, "_"))
 	//gnomon.Log().Debug("createIndex", gnomon.Log().Field("customID", customID))
-	index := &index{id: customID, primary: false, keyStructure: keyStructure, form: form}
+	index := &index{id: customID, primary: false, unique: unique, keyStructure: keyStructure, form: form}
 	node := &node{level: 1, degreeIndex: 0, preNode: nil, nodes: []Nodal{}, index: index}
 	index.node = node
-	form.getIndexes()[customID] = index
-	// 同步数据到 pb.Lily
-	d.lily.lilyData.Databases[d.name].Forms[formName].Indexes[customID] = &api.Index{
-		ID:           customID,
-		Primary:      false,
-		KeyStructure: keyStructure,
+	// 索引集合在读写中被并发遍历，以新集合替换
+	form.lock()
+	if form.isDropped() {
+		form.unLock()
+		return ErrFormDropped
+	}
+	indexes := make(map[string]Index, len(form.getIndexes())+1)
+	for id, idx := range form.getIndexes() {
+		indexes[id] = idx
+	}
+	indexes[customID] = index
+	// 表中已有数据回填完成前检索不采用该索引，表中没有数据时无需回填
+	links := backfillLinks(form)
+	building := len(links) > 0
+	if building {
+		index.setStatus(&IndexStatus{State: IndexBuilding, Total: int64(len(links))})
+	}
+	form.setIndexes(indexes)
+	form.unLock()
+	// 同步数据到 pb.Lily，回填任务在后台并发修改 pb.Lily
+	d.syncForm(form, func(fv *api.Form) {
+		fv.Indexes[customID] = &api.Index{
+			ID:           customID,
+			Primary:      false,
+			KeyStructure: keyStructure,
+			Ordered:      true,
+			Unique:       unique,
+			Building:     building,
+		}
+	})
+	if building {
+		go d.backfill(form, index, links)
 	}
 	return nil
 }
 
-func (d *database) put(formName string, key string, value interface{}, update bool) (uint64, error) {
-	form := d.forms[formName] // 获取待操作表
+func (d *database) put(formName string, key string, value interface{}, update bool, expire int64) (uint64, error) {
+	form := d.getForms()[formName] // 获取待操作表
 	if nil == form {
 		return 0, formIsInvalid(formName)
 	}
-	indexes := form.getIndexes() // 获取表索引ID集合
-	return d.insertDataWithIndexInfo(form, key, indexes, value, update, true)
+	return form.getStorage().Put(key, value, update, expire)
 }
 
 func (d *database) get(formName string, key string) (interface{}, error) {
-	form := d.forms[formName]
+	form := d.getForms()[formName]
 	if nil == form {
 		return nil, formIsInvalid(formName)
 	}
-	for _, index := range form.getIndexes() {
-		if index.getKeyStructure() == indexDefaultID {
-			rs := index.get(key, hash(key))
-			if nil != rs.err {
-				return nil, rs.err
-			}
-			switch rs.value.(type) {
-			default:
-				return rs.value, nil
-			case string:
-				if gnomon.StringIsEmpty(rs.value.(string)) {
-					return nil, errors.New("value is invalid")
-				}
-			}
-		}
-	}
-	return nil, errors.New("no key for custom id index")
+	return form.getStorage().Get(key)
 }
 
 func (d *database) remove(formName string, key string) error {
-	value, err := d.get(formName, key)
-	if nil != err {
-		return err
-	}
-	form := d.forms[formName] // 获取待操作表
+	form := d.getForms()[formName] // 获取待操作表
 	if nil == form {
 		return formIsInvalid(formName)
 	}
-	indexes := form.getIndexes() // 获取表索引ID集合
-	_, err = d.insertDataWithIndexInfo(form, key, indexes, value, true, false)
-	return err
+	return form.getStorage().Delete(key)
 }
 
 func (d *database) delete(formName string, selector *Selector) (int32, error) {
@@ -227,20 +400,72 @@ func (d *database) query(formName string, selector *Selector) (int32, []interfac
 	return selector.exec()
 }
 
-func (d *database) insertDataWithIndexInfo(form Form, key string, indexes map[string]Index, value interface{}, update, valid bool) (uint64, error) {
+func (d *database) insertDataWithIndexInfo(form Form, key string, value interface{}, update, valid bool, expire int64) (uint64, error) {
+	defer form.unLock()
+	form.lock()
+	// 等待表写锁期间表已被删除则不再写入
+	if form.isDropped() {
+		return 0, ErrFormDropped
+	}
+	return d.writeData(form, key, value, update, valid, expire)
+}
+
+// expireData 将key已过期的记录以删除相同的方式标记为无效，返回是否已标记
+//
+// 持有表写锁后再次确认key当前记录仍为 location 处已过期的记录，扫描后重新写入的记录不受影响
+func (d *database) expireData(form Form, key string, value interface{}, location recordLocation) (bool, error) {
+	defer form.unLock()
+	form.lock()
+	if form.isDropped() {
+		return false, ErrFormDropped
+	}
+	if current, ok := currentLocation(form, key); !ok || current != location {
+		return false, nil
+	}
+	if _, err := d.writeData(form, key, value, true, false, 0); nil != err {
+		return false, err
+	}
+	return true, nil
+}
+
+// writeData 写入数据并更新全部索引，调用方持有表写锁
+func (d *database) writeData(form Form, key string, value interface{}, update, valid bool, expire int64) (uint64, error) {
 	var (
 		ibs []IndexBack
 		wg  sync.WaitGroup
+		seq uint64
 		err error
 	)
-	//gnomon.Log().Debug("insertDataWithIndexInfo", gnomon.Log().Field("ibs", ibs))
-	defer form.unLock()
-	form.lock()
+	// 持有表写锁后获取索引集合，与索引的新建及删除互斥
+	indexes := form.getIndexes()
+	// 新增时主键已有记录则拒绝写入，已失效或已过期的记录视为不存在，以覆盖方式写入
+	if !update {
+		var exist bool
+		if exist, err = existKey(form, key); nil != err {
+			return 0, err
+		} else if exist {
+			return 0, errors.New(strings.Join([]string{"data ", key, " already exist"}, ""))
+		}
+		update = true
+	}
+	// 唯一索引校验通过后再执行写入，避免部分索引已写入
+	if valid {
+		if err = d.checkUnique(key, indexes, value); nil != err {
+			return 0, err
+		}
+	}
+	// 先将本次逻辑操作写入预写日志，数据及索引全部落盘后再标记完成
+	if seq, err = d.wal.begin(form.getID(), key, value, valid, expire); nil != err {
+		return 0, err
+	}
 	// 遍历表索引ID集合，检索并计算当前索引所在文件位置
 	ibs = d.rangeIndexes(form, key, indexes, value, update)
+	vd := &valueData{K: key, I: valid, V: value, E: expire, T: time.Now().UnixNano()}
+	chainVersion(ibs, vd)
 	// 存储数据到表文件
-	dataWriteResult := store().storeData(key, pathFormDataFile(d.id, form.getID()), value, valid)
+	dataWriteResult := store().storeData(form, vd)
 	if nil != dataWriteResult.err {
+		d.abort(seq, key)
 		return 0, dataWriteResult.err
 	}
 	errBack := make(chan error, len(ibs)) // 索引存储结果通道
@@ -248,7 +473,7 @@ func (d *database) insertDataWithIndexInfo(form Form, key string, indexes map[st
 		wg.Add(1)
 		go func(key string, ib IndexBack) {
 			defer wg.Done()
-			wrIndexBack := store().storeIndex(ib, dataWriteResult)
+			wrIndexBack := store().storeIndex(ib, dataWriteResult, d.getSyncMode())
 			if wrIndexBack.err != nil {
 				errBack <- wrIndexBack.err
 			}
@@ -257,21 +482,86 @@ func (d *database) insertDataWithIndexInfo(form Form, key string, indexes map[st
 	wg.Wait()
 	if len(errBack) > 0 {
 		if err = <-errBack; nil != err {
-			// todo 回滚策略待完成
+			// 已告知调用方写入失败，重启恢复时不再重做
+			d.abort(seq, key)
 			return 0, err
 		}
 	}
+	if !hasDefaultIndex(form) {
+		form.getHeads().set(key, recordLocation{segment: dataWriteResult.segment, seekStart: dataWriteResult.seekStart}, valid)
+	}
+	if err = d.wal.commit(seq); nil != err {
+		return 0, err
+	}
 	return *form.getAutoID(), nil
 }
 
+// abort 标记预写日志中的操作失败，标记失败时仅记录日志，写入本身的错误已返回调用方
+func (d *database) abort(seq uint64, key string) {
+	if err := d.wal.abort(seq); nil != err {
+		log.Warn("wal abort failed", log.Field("database", d.getName()), log.Field("key", key), log.Err(err))
+	}
+}
+
+// existKey 主键是否已存在有效记录，已失效或已过期但尚未回收的记录视为不存在，调用方持有表写锁
+func existKey(form Form, key string) (bool, error) {
+	for _, index := range form.getIndexes() {
+		if index.getKeyStructure() != indexDefaultID {
+			continue
+		}
+		ln := index.getLink(key, hash(key))
+		if nil == ln || ln.getSeekStartIndex() == -1 {
+			return false, nil
+		}
+		switch rs := ln.get(); rs.err {
+		case nil:
+			return true, nil
+		case ErrValueInvalid, ErrValueExpired:
+			return false, nil
+		default:
+			return false, rs.err
+		}
+	}
+	return false, nil
+}
+
+// recover 重做预写日志中所有未完成的操作，使数据文件与索引文件重新一致
+func (d *database) recover() error {
+	return d.wal.recover(func(entry *walEntry) error {
+		for _, form := range d.getForms() {
+			if form.getID() == entry.F {
+				_, err := d.insertDataWithIndexInfo(form, entry.K, entry.V, true, entry.I, entry.E)
+				if nil != err && !ioError(err) {
+					// 重做时被拒绝的操作，如违反唯一索引，不影响其它操作的恢复
+					log.Warn("wal entry redo rejected, discard", log.Field("formID", entry.F), log.Field("key", entry.K), log.Err(err))
+					return nil
+				}
+				return err
+			}
+		}
+		log.Warn("wal entry form not found, discard", log.Field("formID", entry.F), log.Field("key", entry.K))
+		return nil
+	})
+}
+
+// close 关闭数据库全部表及预写日志
+func (d *database) close() error {
+	for _, form := range d.getForms() {
+		if err := form.getStorage().Close(); nil != err {
+			log.Error("close", log.Field("form", form.getName()), log.Err(err))
+		}
+	}
+	return d.wal.close()
+}
+
 // rangeIndexes 遍历表索引ID集合，检索并计算所有索引返回对象集合
 func (d *database) rangeIndexes(form Form, key string, indexes map[string]Index, value interface{}, update bool) []IndexBack {
 	var (
 		wg        sync.WaitGroup
-		chanIndex chan IndexBack
+		chanIndex chan []IndexBack
 	)
 	indexLen := len(indexes)
-	chanIndex = make(chan IndexBack, indexLen) // 创建索引ID结果返回通道
+	chanIndex = make(chan []IndexBack, indexLen) // 创建索引ID结果返回通道，数组字段的自定义索引返回多个对象
 	// 遍历表索引ID集合，检索并计算当前索引所在文件位置
 	for _, index := range indexes {
 		wg.Add(1)
@@ -280,32 +570,69 @@ func (d *database) rangeIndexes(form Form, key string, indexes map[string]Index,
 			//gnomon.Log().Debug("rangeIndexes", gnomon.Log().Field("index.id", index.getID()), gnomon.Log().Field("index.keyStructure", index.getKeyStructure()))
 			if index.getKeyStructure() == indexAutoID {
 				autoID := atomic.AddUint64(form.getAutoID(), 1) // ID自增
-				chanIndex <- form.getIndexes()[index.getID()].put(strconv.FormatUint(autoID, 10), autoID, update)
+				chanIndex <- []IndexBack{form.getIndexes()[index.getID()].put(strconv.FormatUint(autoID, 10), autoID, update)}
 			} else if index.getKeyStructure() == indexDefaultID {
-				chanIndex <- form.getIndexes()[index.getID()].put(key, hash(key), update)
+				chanIndex <- []IndexBack{form.getIndexes()[index.getID()].put(key, hash(key), update)}
 			} else {
-				chanIndex <- d.getCustomIndex(form, index, value, update)
+				chanIndex <- d.getCustomIndex(form, index, key, value, update)
 			}
 		}(index)
 	}
 	wg.Wait()
 	var ibs []IndexBack
 	for i := 0; i < indexLen; i++ {
-		ib := <-chanIndex
-		if ib.getErr() == nil {
-			ibs = append(ibs, ib)
+		for _, ib := range <-chanIndex {
+			if ib.getErr() == nil {
+				ibs = append(ibs, ib)
+			}
 		}
 	}
 	return ibs
 }
 
-// getCustomIndex 获取自定义索引预插入返回对象
-func (d *database) getCustomIndex(form Form, idx Index, value interface{}, update bool) IndexBack {
+// getCustomIndex 获取自定义索引预插入返回对象集合
+//
+// key 为记录主键，相同索引值的多条记录在叶子节点中各自保有链表，索引字段为数组时每个元素各对应一个链表，见 postingKey
+func (d *database) getCustomIndex(form Form, idx Index, key string, value interface{}, update bool) []IndexBack {
+	customKeys, err := customIndexKeys(idx, value)
+	if nil != err {
+		return []IndexBack{&indexBack{err: err}}
+	}
+	ibs := make([]IndexBack, len(customKeys))
+	for i, ck := range customKeys {
+		ibs[i] = form.getIndexes()[idx.getID()].put(postingKey(idx, ck.key, key), ck.hashKey, update)
+	}
+	return ibs
+}
+
+// postingKey 非主键索引的链表key，由索引key与记录主键组成
+//
+// 链表以该key的md5去重，同一索引值下每条记录对应一个链表，共同组成该索引值的记录集合
+//
+// 唯一索引每个索引值至多对应一条当前记录，链表key即索引key，校验唯一性时可直接定位占用该索引值的链表
+func postingKey(idx Index, indexKey, key string) string {
+	if idx.isUnique() {
+		return indexKey
+	}
+	return strings.Join([]string{indexKey, key}, "\x00")
+}
+
+// customIndexKeys 根据自定义索引结构名从存储数据中计算索引key集合
+//
+// 索引字段为数组时每个元素各对应一个索引key，见 multikeys
+func customIndexKeys(idx Index, value interface{}) ([]*customKey, error) {
+	if fields := compositeFields(idx.getKeyStructure()); nil != fields {
+		keyNew, hashKeyNew, err := compositeIndexKey(fields, value)
+		if nil != err {
+			return nil, err
+		}
+		return []*customKey{{key: keyNew, hashKey: hashKeyNew}}, nil
+	}
 	reflectValue := reflect.ValueOf(value) // 反射对象，通过reflectObj获取存储在里面的值，还可以去改变值
 	params := strings.Split(idx.getKeyStructure(), ".")
 	switch reflectValue.Kind() {
 	default:
-		return &indexBack{err: errors.New(strings.Join([]string{"index", idx.getKeyStructure(), "with type is invalid"}, " "))}
+		return nil, errors.New(strings.Join([]string{"index", idx.getKeyStructure(), "with type is invalid"}, " "))
 	case reflect.Map:
 		var (
 			item      interface{}
@@ -321,16 +648,19 @@ func (d *database) getCustomIndex(form Form, idx Index, value interface{}, updat
 			}
 			switch item := item.(type) {
 			default:
-				return &indexBack{err: errors.New(strings.Join([]string{"index", idx.getKeyStructure(), "with map is invalid"}, " "))}
+				return nil, errors.New(strings.Join([]string{"index", idx.getKeyStructure(), "with map is invalid"}, " "))
 			case map[string]interface{}:
 				itemMap = item
 				continue
 			}
 		}
 		if keyNew, hashKeyNew, valid := type2index(item); valid {
-			return form.getIndexes()[idx.getID()].put(keyNew, hashKeyNew, update)
+			return []*customKey{{key: keyNew, hashKey: hashKeyNew}}, nil
 		}
-		return &indexBack{err: errors.New(strings.Join([]string{"index", idx.getKeyStructure(), "with map value is invalid"}, " "))}
+		if customKeys := multikeys(reflect.ValueOf(item)); len(customKeys) > 0 {
+			return customKeys, nil
+		}
+		return nil, errors.New(strings.Join([]string{"index", idx.getKeyStructure(), "with map value is invalid"}, " "))
 	case reflect.Ptr:
 		checkValue := reflectValue
 		for _, param := range params {
@@ -339,12 +669,15 @@ func (d *database) getCustomIndex(form Form, idx Index, value interface{}, updat
 				checkValue = checkNewValue
 				continue
 			}
-			return &indexBack{err: errors.New(strings.Join([]string{"index", idx.getKeyStructure(), "with ptr is invalid"}, " "))}
+			return nil, errors.New(strings.Join([]string{"index", idx.getKeyStructure(), "with ptr is invalid"}, " "))
 		}
 		if keyNew, hashKeyNew, valid := valueType2index(&checkValue); valid {
-			return form.getIndexes()[idx.getID()].put(keyNew, hashKeyNew, update)
+			return []*customKey{{key: keyNew, hashKey: hashKeyNew}}, nil
+		}
+		if customKeys := multikeys(checkValue); len(customKeys) > 0 {
+			return customKeys, nil
 		}
-		return &indexBack{err: errors.New(strings.Join([]string{"index", idx.getKeyStructure(), "with ptr value is invalid"}, " "))}
+		return nil, errors.New(strings.Join([]string{"index", idx.getKeyStructure(), "with ptr value is invalid"}, " "))
 	}
 }
 
@@ -359,7 +692,7 @@ func (d *database) name2id(name string) string {
 	have := true
 	for have {
 		have = false
-		for _, v := range d.forms {
+		for _, v := range d.getForms() {
 			if v.getID() == id {
 				have = true
 				id = gnomon.HashMD516(strings.Join([]string{id, gnomon.StringRandSeq(3)}, ""))
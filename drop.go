@@ -0,0 +1,281 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"errors"
+	"github.com/aberic/gnomon"
+	"github.com/aberic/gnomon/log"
+	"os"
+	"path/filepath"
+	sort2 "sort"
+	"strings"
+)
+
+var (
+	// ErrSystemProtected 跟随‘Lily’创建的默认库及其默认表不能被删除或重命名
+	ErrSystemProtected = errors.New("system database and its default forms can not be dropped or renamed")
+	// ErrNameInvalid 新名称为空
+	ErrNameInvalid = errors.New("name can not be empty")
+	// ErrIndexPrimary 表默认主键及自增ID索引不能被删除
+	ErrIndexPrimary = errors.New("primary index can not be dropped")
+	// ErrFormDropped 表已被删除，等待表写锁期间表被删除的写入将被拒绝
+	ErrFormDropped = errors.New("form has been dropped")
+)
+
+// systemProtected 是否为跟随‘Lily’创建的默认库或其默认表，formName 为空时仅判断库
+func systemProtected(databaseName, formName string) bool {
+	if databaseName != sysDatabase {
+		return false
+	}
+	return gnomon.StringIsEmpty(formName) || formName == defaultForm || formName == userForm
+}
+
+// holdForm 等待表进行中的读写完成并阻止新的读写，返回释放方法
+//
+// 写入持有表写锁，读取数据文件持有数据文件替换读锁
+func holdForm(form Form) func() {
+	form.lock()
+	swap := form.getSwapLocker()
+	swap.lock()
+	return func() {
+		swap.unLock()
+		form.unLock()
+	}
+}
+
+// retireForm 等待表进行中的读写完成并将表标记为已删除，返回释放方法
+//
+// 等待表写锁的写入在获得锁后发现表已删除，不再写入
+func retireForm(form Form) func() {
+	release := holdForm(form)
+	form.drop()
+	return release
+}
+
+// holdForms 依次持有多个表，返回按相反顺序释放的方法
+//
+// 表按库唯一ID及表唯一ID顺序持有，唯一ID不随重命名变化，同时持有多个表的删除库、恢复及备份不会相互等待
+func holdForms(forms []Form, hold func(form Form) func()) func() {
+	sorted := make([]Form, len(forms))
+	copy(sorted, forms)
+	sort2.Slice(sorted, func(i, j int) bool {
+		if di, dj := sorted[i].getDatabase().getID(), sorted[j].getDatabase().getID(); di != dj {
+			return di < dj
+		}
+		return sorted[i].getID() < sorted[j].getID()
+	})
+	releases := make([]func(), 0, len(sorted))
+	for _, form := range sorted {
+		releases = append(releases, hold(form))
+	}
+	return func() {
+		for i := len(releases) - 1; i >= 0; i-- {
+			releases[i]()
+		}
+	}
+}
+
+// retireForms 依次等待库中全部表进行中的读写完成并将表标记为已删除，返回释放方法
+func retireForms(db Database) func() {
+	var forms []Form
+	for _, form := range db.getForms() {
+		forms = append(forms, form)
+	}
+	return holdForms(forms, retireForm)
+}
+
+// dropDatabase 删除库
+//
+// 先自 lily.sync 及库集合中移除，再等待库中全部表进行中的读写完成后关闭库并删除库存储目录
+func (l *Lily) dropDatabase(name string) error {
+	if systemProtected(name, "") {
+		return ErrSystemProtected
+	}
+	l.lock.Lock()
+	db := l.obtainDatabase(name)
+	if nil == db {
+		l.lock.Unlock()
+		return ErrDataIsNil
+	}
+	l.detachDatabase(name)
+	delete(l.lilyData.Databases, name)
+	l.storeRPC()
+	l.lock.Unlock()
+	defer retireForms(db)()
+	if err := db.close(); nil != err {
+		log.Warn("drop database close failed", log.Field("database", name), log.Err(err))
+	}
+	dir := filepath.Join(obtainConf().DataDir, db.getID())
+	store().invalidateDir(dir)
+	return os.RemoveAll(dir)
+}
+
+// dropForm 删除表
+//
+// 先自 lily.sync 及表集合中移除，再等待表进行中的读写完成后关闭表并删除表存储目录
+func (l *Lily) dropForm(databaseName, formName string) error {
+	if systemProtected(databaseName, formName) {
+		return ErrSystemProtected
+	}
+	l.lock.Lock()
+	db := l.obtainDatabase(databaseName)
+	if nil == db {
+		l.lock.Unlock()
+		return ErrDataIsNil
+	}
+	form := db.getForms()[formName]
+	if nil == form {
+		l.lock.Unlock()
+		return formIsInvalid(formName)
+	}
+	db.detachForm(formName)
+	delete(l.lilyData.Databases[databaseName].Forms, formName)
+	l.storeRPC()
+	l.lock.Unlock()
+	defer retireForm(form)()
+	if err := form.getStorage().Close(); nil != err {
+		log.Warn("drop form close failed", log.Field("form", formName), log.Err(err))
+	}
+	// 默认存储引擎以外的表由引擎自行管理存储资源
+	if form.getEngine() != EngineFile {
+		return nil
+	}
+	dir := pathFormDir(db.getID(), form.getID())
+	store().invalidateDir(dir)
+	return os.RemoveAll(dir)
+}
+
+// dropIndex 删除索引
+//
+// 等待表进行中的读写完成后移除索引并删除索引文件，再自 lily.sync 中移除
+func (l *Lily) dropIndex(databaseName, formName, keyStructure string) error {
+	db := l.obtainDatabase(databaseName)
+	if nil == db {
+		return ErrDataIsNil
+	}
+	form := db.getForms()[formName]
+	if nil == form {
+		return formIsInvalid(formName)
+	}
+	release := holdForm(form)
+	if form.isDropped() {
+		release()
+		return formIsInvalid(formName)
+	}
+	var idx Index
+	for _, index := range form.getIndexes() {
+		if index.getKeyStructure() == keyStructure {
+			idx = index
+			break
+		}
+	}
+	if nil == idx {
+		release()
+		return errors.New(strings.Join([]string{"index", keyStructure, "not found"}, " "))
+	}
+	if idx.isPrimary() {
+		release()
+		return ErrIndexPrimary
+	}
+	indexes := make(map[string]Index, len(form.getIndexes()))
+	for id, index := range form.getIndexes() {
+		if id != idx.getID() {
+			indexes[id] = index
+		}
+	}
+	form.setIndexes(indexes)
+	indexFilePath := pathFormIndexFile(db.getID(), form.getID(), idx.getID())
+	store().invalidate(indexFilePath)
+	err := os.Remove(indexFilePath)
+	release()
+	if nil != err && !os.IsNotExist(err) {
+		return err
+	}
+	l.lock.Lock()
+	if dv := l.lilyData.Databases[databaseName]; nil != dv && nil != dv.Forms[formName] {
+		delete(dv.Forms[formName].Indexes, idx.getID())
+		l.storeRPC()
+	}
+	l.lock.Unlock()
+	return nil
+}
+
+// renameDatabase 重命名库，库存储目录以库唯一ID命名，无需移动文件
+func (l *Lily) renameDatabase(name, newName string) error {
+	if systemProtected(name, "") || systemProtected(newName, "") {
+		return ErrSystemProtected
+	}
+	if gnomon.StringIsEmpty(newName) {
+		return ErrNameInvalid
+	}
+	defer l.lock.Unlock()
+	l.lock.Lock()
+	databases := l.getDatabaseMap()
+	db := databases[name]
+	if nil == db {
+		return ErrDataIsNil
+	}
+	if nil != databases[newName] {
+		return ErrDatabaseExist
+	}
+	db.setName(newName)
+	// 库集合在读写中被并发访问，以新集合替换
+	l.dLock.Lock()
+	renamed := make(map[string]Database, len(l.databases))
+	for k, v := range l.databases {
+		if k != name {
+			renamed[k] = v
+		}
+	}
+	renamed[newName] = db
+	l.databases = renamed
+	l.dLock.Unlock()
+	dv := l.lilyData.Databases[name]
+	delete(l.lilyData.Databases, name)
+	dv.Name = newName
+	l.lilyData.Databases[newName] = dv
+	l.storeRPC()
+	return nil
+}
+
+// renameForm 重命名表，表存储目录以表唯一ID命名，无需移动文件
+func (l *Lily) renameForm(databaseName, formName, newName string) error {
+	if systemProtected(databaseName, formName) || systemProtected(databaseName, newName) {
+		return ErrSystemProtected
+	}
+	if gnomon.StringIsEmpty(newName) {
+		return ErrNameInvalid
+	}
+	defer l.lock.Unlock()
+	l.lock.Lock()
+	db := l.obtainDatabase(databaseName)
+	if nil == db {
+		return ErrDataIsNil
+	}
+	if nil == db.getForms()[formName] {
+		return formIsInvalid(formName)
+	}
+	if nil != db.getForms()[newName] {
+		return ErrFormExist
+	}
+	db.renameForm(formName, newName)
+	fv := l.lilyData.Databases[databaseName].Forms[formName]
+	delete(l.lilyData.Databases[databaseName].Forms, formName)
+	fv.Name = newName
+	l.lilyData.Databases[databaseName].Forms[newName] = fv
+	l.storeRPC()
+	return nil
+}
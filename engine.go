@@ -0,0 +1,156 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"errors"
+	"github.com/aberic/gnomon"
+	"github.com/aberic/lily/connector"
+	"sync/atomic"
+	"time"
+)
+
+func init() {
+	if err := connector.Register(&fileEngine{}); nil != err {
+		panic(err)
+	}
+}
+
+// fileEngine 默认存储引擎
+//
+// 数据以记录形式追加写入表数据分段文件，并由表索引树及索引文件定位，写入经由库预写日志保证数据与索引一致
+type fileEngine struct{}
+
+func (e *fileEngine) Name() string {
+	return EngineFile
+}
+
+// Open 打开表，表对象由 Lily 在调用前创建或恢复，并经由 meta.Context 传入
+func (e *fileEngine) Open(meta *connector.FormMeta) (connector.Storage, error) {
+	form, ok := meta.Context.(Form)
+	if !ok {
+		return nil, formIsInvalid(meta.FormName)
+	}
+	// 重启后无法得知表中是否存在带过期时间的记录，首次回收时扫描确认
+	return &fileStorage{form: form, expiring: 1}, nil
+}
+
+// fileStorage 默认存储引擎的表存储对象
+type fileStorage struct {
+	form     Form
+	expiring int32 // 表中是否可能存在带过期时间的记录，1表示可能存在，回收时据此跳过无需扫描的表
+}
+
+func (s *fileStorage) Put(key string, value interface{}, update bool, expire int64) (uint64, error) {
+	if expire > 0 {
+		atomic.StoreInt32(&s.expiring, 1)
+	}
+	return s.form.getDatabase().insertDataWithIndexInfo(s.form, key, value, update, true, expire)
+}
+
+func (s *fileStorage) Get(key string) (interface{}, error) {
+	for _, index := range s.form.getIndexes() {
+		if index.getKeyStructure() == indexDefaultID {
+			rs := index.get(key, hash(key))
+			if nil != rs.err {
+				return nil, rs.err
+			}
+			switch rs.value.(type) {
+			default:
+				return rs.value, nil
+			case string:
+				if gnomon.StringIsEmpty(rs.value.(string)) {
+					return nil, errors.New("value is invalid")
+				}
+				return rs.value, nil
+			}
+		}
+	}
+	return nil, errors.New("no key for custom id index")
+}
+
+func (s *fileStorage) Delete(key string) error {
+	value, err := s.Get(key)
+	if nil != err {
+		return err
+	}
+	_, err = s.form.getDatabase().insertDataWithIndexInfo(s.form, key, value, true, false, 0)
+	return err
+}
+
+// Scan 逐条扫描表全部数据分段文件，按写入顺序遍历每个key当前且有效的记录，大数据分块记录不参与遍历
+//
+// 扫描期间持有表读锁，遍历结果为同一时刻的数据，fn 中不能写入该表
+func (s *fileStorage) Scan(fn func(key string, value interface{}) bool) error {
+	defer s.form.rUnLock()
+	s.form.rLock()
+	now := time.Now().UnixNano()
+	return scanCurrentRecords(s.form, func(record *scannedRecord) bool {
+		if record.vd.expired(now) || isBlobChunk(record.vd.K) {
+			return true
+		}
+		return fn(record.vd.K, record.vd.V)
+	})
+}
+
+// Expire 扫描表全部数据分段文件，将已过期的记录以删除相同的方式标记为无效
+//
+// 扫描时仅收集已过期的记录，标记时持有表写锁再次确认key当前记录仍为扫描到的过期记录，避免误删扫描后重新写入的数据
+func (s *fileStorage) Expire() (int64, error) {
+	// 先清除标记，扫描期间写入的带过期时间记录会重新设置标记
+	if !atomic.CompareAndSwapInt32(&s.expiring, 1, 0) {
+		return 0, nil
+	}
+	var (
+		now     = time.Now().UnixNano()
+		expires []*scannedRecord
+	)
+	s.form.rLock()
+	err := scanCurrentRecords(s.form, func(record *scannedRecord) bool {
+		if record.vd.E == 0 {
+			return true
+		}
+		if !record.vd.expired(now) {
+			atomic.StoreInt32(&s.expiring, 1)
+			return true
+		}
+		expires = append(expires, record)
+		return true
+	})
+	s.form.rUnLock()
+	if nil != err {
+		atomic.StoreInt32(&s.expiring, 1)
+		return 0, err
+	}
+	var count int64
+	for _, record := range expires {
+		expired, err := s.form.getDatabase().expireData(s.form, record.vd.K, record.vd.V, recordLocation{segment: record.segment, seekStart: record.seekStart})
+		if nil != err {
+			atomic.StoreInt32(&s.expiring, 1)
+			return count, err
+		}
+		if !expired { // 扫描后已被重新写入或迁移，下次回收时重新确认
+			atomic.StoreInt32(&s.expiring, 1)
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
+
+// Close 数据及索引文件句柄由 storage 统一缓存，预写日志由库关闭
+func (s *fileStorage) Close() error {
+	return nil
+}
@@ -0,0 +1,225 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"errors"
+	"github.com/aberic/gnomon"
+	"github.com/aberic/lily/api"
+	"github.com/aberic/lily/connector"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testEngine 测试用存储引擎，数据仅保存在内存中
+type testEngine struct{}
+
+func (e *testEngine) Name() string {
+	return "test"
+}
+
+func (e *testEngine) Open(meta *connector.FormMeta) (connector.Storage, error) {
+	return &testStorage{values: map[string]interface{}{}}, nil
+}
+
+type testStorage struct {
+	values map[string]interface{}
+	keys   []string
+	lock   sync.RWMutex
+}
+
+func (s *testStorage) Put(key string, value interface{}, update bool, expire int64) (uint64, error) {
+	defer s.lock.Unlock()
+	s.lock.Lock()
+	if _, ok := s.values[key]; ok && !update {
+		return 0, ErrKeyExist
+	} else if !ok {
+		s.keys = append(s.keys, key)
+	}
+	s.values[key] = value
+	return uint64(len(s.keys)), nil
+}
+
+func (s *testStorage) Get(key string) (interface{}, error) {
+	defer s.lock.RUnlock()
+	s.lock.RLock()
+	if value, ok := s.values[key]; ok {
+		return value, nil
+	}
+	return nil, errors.New("key not found")
+}
+
+func (s *testStorage) Delete(key string) error {
+	defer s.lock.Unlock()
+	s.lock.Lock()
+	delete(s.values, key)
+	return nil
+}
+
+func (s *testStorage) Scan(fn func(key string, value interface{}) bool) error {
+	defer s.lock.RUnlock()
+	s.lock.RLock()
+	for _, key := range s.keys {
+		if value, ok := s.values[key]; ok && !fn(key, value) {
+			break
+		}
+	}
+	return nil
+}
+
+func (s *testStorage) Close() error {
+	return nil
+}
+
+func TestLily_Engine(t *testing.T) {
+	dbName := "engine"
+	if err := connector.Register(&fileEngine{}); connector.ErrEngineExist != err {
+		t.Error("file engine should be registered once", err)
+	}
+	_ = connector.Register(&testEngine{})
+	t.Log("engines", connector.Names())
+	l := ObtainLily()
+	l.Start()
+	if _, err := l.CreateDatabase(dbName, "存储引擎测试"); nil != err {
+		t.Log(err)
+	}
+	if err := l.CreateFormWithOptions(dbName, "unknown", "", FormTypeDoc, &FormOptions{Engine: "unknown"}); ErrEngineInvalid != err {
+		t.Error("unknown engine should fail", err)
+	}
+	if err := l.CreateFormWithOptions(dbName, "test", "", FormTypeDoc, &FormOptions{Engine: "test"}); nil != err {
+		t.Log(err)
+	}
+	if engine := l.GetDatabase(dbName).getForms()["test"].getEngine(); engine != "test" {
+		t.Fatal("form engine should be test, got", engine)
+	}
+	for i, name := range []string{"a", "b", "c"} {
+		if _, err := l.Put(dbName, "test", name, map[string]interface{}{"i": int64(i)}); nil != err {
+			t.Fatal(err)
+		}
+	}
+	if _, err := l.Put(dbName, "test", "a", map[string]interface{}{"i": int64(9)}); ErrKeyExist != err {
+		t.Error("put exist key should fail", err)
+	}
+	if v, err := l.Get(dbName, "test", "b"); nil != err || v.(map[string]interface{})["i"] != int64(1) {
+		t.Error("get from test engine failed", v, err)
+	}
+	count, is, err := l.Select(dbName, "test", &Selector{Conditions: []*condition{{Param: "i", Cond: "gt", Value: int64(0)}}})
+	if nil != err || count != 2 {
+		t.Error("select from test engine failed", count, is, err)
+	}
+	if err = l.Remove(dbName, "test", "a"); nil != err {
+		t.Error(err)
+	}
+	if _, err = l.Get(dbName, "test", "a"); nil == err {
+		t.Error("removed key should not be found")
+	}
+	// 重启时存储引擎未注册的表不被加载，库中其它表正常恢复
+	if err = l.CreateForm(dbName, "file", "", FormTypeDoc); nil != err {
+		t.Log(err)
+	}
+	if _, err = l.Put(dbName, "file", "a", "file"); nil != err {
+		t.Fatal(err)
+	}
+	for _, fv := range l.lilyData.Databases[dbName].Forms {
+		if fv.Name == "test" {
+			fv.Engine = "missing"
+		}
+	}
+	l.syncRPC2Store()
+	restarted := &Lily{lilyData: &api.Lily{Databases: map[string]*api.Database{}}, databases: map[string]Database{}}
+	restarted.Restart()
+	if _, err = restarted.Put(dbName, "test", "d", "d"); nil == err {
+		t.Error("form with missing engine should not be written")
+	}
+	if v, err := restarted.Get(dbName, "file", "a"); nil != err || v != "file" {
+		t.Error("other forms should be recovered", v, err)
+	}
+	if err = l.DropDatabase(dbName); nil != err {
+		t.Fatal(err)
+	}
+}
+
+func TestLily_MemoryEngine(t *testing.T) {
+	dbName := "memory"
+	l := ObtainLily()
+	l.Start()
+	if _, err := l.CreateDatabase(dbName, "内存存储引擎测试"); nil != err {
+		t.Log(err)
+	}
+	engine := obtainConf().Engine
+	obtainConf().Engine = EngineMemory
+	defer func() { obtainConf().Engine = engine }()
+	if err := l.CreateForm(dbName, "cache", "", FormTypeDoc); nil != err {
+		t.Log(err)
+	}
+	form := l.GetDatabase(dbName).getForms()["cache"]
+	if form.getEngine() != EngineMemory {
+		t.Fatal("form engine should follow conf, got", form.getEngine())
+	}
+	if gnomon.FilePathExists(pathFormDir(l.GetDatabase(dbName).getID(), form.getID())) {
+		t.Error("memory form should not touch disk")
+	}
+	for i := 1; i <= 5; i++ {
+		if _, err := l.Put(dbName, "cache", strconv.Itoa(i), map[string]interface{}{"i": int64(i), "s": "lily"}); nil != err {
+			t.Fatal(err)
+		}
+	}
+	if _, err := l.Put(dbName, "cache", "1", map[string]interface{}{"i": int64(9)}); nil == err {
+		t.Error("put exist key should fail")
+	}
+	if _, err := l.Set(dbName, "cache", "1", map[string]interface{}{"i": int64(6), "s": "lily"}); nil != err {
+		t.Error(err)
+	}
+	v, err := l.Get(dbName, "cache", "1")
+	if nil != err || v.(map[string]interface{})["i"] != int64(6) {
+		t.Error("get from memory engine failed", v, err)
+	}
+	count, result, err := l.Select(dbName, "cache", &Selector{
+		Conditions: []*condition{{Param: "i", Cond: "gt", Value: int64(2)}},
+		Sort:       &sort{Param: "i", ASC: false},
+	})
+	t.Log("select", count, result)
+	is, _ := result.([]interface{})
+	if nil != err || count != 4 || len(is) != 4 || is[0].(map[string]interface{})["i"] != int64(6) {
+		t.Error("select from memory engine failed", is, err)
+	}
+	// 遍历检索时跳过及限制数量随扫描生效，满足数量后停止扫描
+	count, result, err = l.Select(dbName, "cache", &Selector{Skip: 1, Limit: 2})
+	t.Log("select with skip and limit", count, result)
+	if is, _ = result.([]interface{}); nil != err || count != 3 || len(is) != 2 {
+		t.Error("select with skip and limit from memory engine failed", count, is, err)
+	}
+	if count, err = l.Delete(dbName, "cache", &Selector{Conditions: []*condition{{Param: "i", Cond: "lt", Value: int64(3)}}}); nil != err || count != 1 {
+		t.Error("delete from memory engine failed", count, err)
+	}
+	if _, err = l.Get(dbName, "cache", "2"); nil == err {
+		t.Error("deleted key should not be found")
+	}
+	if _, err = l.SetWithTTL(dbName, "cache", "ttl", "lily", 20*time.Millisecond); nil != err {
+		t.Fatal(err)
+	}
+	time.Sleep(40 * time.Millisecond)
+	if _, err = l.Get(dbName, "cache", "ttl"); ErrValueExpired != err {
+		t.Error("ttl key should be expired", err)
+	}
+	if count, err := form.getStorage().(connector.Expirer).Expire(); nil != err || count != 1 {
+		t.Error("expired key should be reclaimed", count, err)
+	}
+	if _, err = l.Compact(dbName, "cache"); ErrEngineUnsupported != err {
+		t.Error("compact memory form should fail", err)
+	}
+}
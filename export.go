@@ -0,0 +1,474 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/aberic/gnomon"
+	"github.com/aberic/gnomon/log"
+	"github.com/aberic/lily/connector"
+	"io"
+	"io/ioutil"
+	"os"
+	sort2 "sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ExportJSONL JSON Lines 格式，每行一条 {"key":key,"value":value}
+	ExportJSONL = "jsonl"
+	// ExportCSV CSV 格式，首行为列名，嵌套对象按 keyStructure 相同的 '.' 路径展开为列
+	ExportCSV = "csv"
+)
+
+const (
+	// csvKeyColumn CSV 中 key 所在列名
+	csvKeyColumn = "_key"
+	// csvValueColumn 存储数据不是对象时 CSV 中数据所在列名
+	csvValueColumn = "_value"
+	// importProgressRows 导入时每处理该行数回调一次进度
+	importProgressRows = 1000
+)
+
+var (
+	// ErrFormatInvalid 导入导出格式错误
+	ErrFormatInvalid = errors.New("format must be one of jsonl and csv")
+	// ErrCSVHeaderInvalid CSV 列名行缺少 key 列
+	ErrCSVHeaderInvalid = errors.New("csv header must contain the _key column")
+)
+
+// ImportResult 导入结果
+type ImportResult struct {
+	Rows     int64 // Rows 已处理行数，不含 CSV 列名行
+	Imported int64 // Imported 成功导入行数
+	Rejected int64 // Rejected 无法解析或写入失败的行数，已写入拒绝文件
+}
+
+// jsonlRow JSON Lines 格式的一行
+type jsonlRow struct {
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+// csvRow 导出 CSV 时暂存的一行，Cells 为展开后的列名及单元格内容
+type csvRow struct {
+	Key   string            `json:"key"`
+	Cells map[string]string `json:"cells"`
+}
+
+// export 将表中全部有效数据按格式写入 w，返回导出行数
+func (d *database) export(formName, format string, w io.Writer) (int64, error) {
+	form := d.getForms()[formName]
+	if nil == form {
+		return 0, formIsInvalid(formName)
+	}
+	switch format {
+	default:
+		return 0, ErrFormatInvalid
+	case ExportJSONL:
+		return exportJSONL(form.getStorage(), w)
+	case ExportCSV:
+		return exportCSV(form.getStorage(), w)
+	}
+}
+
+func exportJSONL(storage connector.Storage, w io.Writer) (int64, error) {
+	var (
+		bw      = bufio.NewWriter(w)
+		encoder = json.NewEncoder(bw)
+		count   int64
+		err     error
+	)
+	if scanErr := storage.Scan(func(key string, value interface{}) bool {
+		if err = encoder.Encode(&jsonlRow{Key: key, Value: normalizeValue(value)}); nil != err {
+			return false
+		}
+		count++
+		return true
+	}); nil != scanErr {
+		return count, scanErr
+	}
+	if nil != err {
+		return count, err
+	}
+	return count, bw.Flush()
+}
+
+// exportCSV 扫描一遍，收集列名的同时将展开后的行暂存至临时文件，扫描完成后写入列名行及暂存的行
+//
+// 列名与数据来自同一次扫描，内存中仅保留列名
+func exportCSV(storage connector.Storage, w io.Writer) (int64, error) {
+	spool, err := ioutil.TempFile("", "lily-export-*.jsonl")
+	if nil != err {
+		return 0, err
+	}
+	defer func() {
+		_ = spool.Close()
+		_ = os.Remove(spool.Name())
+	}()
+	var (
+		paths   = map[string]bool{}
+		bw      = bufio.NewWriter(spool)
+		encoder = json.NewEncoder(bw)
+		count   int64
+	)
+	if scanErr := storage.Scan(func(key string, value interface{}) bool {
+		row := &csvRow{Key: key, Cells: map[string]string{}}
+		flattenValue("", value, func(path string, value interface{}) {
+			paths[path] = true
+			row.Cells[path] = csvCell(value)
+		})
+		if err = encoder.Encode(row); nil != err {
+			return false
+		}
+		count++
+		return true
+	}); nil != scanErr {
+		return 0, scanErr
+	}
+	if nil != err {
+		return 0, err
+	}
+	if err = bw.Flush(); nil != err {
+		return 0, err
+	}
+	if _, err = spool.Seek(0, io.SeekStart); nil != err {
+		return 0, err
+	}
+	header := make([]string, 0, len(paths)+1)
+	for path := range paths {
+		header = append(header, path)
+	}
+	sort2.Strings(header)
+	header = append([]string{csvKeyColumn}, header...)
+	positions := map[string]int{}
+	for position, path := range header {
+		positions[path] = position
+	}
+	cw := csv.NewWriter(w)
+	if err = cw.Write(header); nil != err {
+		return 0, err
+	}
+	decoder := json.NewDecoder(bufio.NewReader(spool))
+	for rows := int64(0); rows < count; rows++ {
+		row := &csvRow{}
+		if err = decoder.Decode(row); nil != err {
+			return rows, err
+		}
+		record := make([]string, len(header))
+		record[0] = row.Key
+		for path, cell := range row.Cells {
+			record[positions[path]] = cell
+		}
+		if err = cw.Write(record); nil != err {
+			return rows, err
+		}
+	}
+	cw.Flush()
+	return count, cw.Error()
+}
+
+// flattenValue 将嵌套对象按 '.' 路径展开，非对象的存储数据对应 csvValueColumn 列
+func flattenValue(prefix string, value interface{}, fn func(path string, value interface{})) {
+	switch value := normalizeValue(value).(type) {
+	case map[string]interface{}:
+		for k, v := range value {
+			if gnomon.StringIsEmpty(prefix) {
+				flattenValue(k, v, fn)
+			} else {
+				flattenValue(strings.Join([]string{prefix, k}, "."), v, fn)
+			}
+		}
+		return
+	}
+	if gnomon.StringIsEmpty(prefix) {
+		prefix = csvValueColumn
+	}
+	fn(prefix, value)
+}
+
+// normalizeValue 复制数据并将非字符串key的map转换为字符串key的map，便于编码为JSON及展开，不修改存储引擎返回的原数据
+func normalizeValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			m[fmt.Sprint(k)] = normalizeValue(v)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			m[k] = normalizeValue(v)
+		}
+		return m
+	case []interface{}:
+		array := make([]interface{}, len(value))
+		for i, v := range value {
+			array[i] = normalizeValue(v)
+		}
+		return array
+	}
+	return value
+}
+
+// csvCell 将数据转换为 CSV 单元格内容，数组等复合数据编码为JSON
+func csvCell(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(value)
+	}
+	data, err := json.Marshal(value)
+	if nil != err {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// load 按格式逐行读取 r 并写入表，key相同则覆盖
+//
+// 无法解析或写入失败的行原样写入 reject，每处理 importProgressRows 行及完成时回调 progress
+func (d *database) load(formName, format string, r io.Reader, reject io.Writer, progress func(*ImportResult)) (*ImportResult, error) {
+	if nil == d.getForms()[formName] {
+		return nil, formIsInvalid(formName)
+	}
+	if nil == reject {
+		reject = ioutil.Discard
+	}
+	var (
+		result = &ImportResult{}
+		err    error
+	)
+	put := func(key string, value interface{}) error {
+		if gnomon.StringIsEmpty(key) {
+			return ErrKeyIsNil
+		}
+		_, err := d.put(formName, key, value, true, 0)
+		return err
+	}
+	report := func() {
+		if nil != progress && result.Rows%importProgressRows == 0 {
+			progress(result)
+		}
+	}
+	switch format {
+	default:
+		return nil, ErrFormatInvalid
+	case ExportJSONL:
+		err = loadJSONL(r, reject, result, put, report)
+	case ExportCSV:
+		err = loadCSV(r, reject, result, put, report)
+	}
+	if nil != err {
+		return result, err
+	}
+	if nil != progress {
+		progress(result)
+	}
+	return result, nil
+}
+
+func loadJSONL(r io.Reader, reject io.Writer, result *ImportResult, put func(key string, value interface{}) error, report func()) error {
+	reader := bufio.NewReader(r)
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if nil != readErr && io.EOF != readErr {
+			return readErr
+		}
+		if len(bytes.TrimSpace(line)) > 0 {
+			result.Rows++
+			row := &jsonlRow{}
+			decoder := json.NewDecoder(bytes.NewReader(line))
+			decoder.UseNumber()
+			err := decoder.Decode(row)
+			if nil == err {
+				err = put(row.Key, normalizeJSON(row.Value))
+			}
+			if nil != err {
+				log.Warn("import row rejected", log.Field("row", result.Rows), log.Err(err))
+				if len(line) > 0 && line[len(line)-1] != '\n' {
+					line = append(line, '\n')
+				}
+				if _, err = reject.Write(line); nil != err {
+					return err
+				}
+				result.Rejected++
+			} else {
+				result.Imported++
+			}
+			report()
+		}
+		if io.EOF == readErr {
+			return nil
+		}
+	}
+}
+
+// loadCSV CSV 不保留数据类型，导入时依据单元格内容推断，空单元格对应的字段不写入
+func loadCSV(r io.Reader, reject io.Writer, result *ImportResult, put func(key string, value interface{}) error, report func()) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if io.EOF == err {
+		return nil
+	}
+	if nil != err {
+		return err
+	}
+	keyPosition := -1
+	for position, column := range header {
+		if column == csvKeyColumn {
+			keyPosition = position
+		}
+	}
+	if keyPosition == -1 {
+		return ErrCSVHeaderInvalid
+	}
+	var (
+		rejectWriter = csv.NewWriter(reject)
+		rejectHeader bool
+	)
+	for {
+		record, err := reader.Read()
+		if io.EOF == err {
+			break
+		}
+		if nil != err {
+			return err
+		}
+		result.Rows++
+		if err = csvRecordPut(header, keyPosition, record, put); nil != err {
+			log.Warn("import row rejected", log.Field("row", result.Rows), log.Err(err))
+			if !rejectHeader {
+				if err = rejectWriter.Write(header); nil != err {
+					return err
+				}
+				rejectHeader = true
+			}
+			if err = rejectWriter.Write(record); nil != err {
+				return err
+			}
+			result.Rejected++
+		} else {
+			result.Imported++
+		}
+		report()
+	}
+	rejectWriter.Flush()
+	return rejectWriter.Error()
+}
+
+// csvRecordPut 依据列名将一行还原为存储数据并写入
+func csvRecordPut(header []string, keyPosition int, record []string, put func(key string, value interface{}) error) error {
+	if len(record) != len(header) {
+		return errors.New(strings.Join([]string{"csv row has", strconv.Itoa(len(record)), "fields, header has", strconv.Itoa(len(header))}, " "))
+	}
+	var (
+		value interface{}
+		item  = map[string]interface{}{}
+	)
+	for position, column := range header {
+		if position == keyPosition || gnomon.StringIsEmpty(record[position]) {
+			continue
+		}
+		if column == csvValueColumn {
+			value = inferCell(record[position])
+			continue
+		}
+		if err := setPath(item, strings.Split(column, "."), inferCell(record[position])); nil != err {
+			return err
+		}
+	}
+	if nil == value {
+		value = item
+	}
+	return put(record[keyPosition], value)
+}
+
+// setPath 按 '.' 路径在对象中设置字段，路径上已存在非对象字段时返回错误
+func setPath(item map[string]interface{}, path []string, value interface{}) error {
+	for _, param := range path[:len(path)-1] {
+		next, ok := item[param]
+		if !ok {
+			nextItem := map[string]interface{}{}
+			item[param] = nextItem
+			item = nextItem
+			continue
+		}
+		if item, ok = next.(map[string]interface{}); !ok {
+			return errors.New(strings.Join([]string{"column", strings.Join(path, "."), "conflicts with", param}, " "))
+		}
+	}
+	item[path[len(path)-1]] = value
+	return nil
+}
+
+// inferCell 依据单元格内容推断数据类型，依次尝试布尔、整数、浮点数及JSON对象或数组，否则为字符串
+func inferCell(cell string) interface{} {
+	switch cell {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if i, err := strconv.ParseInt(cell, 10, 64); nil == err {
+		return i
+	}
+	if f, err := strconv.ParseFloat(cell, 64); nil == err {
+		return f
+	}
+	if strings.HasPrefix(cell, "{") || strings.HasPrefix(cell, "[") {
+		var value interface{}
+		decoder := json.NewDecoder(strings.NewReader(cell))
+		decoder.UseNumber()
+		if err := decoder.Decode(&value); nil == err {
+			return normalizeJSON(value)
+		}
+	}
+	return cell
+}
+
+// normalizeJSON 将JSON数字还原为 int64 或 float64，整数值的浮点数将还原为 int64
+func normalizeJSON(value interface{}) interface{} {
+	switch value := value.(type) {
+	case json.Number:
+		if i, err := value.Int64(); nil == err {
+			return i
+		}
+		f, _ := value.Float64()
+		return f
+	case map[string]interface{}:
+		for k, v := range value {
+			value[k] = normalizeJSON(v)
+		}
+		return value
+	case []interface{}:
+		for i, v := range value {
+			value[i] = normalizeJSON(v)
+		}
+		return value
+	}
+	return value
+}
@@ -0,0 +1,167 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"bytes"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLily_ExportImport(t *testing.T) {
+	var (
+		dbName   = "export"
+		formName = "record"
+	)
+	l := ObtainLily()
+	l.Start()
+	if _, err := l.CreateDatabase(dbName, "导入导出测试"); nil != err {
+		t.Log(err)
+	}
+	for _, name := range []string{formName, "jsonl", "csv"} {
+		if err := l.CreateForm(dbName, name, "", FormTypeDoc); nil != err {
+			t.Log(err)
+		}
+	}
+	values := map[string]interface{}{}
+	for i := 1; i <= 5; i++ {
+		value := map[string]interface{}{
+			"name": "lily" + strconv.Itoa(i),
+			"info": map[string]interface{}{"age": int64(20 + i), "score": float64(i) + 0.5},
+			"tags": []interface{}{"a", "b"},
+		}
+		values[strconv.Itoa(i)] = value
+		if _, err := l.Set(dbName, formName, strconv.Itoa(i), value); nil != err {
+			t.Fatal(err)
+		}
+	}
+	for _, format := range []string{ExportJSONL, ExportCSV} {
+		buf := &bytes.Buffer{}
+		rows, err := l.Export(dbName, formName, format, buf)
+		if nil != err {
+			t.Fatal(err)
+		}
+		t.Log(format, "export rows =", rows, "\n"+buf.String())
+		if rows != 5 {
+			t.Error(format, "export rows should be 5, got", rows)
+		}
+		// 追加一行无法导入的数据
+		if format == ExportJSONL {
+			buf.WriteString("{\"key\":\"bad\",\n")
+		} else {
+			buf.WriteString("bad\n")
+		}
+		var (
+			reject   = &bytes.Buffer{}
+			progress int
+		)
+		result, err := l.Import(dbName, format, format, buf, reject, func(*ImportResult) { progress++ })
+		if nil != err {
+			t.Fatal(err)
+		}
+		t.Log(format, "import rows =", result.Rows, "imported =", result.Imported, "rejected =", result.Rejected, "reject =", reject.String())
+		if result.Rows != 6 || result.Imported != 5 || result.Rejected != 1 || progress == 0 {
+			t.Error(format, "import result is wrong", result, progress)
+		}
+		if !strings.Contains(reject.String(), "bad") {
+			t.Error(format, "reject should contain the bad row")
+		}
+		for key, value := range values {
+			if v, err := l.Get(dbName, format, key); nil != err || !reflect.DeepEqual(v, value) {
+				t.Error(format, "get", key, "after import is", v, "want", value, err)
+			}
+		}
+	}
+	if _, err := l.Export(dbName, formName, "xml", &bytes.Buffer{}); err != ErrFormatInvalid {
+		t.Error("unknown format should be rejected", err)
+	}
+}
+
+func TestInferCell(t *testing.T) {
+	for cell, want := range map[string]interface{}{
+		"true":      true,
+		"12":        int64(12),
+		"1.5":       1.5,
+		"lily":      "lily",
+		"[1,\"a\"]": []interface{}{int64(1), "a"},
+		"{\"a\":1}": map[string]interface{}{"a": int64(1)},
+		"{bad":      "{bad",
+	} {
+		if got := inferCell(cell); !reflect.DeepEqual(got, want) {
+			t.Error("infer", cell, "got", got, "want", want)
+		}
+	}
+}
+
+func TestLily_ExportStream(t *testing.T) {
+	var (
+		dbName   = "export"
+		formName = "stream"
+	)
+	l := ObtainLily()
+	l.Start()
+	if _, err := l.CreateDatabase(dbName, "导入导出测试"); nil != err {
+		t.Log(err)
+	}
+	if err := l.CreateForm(dbName, formName, "", FormTypeDoc); nil != err {
+		t.Log(err)
+	}
+	// 各行字段不同，key "1" 被覆盖，key "4" 被删除
+	for key, value := range map[string]interface{}{
+		"1": map[string]interface{}{"name": "lily1"},
+		"2": map[string]interface{}{"name": "lily2", "age": int64(2)},
+		"3": map[string]interface{}{"city": "hz"},
+		"4": map[string]interface{}{"gone": true},
+	} {
+		if _, err := l.Set(dbName, formName, key, value); nil != err {
+			t.Fatal(err)
+		}
+	}
+	if _, err := l.Set(dbName, formName, "1", map[string]interface{}{"name": "lily1", "score": 1.5}); nil != err {
+		t.Fatal(err)
+	}
+	if err := l.Remove(dbName, formName, "4"); nil != err {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	rows, err := l.Export(dbName, formName, ExportCSV, buf)
+	if nil != err {
+		t.Fatal(err)
+	}
+	t.Log("export rows =", rows, "\n"+buf.String())
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if rows != 3 || len(lines) != 4 {
+		t.Error("export rows should be 3, got", rows, len(lines)-1)
+	}
+	if lines[0] != "_key,age,city,name,score" {
+		t.Error("header should contain every column, got", lines[0])
+	}
+	if strings.Contains(buf.String(), "gone") {
+		t.Error("removed key should not be exported")
+	}
+	var keys []string
+	if err = l.GetDatabase(dbName).getForms()[formName].getStorage().Scan(func(key string, value interface{}) bool {
+		keys = append(keys, key)
+		return len(keys) < 2
+	}); nil != err {
+		t.Fatal(err)
+	}
+	t.Log("scan stopped at", keys)
+	if len(keys) != 2 {
+		t.Error("scan should stop when fn returns false, got", keys)
+	}
+}
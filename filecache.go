@@ -0,0 +1,232 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"container/list"
+	"github.com/aberic/gnomon/log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// fileCache 有界LRU文件句柄缓存
+//
+// 每个数据分段文件及索引文件仅保持一个读写句柄，读取使用 ReadAt，写入使用 WriteAt，均为位置读写，
+// 因此同一句柄可在并发读取与表写入之间安全共享
+//
+// 超出容量时淘汰最久未使用的句柄，仍在使用中的句柄在最后一个使用者归还后关闭，关闭前落盘尚未落盘的写入
+type fileCache struct {
+	limit int                      // 缓存句柄数量上限
+	files map[string]*list.Element // 文件路径对应缓存元素
+	lru   *list.List               // 最近使用在前
+	lock  sync.Mutex
+}
+
+// cachedFile 缓存的文件句柄
+type cachedFile struct {
+	path    string
+	file    *os.File
+	size    int64 // 文件当前大小，追加写入位置
+	dirty   bool  // 是否有尚未落盘的写入
+	refs    int   // 正在使用该句柄的调用数，由 fileCache.lock 保护
+	evicted bool  // 已被淘汰，由 fileCache.lock 保护
+	wLock   sync.Mutex
+}
+
+func newFileCache(limit int) *fileCache {
+	return &fileCache{limit: limit, files: map[string]*list.Element{}, lru: list.New()}
+}
+
+// acquire 获取文件句柄，使用完毕须调用 release 归还
+//
+// create 文件不存在时是否创建
+func (fc *fileCache) acquire(path string, create bool) (*cachedFile, error) {
+	defer fc.lock.Unlock()
+	fc.lock.Lock()
+	if element, ok := fc.files[path]; ok {
+		fc.lru.MoveToFront(element)
+		cf := element.Value.(*cachedFile)
+		cf.refs++
+		return cf, nil
+	}
+	flag := os.O_RDWR
+	if create {
+		flag |= os.O_CREATE
+	}
+	file, err := os.OpenFile(path, flag, 0644)
+	if nil != err {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if nil != err {
+		_ = file.Close()
+		return nil, err
+	}
+	cf := &cachedFile{path: path, file: file, size: info.Size(), refs: 1}
+	fc.files[path] = fc.lru.PushFront(cf)
+	for fc.lru.Len() > fc.limit {
+		fc.evict(fc.lru.Back())
+	}
+	return cf, nil
+}
+
+// release 归还文件句柄
+func (fc *fileCache) release(cf *cachedFile) {
+	defer fc.lock.Unlock()
+	fc.lock.Lock()
+	cf.refs--
+	if cf.evicted && cf.refs == 0 {
+		cf.close()
+	}
+}
+
+// invalidate 淘汰指定文件的句柄，文件被替换、删除或截断后调用
+func (fc *fileCache) invalidate(path string) {
+	defer fc.lock.Unlock()
+	fc.lock.Lock()
+	if element, ok := fc.files[path]; ok {
+		fc.evict(element)
+	}
+}
+
+// invalidateDir 淘汰指定目录下全部文件的句柄，目录被替换或删除后调用
+func (fc *fileCache) invalidateDir(dir string) {
+	prefix := filepath.Clean(dir) + string(filepath.Separator)
+	defer fc.lock.Unlock()
+	fc.lock.Lock()
+	for path, element := range fc.files {
+		if strings.HasPrefix(filepath.Clean(path), prefix) {
+			fc.evict(element)
+		}
+	}
+}
+
+// flush 将全部有尚未落盘写入的句柄落盘
+func (fc *fileCache) flush() {
+	var files []*cachedFile
+	fc.lock.Lock()
+	for element := fc.lru.Front(); nil != element; element = element.Next() {
+		cf := element.Value.(*cachedFile)
+		cf.refs++
+		files = append(files, cf)
+	}
+	fc.lock.Unlock()
+	for _, cf := range files {
+		if err := cf.sync(); nil != err {
+			log.Error("flush", log.Field("path", cf.path), log.Err(err))
+		}
+		fc.release(cf)
+	}
+}
+
+// close 淘汰全部句柄
+func (fc *fileCache) close() {
+	defer fc.lock.Unlock()
+	fc.lock.Lock()
+	for element := fc.lru.Back(); nil != element; element = fc.lru.Back() {
+		fc.evict(element)
+	}
+}
+
+// evict 从缓存中移除句柄，无使用者时立即关闭，调用方持有 lock
+func (fc *fileCache) evict(element *list.Element) {
+	cf := fc.lru.Remove(element).(*cachedFile)
+	delete(fc.files, cf.path)
+	cf.evicted = true
+	if cf.refs == 0 {
+		cf.close()
+	}
+}
+
+// append 追加写入，返回写入的起始位置
+func (cf *cachedFile) append(data []byte) (int64, error) {
+	defer cf.wLock.Unlock()
+	cf.wLock.Lock()
+	seekStart := cf.size
+	n, err := cf.file.WriteAt(data, seekStart)
+	cf.size += int64(n)
+	return seekStart, err
+}
+
+// appendHeader 文件为空时写入文件头
+func (cf *cachedFile) appendHeader(header []byte) error {
+	defer cf.wLock.Unlock()
+	cf.wLock.Lock()
+	if cf.size > 0 {
+		return nil
+	}
+	n, err := cf.file.WriteAt(header, 0)
+	cf.size += int64(n)
+	return err
+}
+
+// writeAt 覆盖写入指定位置
+func (cf *cachedFile) writeAt(data []byte, seekStart int64) error {
+	defer cf.wLock.Unlock()
+	cf.wLock.Lock()
+	n, err := cf.file.WriteAt(data, seekStart)
+	if end := seekStart + int64(n); end > cf.size {
+		cf.size = end
+	}
+	return err
+}
+
+// persist 按落盘策略处理写入，SyncModeAlways 立即落盘，SyncModeInterval 标记待落盘
+func (cf *cachedFile) persist(syncMode string) error {
+	switch syncMode {
+	case SyncModeAlways:
+		return cf.file.Sync()
+	case SyncModeInterval:
+		cf.wLock.Lock()
+		cf.dirty = true
+		cf.wLock.Unlock()
+	}
+	return nil
+}
+
+// sync 有尚未落盘的写入时落盘
+func (cf *cachedFile) sync() error {
+	cf.wLock.Lock()
+	dirty := cf.dirty
+	cf.dirty = false
+	cf.wLock.Unlock()
+	if !dirty {
+		return nil
+	}
+	return cf.file.Sync()
+}
+
+// close 落盘尚未落盘的写入后关闭句柄
+func (cf *cachedFile) close() {
+	if err := cf.sync(); nil != err {
+		log.Error("close", log.Field("path", cf.path), log.Err(err))
+	}
+	_ = cf.file.Close()
+}
+
+// getSize 文件当前大小
+func (cf *cachedFile) getSize() int64 {
+	defer cf.wLock.Unlock()
+	cf.wLock.Lock()
+	return cf.size
+}
+
+// readAt 读取指定位置
+func (cf *cachedFile) readAt(data []byte, seekStart int64) error {
+	_, err := cf.file.ReadAt(data, seekStart)
+	return err
+}
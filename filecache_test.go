@@ -0,0 +1,75 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestFileCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lily-file")
+	if nil != err {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	fc := newFileCache(2)
+	if _, err = fc.acquire(filepath.Join(dir, "none"), false); nil == err {
+		t.Error("acquire without create should fail for missing file")
+	}
+	first, err := fc.acquire(filepath.Join(dir, "0"), true)
+	if nil != err {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = first.append([]byte("lily"))
+		}()
+	}
+	wg.Wait()
+	if first.getSize() != 40 {
+		t.Error("concurrent append should not overlap, size =", first.getSize())
+	}
+	// 使用中的句柄被淘汰后仍可使用，归还后关闭
+	for i := 1; i <= 2; i++ {
+		cf, err := fc.acquire(filepath.Join(dir, strconv.Itoa(i)), true)
+		if nil != err {
+			t.Fatal(err)
+		}
+		fc.release(cf)
+	}
+	if _, ok := fc.files[first.path]; ok || !first.evicted {
+		t.Error("least recently used handle should be evicted")
+	}
+	data := make([]byte, 4)
+	if err = first.readAt(data, 36); nil != err || string(data) != "lily" {
+		t.Error("evicted handle in use should still be readable", err)
+	}
+	fc.release(first)
+	if err = first.readAt(data, 0); nil == err {
+		t.Error("evicted handle should be closed after release")
+	}
+	fc.close()
+	if fc.lru.Len() != 0 || len(fc.files) != 0 {
+		t.Error("close should evict all handles")
+	}
+}
@@ -15,6 +15,7 @@
 package lily
 
 import (
+	"github.com/aberic/lily/connector"
 	"sync"
 )
 
@@ -62,14 +63,23 @@ import (
 //
 // 索引格式
 type form struct {
-	id       string           // 表唯一ID，不能改变
-	name     string           // 表名，根据需求可以随时变化
-	autoID   uint64           // 自增id
-	comment  string           // 描述
-	formType string           // 表类型 SQL/Doc
-	database Database         // 数据库对象
-	indexes  map[string]Index // 索引ID集合
-	fLock    sync.RWMutex
+	id          string            // 表唯一ID，不能改变
+	name        string            // 表名，根据需求可以随时变化
+	autoID      uint64            // 自增id
+	comment     string            // 描述
+	formType    string            // 表类型 SQL/Doc
+	compression string            // 表数据压缩方式
+	engine      string            // 表存储引擎名称
+	versions    uint32            // 每条数据保留的版本数
+	storage     connector.Storage // 表存储对象
+	database    Database          // 数据库对象
+	indexes     map[string]Index  // 索引ID集合
+	segment     uint32            // 当前写入的数据分段文件序号
+	swap        rwLocker          // 数据文件替换锁，读取数据时持有读锁，替换数据及索引文件时持有写锁
+	heads       recordHeads       // 没有主键索引的表中每个key当前记录的位置
+	dropped     bool              // 表是否已被删除，删除后不再接受写入
+	fLock       sync.RWMutex
+	mLock       sync.RWMutex // 表名、索引集合及删除标记读写锁
 }
 
 func (f *form) getAutoID() *uint64 {
@@ -81,6 +91,8 @@ func (f *form) getID() string {
 }
 
 func (f *form) getName() string {
+	defer f.mLock.RUnlock()
+	f.mLock.RLock()
 	return f.name
 }
 
@@ -92,14 +104,77 @@ func (f *form) getDatabase() Database {
 	return f.database
 }
 
+func (f *form) setName(name string) {
+	defer f.mLock.Unlock()
+	f.mLock.Lock()
+	f.name = name
+}
+
 func (f *form) getIndexes() map[string]Index {
+	defer f.mLock.RUnlock()
+	f.mLock.RLock()
 	return f.indexes
 }
 
+func (f *form) setIndexes(indexes map[string]Index) {
+	defer f.mLock.Unlock()
+	f.mLock.Lock()
+	f.indexes = indexes
+}
+
+// drop 标记表已被删除，调用方持有表写锁
+func (f *form) drop() {
+	defer f.mLock.Unlock()
+	f.mLock.Lock()
+	f.dropped = true
+}
+
+// isDropped 表是否已被删除，写入方持有表写锁后判断
+func (f *form) isDropped() bool {
+	defer f.mLock.RUnlock()
+	f.mLock.RLock()
+	return f.dropped
+}
+
 func (f *form) getFormType() string {
 	return f.formType
 }
 
+func (f *form) getCompression() string {
+	return f.compression
+}
+
+func (f *form) getEngine() string {
+	return f.engine
+}
+
+func (f *form) getVersions() uint32 {
+	if f.versions == 0 {
+		return 1
+	}
+	return f.versions
+}
+
+func (f *form) getStorage() connector.Storage {
+	return f.storage
+}
+
+func (f *form) getSwapLocker() WriteLocker {
+	return &f.swap
+}
+
+func (f *form) getHeads() *recordHeads {
+	return &f.heads
+}
+
+func (f *form) getSegment() uint32 {
+	return f.segment
+}
+
+func (f *form) setSegment(segment uint32) {
+	f.segment = segment
+}
+
 func (f *form) lock() {
 	f.fLock.Lock()
 }
@@ -115,3 +190,24 @@ func (f *form) rLock() {
 func (f *form) rUnLock() {
 	f.fLock.RUnlock()
 }
+
+// rwLocker 读写锁
+type rwLocker struct {
+	rw sync.RWMutex
+}
+
+func (r *rwLocker) lock() {
+	r.rw.Lock()
+}
+
+func (r *rwLocker) unLock() {
+	r.rw.Unlock()
+}
+
+func (r *rwLocker) rLock() {
+	r.rw.RLock()
+}
+
+func (r *rwLocker) rUnLock() {
+	r.rw.RUnlock()
+}
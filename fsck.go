@@ -0,0 +1,233 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"github.com/aberic/gnomon"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+const (
+	// FsckCorrupt 索引记录残缺或校验失败
+	FsckCorrupt = "corrupt"
+	// FsckDangling 索引记录指向的数据不存在或校验失败
+	FsckDangling = "dangling"
+	// FsckDuplicate 同一索引中存在多条相同key的索引记录，仅保留最后一条
+	FsckDuplicate = "duplicate"
+)
+
+// FsckIssue 完整性检查发现的问题
+type FsckIssue struct {
+	Database string // Database 数据库名称
+	Form     string // Form 表名称
+	Index    string // Index 索引结构名
+	Position int64  // Position 问题索引记录在索引文件中的起始位置
+	Kind     string // Kind 问题类型 FsckCorrupt/FsckDangling/FsckDuplicate
+	Detail   string // Detail 问题描述
+}
+
+// FsckReport 完整性检查报告
+type FsckReport struct {
+	Indexes  int          // Indexes 检查的索引文件数
+	Entries  int          // Entries 检查的索引记录数
+	Issues   []*FsckIssue // Issues 发现的问题集合
+	Repaired int          // Repaired 修复时移除的索引记录数
+}
+
+// Fsck 离线检查所有库表数据与索引的完整性
+//
+// 须在 lily 停止时执行，逐条校验索引记录及其指向的数据记录
+//
+// repair 为 true 时重写有问题的索引文件，移除残缺、悬空及重复的索引记录，数据文件不做修改
+func Fsck(repair bool) (*FsckReport, error) {
+	report := &FsckReport{}
+	if !gnomon.FilePathExists(obtainConf().LilyBootstrapFilePath) {
+		return report, nil
+	}
+	// 当前 lily.sync 损坏时检查将回滚的历史代，回滚在下次启动时进行
+	lily, _, _, err := loadCatalog(obtainConf().LilyBootstrapFilePath)
+	if nil != err {
+		return nil, err
+	}
+	master, err := obtainMasterKey()
+	if nil != err {
+		return nil, err
+	}
+	for _, db := range lily.Databases {
+		// 加密的库须以主密钥解密数据密钥后才能校验数据记录
+		keys, _, err := openKeyring(db, master)
+		if nil != err {
+			return nil, err
+		}
+		for _, fm := range db.Forms {
+			// 默认存储引擎以外的表没有数据及索引文件
+			if gnomon.StringIsNotEmpty(fm.Engine) && fm.Engine != EngineFile {
+				continue
+			}
+			dataID, formID := db.ID, fm.ID
+			dataPath := func(segment uint32) string {
+				// 尚未迁移的旧版表数据文件即第0个数据分段
+				if legacyPath := pathFormLegacyDataFile(dataID, formID); segment == 0 && gnomon.FilePathExists(legacyPath) &&
+					!gnomon.FilePathExists(pathFormDataFile(dataID, formID, 0)) {
+					return legacyPath
+				}
+				return pathFormDataFile(dataID, formID, segment)
+			}
+			for _, idx := range fm.Indexes {
+				indexPath := pathFormIndexFile(db.ID, fm.ID, idx.ID)
+				if !gnomon.FilePathExists(indexPath) {
+					continue
+				}
+				check, err := fsckIndex(dataPath, indexPath, keys, repair)
+				if nil != err {
+					return nil, err
+				}
+				for _, issue := range check.Issues {
+					issue.Database, issue.Form, issue.Index = db.Name, fm.Name, idx.KeyStructure
+				}
+				report.Indexes++
+				report.Entries += check.Entries
+				report.Issues = append(report.Issues, check.Issues...)
+				report.Repaired += check.Repaired
+			}
+		}
+	}
+	return report, nil
+}
+
+// fsckIndex 检查单个索引文件
+//
+// dataPath 根据数据分段文件序号获取索引所属表数据分段文件路径
+//
+// indexPath 索引文件路径
+//
+// keys 索引所属库数据密钥环
+func fsckIndex(dataPath func(segment uint32) string, indexPath string, keys *keyring, repair bool) (*FsckReport, error) {
+	var (
+		indexData []byte
+		segments  = &fsckSegments{dataPath: dataPath, keys: keys, files: map[uint32]*os.File{}, sizes: map[uint32]int64{}}
+		err       error
+	)
+	defer segments.close()
+	report := &FsckReport{}
+	if indexData, err = ioutil.ReadFile(indexPath); nil != err {
+		return nil, err
+	}
+	format, err := detectIndexFormat(indexData)
+	if nil != err && ErrIndexCorrupt != err {
+		return nil, err
+	}
+	if nil != err {
+		// 文件头残缺的索引文件视为不含任何有效索引记录
+		report.Issues = append(report.Issues, &FsckIssue{Kind: FsckCorrupt, Detail: err.Error()})
+		format = indexFormat{headerLen: len(indexData), entryLen: indexEntryLen}
+	}
+	var (
+		entryLen  = format.entryLen
+		records   []*indexRecord
+		positions []int64
+		last      = map[string]int{} // md5Key对应最后一条有效索引记录下标
+	)
+	for position := format.headerLen; position < len(indexData); position += entryLen {
+		report.Entries++
+		if position+entryLen > len(indexData) {
+			report.Issues = append(report.Issues, &FsckIssue{Position: int64(position), Kind: FsckCorrupt, Detail: "index tail is torn"})
+			break
+		}
+		record, err := parseIndexEntry(indexData[position : position+entryLen])
+		if nil != err {
+			report.Issues = append(report.Issues, &FsckIssue{Position: int64(position), Kind: FsckCorrupt, Detail: err.Error()})
+			continue
+		}
+		if detail := segments.check(record); gnomon.StringIsNotEmpty(detail) {
+			report.Issues = append(report.Issues, &FsckIssue{Position: int64(position), Kind: FsckDangling, Detail: detail})
+			continue
+		}
+		last[record.md516Key] = len(records)
+		records = append(records, record)
+		positions = append(positions, int64(position))
+	}
+	var kept []*indexRecord
+	for index, record := range records {
+		if last[record.md516Key] != index {
+			report.Issues = append(report.Issues, &FsckIssue{Position: positions[index], Kind: FsckDuplicate, Detail: strings.Join([]string{"md5 key", record.md516Key, "appears again later"}, " ")})
+			continue
+		}
+		kept = append(kept, record)
+	}
+	// 旧版索引文件在修复时一并升级为当前格式
+	if repair && (len(report.Issues) > 0 || !format.current()) {
+		buf := newIndexBuffer()
+		for _, record := range kept {
+			buf.Write(indexEntry(record.hashKey, record.md516Key, record.segment, record.seekStart, record.seekLast))
+		}
+		tmpPath := strings.Join([]string{indexPath, ".fsck"}, "")
+		if err = writeFileSync(tmpPath, buf.Bytes()); nil != err {
+			return nil, err
+		}
+		if err = os.Rename(tmpPath, indexPath); nil != err {
+			return nil, err
+		}
+		report.Repaired = report.Entries - len(kept)
+	}
+	return report, nil
+}
+
+// fsckSegments 完整性检查时按需打开的数据分段文件集合
+type fsckSegments struct {
+	dataPath func(segment uint32) string
+	keys     *keyring
+	files    map[uint32]*os.File
+	sizes    map[uint32]int64
+}
+
+// check 检查索引记录指向的数据记录，返回问题描述，无问题返回空字符串
+func (fs *fsckSegments) check(record *indexRecord) string {
+	file, ok := fs.files[record.segment]
+	if !ok {
+		var err error
+		if file, err = os.OpenFile(fs.dataPath(record.segment), os.O_RDONLY, 0644); nil != err {
+			file = nil
+		} else if info, err := file.Stat(); nil == err {
+			fs.sizes[record.segment] = info.Size()
+		}
+		fs.files[record.segment] = file
+	}
+	if nil == file {
+		return "data segment file does not exist"
+	}
+	if record.seekLast <= 0 || record.seekStart < 0 || record.seekStart+int64(record.seekLast) > fs.sizes[record.segment] {
+		return "record is out of data segment file range"
+	}
+	data := make([]byte, record.seekLast)
+	if _, err := file.ReadAt(data, record.seekStart); nil != err {
+		return err.Error()
+	}
+	if _, err := decodeRecord(data, fs.keys); nil != err {
+		return err.Error()
+	}
+	return ""
+}
+
+// close 关闭所有已打开的数据分段文件
+func (fs *fsckSegments) close() {
+	for _, file := range fs.files {
+		if nil != file {
+			_ = file.Close()
+		}
+	}
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"github.com/aberic/gnomon"
+	"github.com/vmihailenco/msgpack"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDecodeRecord(t *testing.T) {
+	data, err := encodeRecord(&valueData{K: "1", I: true, V: "one"}, codecNone, nil)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if vd, err := decodeRecord(data, nil); nil != err || vd.K != "1" || vd.V != "one" {
+		t.Error("decode record failed", vd, err)
+	}
+	data[len(data)-1] ^= 0xff
+	if _, err = decodeRecord(data, nil); ErrRecordCorrupt != err {
+		t.Error("corrupt record should fail the checksum", err)
+	}
+	for _, codec := range []byte{codecGzip, codecFlate, codecSnappy} {
+		data, err = encodeRecord(&valueData{K: "3", I: true, V: strings.Repeat("three", 32)}, codec, nil)
+		if nil != err {
+			t.Fatal(err)
+		}
+		if vd, err := decodeRecord(data, nil); nil != err || vd.V != strings.Repeat("three", 32) {
+			t.Error("decode compressed record failed", codec, err)
+		}
+	}
+	legacy, _ := msgpack.Marshal(&valueData{K: "2", I: true, V: "two"})
+	if vd, err := decodeRecord(legacy, nil); nil != err || vd.K != "2" {
+		t.Error("legacy record should still decode", vd, err)
+	}
+}
+
+// legacyIndexEntry 组装一条文本索引记录
+func legacyIndexEntry(hashKey uint64, md5Key string, seekStart int64, seekLast int) string {
+	return strings.Join([]string{
+		gnomon.StringPrefixSupplementZero(gnomon.ScaleUint64ToDDuoString(hashKey), 11),
+		md5Key,
+		gnomon.StringPrefixSupplementZero(gnomon.ScaleInt64ToDDuoString(seekStart), 11),
+		gnomon.StringPrefixSupplementZero(gnomon.ScaleIntToDDuoString(seekLast), 4),
+	}, "")
+}
+
+func TestIndexEntry(t *testing.T) {
+	entry := indexEntry(hash("1"), gnomon.HashMD516("1"), 3, 1<<40, 1<<32)
+	if len(entry) != indexEntryLen {
+		t.Fatal("index entry length should be", indexEntryLen, "got", len(entry))
+	}
+	record, err := parseIndexEntry(entry)
+	if nil != err || record.md516Key != gnomon.HashMD516("1") || record.segment != 3 || record.seekStart != 1<<40 || record.seekLast != 1<<32 {
+		t.Error("parse index entry failed", record, err)
+	}
+	entry[10] ^= 0xff
+	if _, err = parseIndexEntry(entry); ErrIndexCorrupt != err {
+		t.Error("corrupt index entry should fail the checksum", err)
+	}
+	if format, err := detectIndexFormat(indexFileHeader()); nil != err || !format.current() {
+		t.Error("binary index file should be detected", format, err)
+	}
+	if _, err = detectIndexFormat([]byte(indexFileMagic)); ErrIndexCorrupt != err {
+		t.Error("torn index header should be corrupt", err)
+	}
+	// 文本索引记录
+	legacy := legacyIndexEntry(hash("1"), gnomon.HashMD516("1"), 1<<40, 1024)
+	if record, err = parseIndexEntry([]byte(legacy)); nil != err || record.segment != 0 || record.seekStart != 1<<40 || record.seekLast != 1024 {
+		t.Error("parse legacy index entry failed", record, err)
+	}
+	if format, err := detectIndexFormat([]byte(legacy + legacy)); nil != err || format.current() || format.entryLen != indexTextEntryLen {
+		t.Error("legacy index file should be detected", format, err)
+	}
+	if _, err = parseIndexEntry([]byte(legacy[:30])); ErrIndexCorrupt != err {
+		t.Error("index entry of unknown length should be corrupt", err)
+	}
+}
+
+func TestFsckIndex(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lily-fsck")
+	if nil != err {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	var (
+		dataPath  = func(segment uint32) string { return filepath.Join(dir, strconv.FormatUint(uint64(segment), 10)+".dat") }
+		indexPath = filepath.Join(dir, "index.idx")
+		dataBytes []byte
+		buf       = newIndexBuffer()
+	)
+	for _, key := range []string{"1", "2"} {
+		record, _ := encodeRecord(&valueData{K: key, I: true, V: key}, codecNone, nil)
+		buf.Write(indexEntry(hash(key), gnomon.HashMD516(key), 0, int64(len(dataBytes)), len(record)))
+		dataBytes = append(dataBytes, record...)
+	}
+	// 重复的key，仅保留最后一条
+	buf.Write(indexEntry(hash("1"), gnomon.HashMD516("1"), 0, 0, len(dataBytes)/2))
+	// 指向数据文件之外
+	buf.Write(indexEntry(hash("3"), gnomon.HashMD516("3"), 0, int64(len(dataBytes)), 20))
+	// 指向不存在的数据分段
+	buf.Write(indexEntry(hash("6"), gnomon.HashMD516("6"), 1, 0, 20))
+	// 校验失败
+	corrupt := indexEntry(hash("4"), gnomon.HashMD516("4"), 0, 0, 10)
+	corrupt[0] = 'z'
+	buf.Write(corrupt)
+	// 尾部残缺
+	buf.Write(indexEntry(hash("5"), gnomon.HashMD516("5"), 0, 0, 10)[:20])
+	if err = ioutil.WriteFile(dataPath(0), dataBytes, 0644); nil != err {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(indexPath, buf.Bytes(), 0644); nil != err {
+		t.Fatal(err)
+	}
+	report, err := fsckIndex(dataPath, indexPath, nil, true)
+	if nil != err {
+		t.Fatal(err)
+	}
+	kinds := map[string]int{}
+	for _, issue := range report.Issues {
+		t.Log(issue.Kind, issue.Position, issue.Detail)
+		kinds[issue.Kind]++
+	}
+	if kinds[FsckDuplicate] != 1 || kinds[FsckDangling] != 2 || kinds[FsckCorrupt] != 2 {
+		t.Error("unexpected issues", kinds)
+	}
+	if report.Repaired != 5 {
+		t.Error("repair should remove 5 entries, got", report.Repaired)
+	}
+	if report, err = fsckIndex(dataPath, indexPath, nil, false); nil != err || len(report.Issues) != 0 || report.Entries != 2 {
+		t.Error("index should be clean after repair", report, err)
+	}
+}
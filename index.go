@@ -16,25 +16,30 @@ package lily
 
 import (
 	"bufio"
-	"errors"
 	"github.com/aberic/gnomon"
 	"github.com/aberic/gnomon/log"
+	"github.com/aberic/lily/api"
 	"io"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 )
 
 // index 索引对象
 //
-// 5位key及16位md5后key及5位起始seek和4位持续seek
+// 索引文件以16字节文件头开始，其后每条记录为40字节二进制索引记录，格式见 indexEntry
 type index struct {
 	id           string // id 索引唯一ID
 	primary      bool   // 是否主键
+	unique       bool   // 是否唯一索引
 	keyStructure string // keyStructure 按照规范结构组成的索引字段名称，由对象结构层级字段通过'.'组成，如'i','in.s'
 	form         Form   // form 索引所属表对象
 	node         Nodal  // 节点
 	fLock        sync.RWMutex
+	status       IndexStatus // 构建状态，State 为空表示无需构建
+	sLock        sync.RWMutex
 }
 
 // getID 索引唯一ID
@@ -47,6 +52,36 @@ func (i *index) isPrimary() bool {
 	return i.primary
 }
 
+// isUnique 是否唯一索引
+func (i *index) isUnique() bool {
+	return i.unique
+}
+
+// isReady 是否已构建完成，构建完成前检索不采用该索引
+func (i *index) isReady() bool {
+	defer i.sLock.RUnlock()
+	i.sLock.RLock()
+	return i.status.State != IndexBuilding && i.status.State != IndexFailed
+}
+
+// getStatus 获取构建状态
+func (i *index) getStatus() *IndexStatus {
+	defer i.sLock.RUnlock()
+	i.sLock.RLock()
+	status := i.status
+	if gnomon.StringIsEmpty(status.State) {
+		status.State = IndexReady
+	}
+	return &status
+}
+
+// setStatus 设置构建状态
+func (i *index) setStatus(status *IndexStatus) {
+	defer i.sLock.Unlock()
+	i.sLock.Lock()
+	i.status = *status
+}
+
 // getKey 索引字段名称，由对象结构层级字段通过'.'组成，如
 func (i *index) getKeyStructure() string {
 	return i.keyStructure
@@ -65,6 +100,10 @@ func (i *index) get(key string, hashKey uint64) *readResult {
 	return i.node.get(key, hashKey, hashKey)
 }
 
+func (i *index) getLink(key string, hashKey uint64) Link {
+	return i.node.getLink(key, hashKey)
+}
+
 func (i *index) recover() {
 	i.recoverMultiReadFile()
 }
@@ -73,78 +112,188 @@ func (i *index) recoverMultiReadFile() {
 	indexFilePath := pathFormIndexFile(i.form.getDatabase().getID(), i.form.getID(), i.id)
 	if gnomon.FilePathExists(indexFilePath) { // 索引文件存在才继续恢复
 		var (
-			file *os.File
-			err  error
+			file   *os.File
+			format indexFormat
+			err    error
 		)
-		defer func() { _ = file.Close() }()
-		if file, err = os.OpenFile(indexFilePath, os.O_RDONLY, 0644); nil != err {
+		if file, err = os.OpenFile(indexFilePath, os.O_RDWR, 0644); nil != err {
 			log.Panic("index recover multi read failed", log.Err(err))
 		}
-		_, err = file.Seek(0, io.SeekStart) // 文件下标置为文件的起始位置
-		if err != nil {
+		format, err = i.read(file)
+		_ = file.Close()
+		if nil != err {
 			log.Panic("index recover multi read failed", log.Err(err))
 		}
-		if err = i.read(file, 0); nil != err && io.EOF != err {
-			log.Panic("index recover multi read failed", log.Err(err))
+		if !format.current() { // 旧版索引文件，升级为当前版本
+			if err = i.upgrade(indexFilePath); nil != err {
+				log.Panic("index recover upgrade failed", log.Err(err))
+			}
 		}
 	}
 }
 
-func (i *index) read(file *os.File, offset int64) (err error) {
+// read 顺序读取索引文件并载入索引树，返回索引文件格式
+//
+// 校验失败的索引记录将被跳过，尾部残缺的索引记录视为崩溃时未写完并被截断
+func (i *index) read(file *os.File) (indexFormat, error) {
 	var (
-		inputReader *bufio.Reader
-		data        []byte
-		peekOnce    = 42000
-		haveNext    = true
-		position    int64
+		reader   = bufio.NewReaderSize(file, indexEntryLen*1000)
+		record   *indexRecord
+		position int64
+		n        int
+		err      error
 	)
-	if _, err = file.Seek(offset, io.SeekStart); nil != err { //表示文件的起始位置，从第二个字符往后写入。
-		return
-	}
-	inputReader = bufio.NewReaderSize(file, peekOnce)
-	data, err = inputReader.Peek(peekOnce)
-	if nil != err && io.EOF != err {
-		return
-	} else if nil != err && io.EOF == err {
-		if len(data) == 0 {
-			return
+	head, _ := reader.Peek(indexHeaderLen)
+	format, err := detectIndexFormat(head)
+	if ErrIndexCorrupt == err && len(head) < indexHeaderLen {
+		// 文件头未写完即崩溃，其中没有索引记录
+		log.Warn("index header is torn, truncate it", log.Field("path", file.Name()))
+		defer store().invalidate(file.Name())
+		return currentIndexFormat, file.Truncate(0)
+	}
+	if nil != err {
+		return format, err
+	}
+	if _, err = reader.Discard(format.headerLen); nil != err {
+		return format, err
+	}
+	position = int64(format.headerLen)
+	entry := make([]byte, format.entryLen)
+	for {
+		if n, err = io.ReadFull(reader, entry); nil != err {
+			break
 		}
-		if len(data)%42 != 0 {
-			return errors.New("index lens does't match")
+		if record, err = parseIndexEntry(entry); nil != err {
+			log.Warn("index entry is corrupt, skip it and run lily fsck to repair",
+				log.Field("path", file.Name()), log.Field("position", position))
+		} else {
+			ln := i.node.put("", record.hashKey, record.hashKey, true).getLink()
+			ln.setSeekStartIndex(position)
+			ln.setMD5Key(record.md516Key)
+			ln.setSegment(record.segment)
+			ln.setSeekStart(record.seekStart)
+			ln.setSeekLast(record.seekLast)
+			atomic.AddUint64(i.form.getAutoID(), 1) // ID自增
 		}
+		position += int64(format.entryLen)
 	}
-	indexStr := string(data)
-	indexStrLen := int64(len(indexStr))
-	for haveNext {
-		go func(i *index, position int64) {
-			var p0, p1, p2, p3, p4 int64
-			// 读取11位key及16位md5后key及5位起始seek和4位持续seek
-			p0 = position
-			p1 = p0 + 11
-			p2 = p1 + 16
-			p3 = p2 + 11
-			p4 = p3 + 4
-			hashKey := gnomon.ScaleDDuoStringToUint64(indexStr[p0:p1])
-			md516Key := indexStr[p1:p2]
-			seekStart := gnomon.ScaleDDuoStringToInt64(indexStr[p2:p3])     // value最终存储在文件中的起始位置
-			seekLast := int(gnomon.ScaleDDuoStringToInt64(indexStr[p3:p4])) // value最终存储在文件中的持续长度
-			ib := i.node.put("", hashKey, hashKey, true)
-			ib.getLink().setSeekStartIndex(p0)
-			ib.getLink().setMD5Key(md516Key)
-			ib.getLink().setSeekStart(seekStart)
-			ib.getLink().setSeekLast(seekLast)
-			atomic.AddUint64(i.form.getAutoID(), 1) // ID自增
-		}(i, position)
-		position += 36
-		if indexStrLen < position+36 {
-			haveNext = false
+	switch err {
+	default:
+		return format, err
+	case io.EOF:
+		return format, nil
+	case io.ErrUnexpectedEOF:
+		log.Warn("index tail is torn, truncate it", log.Field("path", file.Name()), log.Field("position", position), log.Field("torn", n))
+		defer store().invalidate(file.Name())
+		return format, file.Truncate(position)
+	}
+}
+
+// upgrade 将旧版索引文件重写为当前版本，并同步更新链表在索引文件中的位置
+func (i *index) upgrade(indexFilePath string) error {
+	var (
+		links []Link
+		buf   = newIndexBuffer()
+	)
+	rangeLinks(i.node, func(ln Link) {
+		links = append(links, ln)
+	})
+	for _, ln := range links {
+		buf.Write(indexEntry(linkHashKey(ln), ln.getMD516Key(), ln.getSegment(), ln.getSeekStart(), ln.getSeekLast()))
+	}
+	tmpPath := strings.Join([]string{indexFilePath, ".upgrade"}, "")
+	if err := writeFileSync(tmpPath, buf.Bytes()); nil != err {
+		return err
+	}
+	if err := os.Rename(tmpPath, indexFilePath); nil != err {
+		return err
+	}
+	store().invalidate(indexFilePath)
+	for n, ln := range links {
+		ln.setSeekStartIndex(indexEntryPosition(n))
+	}
+	return nil
+}
+
+// rebuild 依据数据记录重建索引树及索引文件
+//
+// 新的索引树及索引文件构建完成后再替换，替换期间持有表数据文件替换锁
+func (i *index) rebuild(records []*scannedRecord) (int64, error) {
+	var (
+		nd     = &node{level: 1, degreeIndex: 0, preNode: nil, nodes: []Nodal{}, index: i}
+		links  []Link
+		autoID uint64
+		buf    = newIndexBuffer()
+	)
+	for _, record := range records {
+		var customKeys []*customKey
+		switch i.keyStructure {
+		default:
+			var err error
+			if customKeys, err = customIndexKeys(i, record.vd.V); nil != err {
+				continue
+			}
+			for _, ck := range customKeys {
+				ck.key = postingKey(i, ck.key, record.vd.K)
+			}
+		case indexAutoID:
+			autoID++
+			customKeys = []*customKey{{key: strconv.FormatUint(autoID, 10), hashKey: autoID}}
+		case indexDefaultID:
+			customKeys = []*customKey{{key: record.vd.K, hashKey: hash(record.vd.K)}}
+		}
+		for _, ck := range customKeys {
+			ln := nd.put(ck.key, ck.hashKey, ck.hashKey, true).getLink()
+			if ln.getSeekStartIndex() == -1 {
+				ln.setSeekStartIndex(indexEntryPosition(len(links)))
+				links = append(links, ln)
+			}
+			ln.setMD5Key(gnomon.HashMD516(ck.key))
+			ln.setSegment(record.segment)
+			ln.setSeekStart(record.seekStart)
+			ln.setSeekLast(record.seekLast)
 		}
 	}
-	if nil == err {
-		offset += int64(peekOnce)
-		return i.read(file, offset)
+	for _, ln := range links {
+		buf.Write(indexEntry(linkHashKey(ln), ln.getMD516Key(), ln.getSegment(), ln.getSeekStart(), ln.getSeekLast()))
+	}
+	indexFilePath := pathFormIndexFile(i.form.getDatabase().getID(), i.form.getID(), i.id)
+	tmpPath := strings.Join([]string{indexFilePath, ".rebuild"}, "")
+	if err := writeFileSync(tmpPath, buf.Bytes()); nil != err {
+		return 0, err
+	}
+	swap := i.form.getSwapLocker()
+	defer swap.unLock()
+	swap.lock()
+	if err := os.Rename(tmpPath, indexFilePath); nil != err {
+		return 0, err
+	}
+	store().invalidate(indexFilePath)
+	i.node = nd
+	if i.keyStructure == indexAutoID {
+		atomic.StoreUint64(i.form.getAutoID(), autoID)
+	}
+	return int64(len(links)), nil
+}
+
+// legacyIndex 是否为旧版散列编码的自定义索引，此类索引中字符串key的顺序与字典序无关
+func legacyIndex(iv *api.Index) bool {
+	return nil != iv && !iv.Ordered && iv.KeyStructure != indexAutoID && iv.KeyStructure != indexDefaultID
+}
+
+// hasLegacyIndex 库中默认存储引擎的表是否存在旧版散列编码的自定义索引
+func hasLegacyIndex(dv *api.Database) bool {
+	for _, fv := range dv.Forms {
+		if gnomon.StringIsNotEmpty(fv.Engine) && fv.Engine != EngineFile {
+			continue
+		}
+		for _, iv := range fv.Indexes {
+			if legacyIndex(iv) {
+				return true
+			}
+		}
 	}
-	return
+	return false
 }
 
 func (i *index) getNode() Nodal {
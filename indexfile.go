@@ -0,0 +1,157 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"github.com/aberic/gnomon"
+	"hash/crc32"
+	"strconv"
+	"strings"
+)
+
+const (
+	// indexFileMagic 二进制索引文件头标识，不以该标识开头的索引文件为文本格式
+	indexFileMagic = "LIDX"
+	// indexFormatVersion 二进制索引文件格式版本，文本格式视为第1版
+	indexFormatVersion uint16 = 2
+	// indexHeaderLen 二进制索引文件头长度，4字节标识 + 2字节格式版本 + 2字节索引记录长度 + 8字节保留
+	indexHeaderLen = 16
+	// indexEntryLen 二进制索引记录长度，8字节key + 8字节md5后key + 4字节数据分段序号 + 8字节起始seek + 8字节持续seek + 4字节crc32
+	indexEntryLen = 40
+	// indexTextEntryLen 文本索引记录长度，11位key + 16位md5后key + 11位起始seek + 4位持续seek，仅支持读取及迁移
+	indexTextEntryLen = 42
+)
+
+// indexRecord 解析后的索引记录
+type indexRecord struct {
+	hashKey   uint64
+	md516Key  string
+	segment   uint32
+	seekStart int64
+	seekLast  int
+}
+
+// indexFormat 索引文件格式
+type indexFormat struct {
+	headerLen int // headerLen 文件头长度，文本格式为0
+	entryLen  int // entryLen 单条索引记录长度
+}
+
+// currentIndexFormat 当前写入的索引文件格式
+var currentIndexFormat = indexFormat{headerLen: indexHeaderLen, entryLen: indexEntryLen}
+
+// current 是否为当前写入的索引文件格式，其余格式仅支持读取
+func (f indexFormat) current() bool {
+	return f == currentIndexFormat
+}
+
+// version 索引文件格式版本
+func (f indexFormat) version() uint16 {
+	if f.headerLen == 0 {
+		return 1
+	}
+	return indexFormatVersion
+}
+
+// indexFileHeader 二进制索引文件头
+func indexFileHeader() []byte {
+	header := make([]byte, indexHeaderLen)
+	copy(header, indexFileMagic)
+	binary.BigEndian.PutUint16(header[4:6], indexFormatVersion)
+	binary.BigEndian.PutUint16(header[6:8], indexEntryLen)
+	return header
+}
+
+// indexEntryPosition 当前格式索引文件中第 n 条索引记录的起始位置
+func indexEntryPosition(n int) int64 {
+	return int64(indexHeaderLen + n*indexEntryLen)
+}
+
+// newIndexBuffer 以文件头开始的索引文件内容缓冲，用于整体重写索引文件
+func newIndexBuffer() *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	buf.Write(indexFileHeader())
+	return buf
+}
+
+// detectIndexFormat 根据索引文件头部内容判断索引文件格式
+//
+// 空文件视为当前格式，文件头残缺或格式版本不支持时返回错误
+func detectIndexFormat(head []byte) (indexFormat, error) {
+	if len(head) == 0 {
+		return currentIndexFormat, nil
+	}
+	if !bytes.HasPrefix(head, []byte(indexFileMagic)) {
+		return indexFormat{entryLen: indexTextEntryLen}, nil
+	}
+	if len(head) < indexHeaderLen {
+		return indexFormat{}, ErrIndexCorrupt
+	}
+	if version := binary.BigEndian.Uint16(head[4:6]); version != indexFormatVersion {
+		return indexFormat{}, errors.New(strings.Join([]string{"index format version", strconv.Itoa(int(version)), "is unsupported"}, " "))
+	}
+	if entryLen := binary.BigEndian.Uint16(head[6:8]); entryLen != indexEntryLen {
+		return indexFormat{}, ErrIndexCorrupt
+	}
+	return currentIndexFormat, nil
+}
+
+// indexEntry 组装一条二进制索引记录
+//
+// 大端 key + md5后key + 数据分段序号 + 起始seek + 持续seek，以及前述内容的crc32
+func indexEntry(hashKey uint64, md5Key string, segment uint32, seekStart int64, seekLast int) []byte {
+	entry := make([]byte, indexEntryLen)
+	binary.BigEndian.PutUint64(entry[0:8], hashKey)
+	md5, _ := hex.DecodeString(md5Key)
+	copy(entry[8:16], md5)
+	binary.BigEndian.PutUint32(entry[16:20], segment)
+	binary.BigEndian.PutUint64(entry[20:28], uint64(seekStart))
+	binary.BigEndian.PutUint64(entry[28:36], uint64(seekLast))
+	binary.BigEndian.PutUint32(entry[36:40], crc32.ChecksumIEEE(entry[:36]))
+	return entry
+}
+
+// parseIndexEntry 校验并解析一条索引记录，依据长度兼容文本索引记录
+//
+// 文本索引记录不分段，均视为第0个数据分段
+func parseIndexEntry(entry []byte) (*indexRecord, error) {
+	switch len(entry) {
+	default:
+		return nil, ErrIndexCorrupt
+	case indexTextEntryLen:
+		text := string(entry)
+		return &indexRecord{
+			hashKey:   gnomon.ScaleDDuoStringToUint64(text[0:11]),
+			md516Key:  text[11:27],
+			seekStart: gnomon.ScaleDDuoStringToInt64(text[27:38]),
+			seekLast:  int(gnomon.ScaleDDuoStringToInt64(text[38:42])),
+		}, nil
+	case indexEntryLen:
+	}
+	if crc32.ChecksumIEEE(entry[:36]) != binary.BigEndian.Uint32(entry[36:40]) {
+		return nil, ErrIndexCorrupt
+	}
+	return &indexRecord{
+		hashKey:   binary.BigEndian.Uint64(entry[0:8]),
+		md516Key:  hex.EncodeToString(entry[8:16]),
+		segment:   binary.BigEndian.Uint32(entry[16:20]),
+		seekStart: int64(binary.BigEndian.Uint64(entry[20:28])),
+		seekLast:  int(binary.BigEndian.Uint64(entry[28:36])),
+	}, nil
+}
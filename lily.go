@@ -19,10 +19,12 @@ import (
 	"github.com/aberic/gnomon"
 	"github.com/aberic/gnomon/log"
 	"github.com/aberic/lily/api"
-	"github.com/golang/protobuf/proto"
-	"io/ioutil"
+	"github.com/aberic/lily/connector"
+	"io"
+	"os"
 	"strings"
 	"sync"
+	"time"
 )
 
 const (
@@ -46,6 +48,16 @@ var (
 	ErrDataIsNil = errors.New("database had never been created")
 	// ErrKeyIsNil 自定义error信息
 	ErrKeyIsNil = errors.New("put keyStructure can not be nil")
+	// ErrCompressionInvalid 自定义error信息
+	ErrCompressionInvalid = errors.New("compression is not supported")
+	// ErrEngineInvalid 自定义error信息
+	ErrEngineInvalid = errors.New("engine is not registered")
+	// ErrEngineUnsupported 自定义error信息
+	ErrEngineUnsupported = errors.New("operation is not supported by form engine")
+	// ErrTTLInvalid 自定义error信息
+	ErrTTLInvalid = errors.New("ttl can not be negative")
+	// ErrSyncModeInvalid 自定义error信息
+	ErrSyncModeInvalid = errors.New("sync mode must be one of none, interval and always")
 )
 
 // Lily 祖宗！
@@ -56,11 +68,15 @@ var (
 //
 // 存储格式 {dataDir}/Data/{dataName}/{formName}/{formName}.dat/idx...
 type Lily struct {
-	lilyData  *api.Lily
-	conf      *Conf
-	databases map[string]Database
-	once      sync.Once
-	lock      sync.Mutex
+	lilyData   *api.Lily
+	generation uint64 // generation lily.sync 当前代数，每次写入递增
+	conf       *Conf
+	databases  map[string]Database // 库集合，只以新集合替换
+	dLock      sync.RWMutex        // 库集合读写锁
+	once       sync.Once
+	lock       sync.Mutex
+	bgStop     chan struct{} // 关闭时停止后台任务
+	bgLock     sync.Mutex
 }
 
 // ObtainLily 获取 Lily 对象
@@ -92,11 +108,85 @@ func (l *Lily) setConf(conf *Conf) {
 func (l *Lily) syncRPC2Store() {
 	defer l.lock.Unlock()
 	l.lock.Lock()
-	data, err := proto.Marshal(l.lilyData)
-	if nil != err {
+	l.storeRPC()
+}
+
+// storeRPC 将 api.Lily 对象写入本地文件中，调用方持有 l.lock
+//
+// 写入临时文件后重命名替换 lily.sync，并保留 CatalogGenerations 个历史代，落盘策略为 SyncModeNone 以外时写入后立即落盘
+func (l *Lily) storeRPC() {
+	conf := obtainConf()
+	if err := storeCatalog(conf.LilyBootstrapFilePath, l.lilyData, l.generation+1, conf.SyncMode != SyncModeNone, int(conf.CatalogGenerations)); nil != err {
+		log.Error("storeRPC", log.Err(err))
+		return
+	}
+	l.generation++
+}
+
+// startBackground 启动后台任务，重复调用无效
+//
+// interval 落盘策略的定时落盘，以及过期数据的定时回收
+func (l *Lily) startBackground() {
+	defer l.bgLock.Unlock()
+	l.bgLock.Lock()
+	if nil != l.bgStop {
 		return
 	}
-	_, _ = gnomon.FileAppend(obtainConf().LilyBootstrapFilePath, data, true)
+	l.bgStop = make(chan struct{})
+	go l.tick(l.bgStop, time.Duration(obtainConf().SyncInterval)*time.Millisecond, l.flush)
+	go l.tick(l.bgStop, time.Duration(obtainConf().SweepInterval)*time.Second, l.sweep)
+}
+
+// stopBackground 停止后台任务
+func (l *Lily) stopBackground() {
+	defer l.bgLock.Unlock()
+	l.bgLock.Lock()
+	if nil != l.bgStop {
+		close(l.bgStop)
+		l.bgStop = nil
+	}
+}
+
+// tick 每隔 interval 执行一次 fn，直至 stop 关闭
+func (l *Lily) tick(stop chan struct{}, interval time.Duration, fn func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			fn()
+		}
+	}
+}
+
+// flush 落盘全部尚未落盘的数据、索引及预写日志
+func (l *Lily) flush() {
+	store().flush()
+	for _, db := range l.getDatabaseMap() {
+		if err := db.flush(); nil != err {
+			log.Error("flush", log.Field("database", db.getName()), log.Err(err))
+		}
+	}
+}
+
+// sweep 回收全部表中已过期的数据
+func (l *Lily) sweep() {
+	for _, db := range l.getDatabaseMap() {
+		for _, form := range db.getForms() {
+			expirer, ok := form.getStorage().(connector.Expirer)
+			if !ok {
+				continue
+			}
+			count, err := expirer.Expire()
+			if nil != err {
+				log.Error("sweep", log.Field("database", db.getName()), log.Field("form", form.getName()), log.Err(err))
+			} else if count > 0 {
+				log.Debug("sweep", log.Field("database", db.getName()), log.Field("form", form.getName()), log.Field("count", count))
+			}
+		}
+	}
 }
 
 // Start 启动lily
@@ -105,81 +195,203 @@ func (l *Lily) syncRPC2Store() {
 func (l *Lily) Start() {
 	log.Info("lily service starting")
 	l.initialize()
+	l.startBackground()
 }
 
 // Stop 停止lily
 func (l *Lily) Stop() {
-	// todo 停止lily
+	l.stopBackground()
+	defer l.lock.Unlock()
+	l.lock.Lock()
+	for _, db := range l.getDatabaseMap() {
+		if err := db.close(); nil != err {
+			log.Error("stop", log.Field("database", db.getName()), log.Err(err))
+		}
+	}
+	store().close()
 }
 
 // Restart 重新启动lily
 //
 // 调用 Restart() 会恢复 Lily 的索引，如果 Lily 索引存在，则 Restart() 什么也不会做
 func (l *Lily) Restart() {
-	defer l.lock.Unlock()
-	l.lock.Lock()
+	l.startBackground()
 	if gnomon.FilePathExists(obtainConf().LilyBootstrapFilePath) {
-		var (
-			data []byte
-			lily api.Lily
-			err  error
-		)
-		if data, err = ioutil.ReadFile(obtainConf().LilyBootstrapFilePath); nil != err {
-			log.Panic("restart failed, file read error", log.Err(err))
+		defer l.lock.Unlock()
+		l.lock.Lock()
+		path := obtainConf().LilyBootstrapFilePath
+		lily, generation, rollback, err := loadCatalog(path)
+		if nil != err {
+			log.Panic("restart failed, catalog read error", log.Err(err))
 		}
-		if err = proto.Unmarshal(data, &lily); nil != err {
-			log.Panic("restart failed, proto unmarshal error", log.Err(err))
+		if rollback {
+			// 保留损坏的文件以便排查，回滚的历史代在恢复后重新写入为当前文件
+			if err = os.Rename(path, path+catalogCorruptSuffix); nil != err {
+				log.Panic("restart failed, corrupt catalog rename error", log.Err(err))
+			}
 		}
-		l.lilyData = &lily
+		l.lilyData, l.generation = lily, generation
 		l.recover()
+		if rollback {
+			l.storeRPC()
+		}
 		return
 	}
+	// initialize 过程中会同步 lily.sync，不能持有 l.lock
 	l.initialize()
 }
 
 // recover Lily恢复数据
 func (l *Lily) recover() {
-	var wg sync.WaitGroup
-	l.databases = map[string]Database{}
+	var (
+		wg       sync.WaitGroup
+		generate bool // 是否为未加密的库生成了数据密钥
+		upgrade  bool // 是否存在需重建为字典序编码的旧版索引
+	)
+	master, err := obtainMasterKey()
+	if nil != err {
+		log.Panic("restart failed, master key read error", log.Err(err))
+	}
+	l.setDatabases(map[string]Database{})
 	for dk, dv := range l.lilyData.Databases {
-		l.databases[dk] = &database{
-			id:      dv.ID,
-			name:    dv.Name,
-			comment: dv.Comment,
-			forms:   map[string]Form{},
-			lily:    l,
+		keys, generated, err := openKeyring(dv, master)
+		if nil != err {
+			log.Panic("restart failed, data key open error", log.Field("database", dv.Name), log.Err(err))
+		}
+		generate = generate || generated
+		upgrade = upgrade || hasLegacyIndex(dv)
+		l.openDatabase(dk, dv, keys, &wg)
+	}
+	wg.Wait()
+	if generate || upgrade {
+		l.storeRPC()
+	}
+	// 索引恢复完成后，重做预写日志中未完成的操作
+	for _, db := range l.getDatabaseMap() {
+		if err := db.recover(); nil != err {
+			log.Panic("restart failed, wal recover error", log.Field("database", db.getName()), log.Err(err))
+		}
+		db.resumeBackfill()
+	}
+}
+
+// openDatabase 依据库对象组装数据库及其全部表并加入库集合，调用方持有 l.lock
+//
+// 默认存储引擎表的索引在 wg 中并行恢复，调用方等待完成后再重做预写日志
+//
+// 无法打开的表，如存储引擎未注册，不加入表集合，仍保留在 lily.sync 中，不影响库中其它表
+func (l *Lily) openDatabase(dk string, dv *api.Database, keys *keyring, wg *sync.WaitGroup) *database {
+	db := &database{
+		id:      dv.ID,
+		name:    dv.Name,
+		comment: dv.Comment,
+		forms:   map[string]Form{},
+		wal:     newWAL(dv.ID, keys),
+		keys:    keys,
+		lily:    l,
+	}
+	db.setSyncMode(dv.SyncMode)
+	l.attachDatabase(dk, db)
+	for fk, fv := range dv.Forms {
+		var formType string
+		switch fv.FormType {
+		default:
+			formType = FormTypeSQL
+		case api.FormType_Doc:
+			formType = FormTypeDoc
+		}
+		engine := fv.Engine
+		if gnomon.StringIsEmpty(engine) {
+			engine = EngineFile
 		}
-		for fk, fv := range dv.Forms {
-			var formType string
-			switch fv.FormType {
-			default:
-				formType = FormTypeSQL
-			case api.FormType_Doc:
-				formType = FormTypeDoc
+		f := &form{
+			id:          fv.ID,
+			name:        fv.Name,
+			autoID:      0,
+			comment:     fv.Comment,
+			formType:    formType,
+			compression: FormatCompression(fv.Compression),
+			engine:      engine,
+			versions:    fv.Versions,
+			database:    db,
+			indexes:     map[string]Index{},
+		}
+		for ik, iv := range fv.Indexes {
+			index := &index{id: iv.ID, primary: iv.Primary, unique: iv.Unique, keyStructure: iv.KeyStructure, form: f}
+			node := &node{level: 1, degreeIndex: 0, preNode: nil, nodes: []Nodal{}, index: index}
+			index.node = node
+			if iv.Building {
+				index.setStatus(&IndexStatus{State: IndexBuilding})
 			}
-			l.databases[dk].getForms()[fk] = &form{
-				id:       fv.ID,
-				name:     fv.Name,
-				autoID:   0,
-				comment:  fv.Comment,
-				formType: formType,
-				database: l.databases[dk],
-				indexes:  map[string]Index{},
+			f.getIndexes()[ik] = index
+		}
+		if err := db.openForm(f); nil != err {
+			log.Error("form open failed, skip it", log.Field("database", dv.Name), log.Field("form", fv.Name), log.Field("engine", engine), log.Err(err))
+			continue
+		}
+		db.attachForm(fk, f)
+		if engine == EngineFile {
+			l.recoverFileForm(wg, db, f, fv)
+		}
+	}
+	return db
+}
+
+// recoverFileForm 恢复默认存储引擎表的数据文件及索引
+//
+// 旧版散列编码的自定义索引依据数据文件重建为字典序编码，并在 fv 中标记
+func (l *Lily) recoverFileForm(wg *sync.WaitGroup, db *database, f *form, fv *api.Form) {
+	// 先完成或丢弃上次未完成的压缩，再恢复索引
+	if err := recoverCompact(db.id, f.id); nil != err {
+		log.Panic("restart failed, compact recover error", log.Field("form", f.name), log.Err(err))
+	}
+	if err := migrateFormDataFile(db.id, f.id); nil != err {
+		log.Panic("restart failed, data file migrate error", log.Field("form", f.name), log.Err(err))
+	}
+	if segments := formSegments(db.id, f.id); len(segments) > 0 {
+		f.setSegment(segments[len(segments)-1])
+	}
+	var records []*scannedRecord
+	// 没有主键索引的表依据数据文件确认各key当前记录
+	if !hasDefaultIndex(f) {
+		var err error
+		if records, err = scanLiveRecords(db.id, f.id, db.keys); nil != err {
+			log.Panic("restart failed, record scan error", log.Field("form", f.name), log.Err(err))
+		}
+		f.getHeads().load(records)
+	}
+	for ik, index := range f.getIndexes() {
+		if iv := fv.Indexes[ik]; legacyIndex(iv) {
+			if nil == records {
+				var err error
+				if records, err = scanLiveRecords(db.id, f.id, db.keys); nil != err {
+					log.Panic("restart failed, legacy index scan error", log.Field("form", f.name), log.Err(err))
+				}
 			}
-			for ik, iv := range fv.Indexes {
-				index := &index{id: iv.ID, primary: iv.Primary, keyStructure: iv.KeyStructure, form: l.databases[dk].getForms()[fk]}
-				node := &node{level: 1, degreeIndex: 0, preNode: nil, nodes: []Nodal{}, index: index}
-				index.node = node
-				l.databases[dk].getForms()[fk].getIndexes()[ik] = index
-				wg.Add(1)
-				go func(l *Lily, dk, fk, ik string) {
-					defer wg.Done()
-					l.databases[dk].getForms()[fk].getIndexes()[ik].recover()
-				}(l, dk, fk, ik)
+			iv.Ordered = true
+			wg.Add(1)
+			go func(index Index, records []*scannedRecord) {
+				defer wg.Done()
+				if _, err := index.rebuild(records); nil != err {
+					log.Panic("restart failed, legacy index rebuild error", log.Field("index", index.getKeyStructure()), log.Err(err))
+				}
+				log.Info("legacy index rebuilt in lexicographic order", log.Field("form", f.name), log.Field("index", index.getKeyStructure()))
+			}(index, records)
+			continue
+		}
+		// 未完成回填的索引文件中可能残留部分记录，清空后在预写日志重做完成后重新回填
+		if iv := fv.Indexes[ik]; nil != iv && iv.Building {
+			if err := os.Remove(pathFormIndexFile(db.id, f.id, ik)); nil != err && !os.IsNotExist(err) {
+				log.Panic("restart failed, backfill index remove error", log.Field("index", index.getKeyStructure()), log.Err(err))
 			}
+			continue
 		}
+		wg.Add(1)
+		go func(index Index) {
+			defer wg.Done()
+			index.recover()
+		}(index)
 	}
-	wg.Wait()
 }
 
 // initialize 初始化默认库及默认表
@@ -208,13 +420,13 @@ func (l *Lily) initialize() {
 			return
 		}
 		log.Info(strings.Join([]string{"lily service have been created ", defaultForm}, ""))
-		l.databases[sysDatabase] = data
+		l.attachDatabase(sysDatabase, data)
 	})
 }
 
 // GetDatabase 获取数据库集合
 func (l *Lily) GetDatabase(name string) Database {
-	for _, db := range l.databases {
+	for _, db := range l.getDatabaseMap() {
 		if name == db.getName() {
 			return db
 		}
@@ -222,10 +434,57 @@ func (l *Lily) GetDatabase(name string) Database {
 	return nil
 }
 
+// getDatabaseMap 获取库集合，库集合只以新集合替换，返回后可直接遍历
+func (l *Lily) getDatabaseMap() map[string]Database {
+	defer l.dLock.RUnlock()
+	l.dLock.RLock()
+	return l.databases
+}
+
+// obtainDatabase 获取库，Lily 为空或库不存在时返回nil
+func (l *Lily) obtainDatabase(name string) Database {
+	if nil == l {
+		return nil
+	}
+	return l.getDatabaseMap()[name]
+}
+
+// setDatabases 以新集合替换库集合
+func (l *Lily) setDatabases(databases map[string]Database) {
+	defer l.dLock.Unlock()
+	l.dLock.Lock()
+	l.databases = databases
+}
+
+// attachDatabase 以加入库后的新集合替换库集合
+func (l *Lily) attachDatabase(name string, db Database) {
+	defer l.dLock.Unlock()
+	l.dLock.Lock()
+	databases := make(map[string]Database, len(l.databases)+1)
+	for k, v := range l.databases {
+		databases[k] = v
+	}
+	databases[name] = db
+	l.databases = databases
+}
+
+// detachDatabase 以移除库后的新集合替换库集合
+func (l *Lily) detachDatabase(name string) {
+	defer l.dLock.Unlock()
+	l.dLock.Lock()
+	databases := make(map[string]Database, len(l.databases))
+	for k, v := range l.databases {
+		if k != name {
+			databases[k] = v
+		}
+	}
+	l.databases = databases
+}
+
 // GetDatabases 获取数据库集合
 func (l *Lily) GetDatabases() []Database {
 	var dbs []Database
-	for _, db := range l.databases {
+	for _, db := range l.getDatabaseMap() {
 		dbs = append(dbs, db)
 	}
 	return dbs
@@ -240,21 +499,37 @@ func (l *Lily) GetDatabases() []Database {
 // comment 数据库描述
 func (l *Lily) CreateDatabase(name, comment string) (Database, error) {
 	// 确定库名不重复
-	for k := range l.databases {
+	for k := range l.getDatabaseMap() {
 		if k == name {
 			return nil, ErrDatabaseExist
 		}
 	}
 	// 确保数据库唯一ID不重复
 	id := l.name2id(name)
-	if err := mkDataDir(id); nil != err {
+	apiDB := &api.Database{ID: id, Name: name, Comment: comment, Forms: map[string]*api.Form{}}
+	// 启用加密时为新库生成数据密钥
+	master, err := obtainMasterKey()
+	if nil != err {
 		return nil, err
 	}
-	l.databases[name] = &database{name: name, id: id, comment: comment, forms: map[string]Form{}, lily: l}
-	// 同步数据到 pb.Lily
-	l.lilyData.Databases[name] = &api.Database{ID: id, Name: name, Comment: comment, Forms: map[string]*api.Form{}}
-	l.syncRPC2Store()
-	return l.databases[name], nil
+	keys, _, err := openKeyring(apiDB, master)
+	if nil != err {
+		return nil, err
+	}
+	if err = mkDataDir(id); nil != err {
+		return nil, err
+	}
+	db := &database{name: name, id: id, comment: comment, forms: map[string]Form{}, wal: newWAL(id, keys), keys: keys, lily: l}
+	// 加入库集合与同步数据到 pb.Lily 同时完成，重命名库时二者一致
+	defer l.lock.Unlock()
+	l.lock.Lock()
+	if nil != l.lilyData.Databases[name] {
+		return nil, ErrDatabaseExist
+	}
+	l.attachDatabase(name, db)
+	l.lilyData.Databases[name] = apiDB
+	l.storeRPC()
+	return db, nil
 }
 
 // CreateForm 创建表
@@ -265,16 +540,50 @@ func (l *Lily) CreateDatabase(name, comment string) (Database, error) {
 //
 // comment 表描述
 func (l *Lily) CreateForm(databaseName, formName, comment, formType string) error {
-	if database := l.databases[databaseName]; nil != database {
+	return l.CreateFormWithOptions(databaseName, formName, comment, formType, nil)
+}
+
+// CreateFormWithOptions 按表选项创建表
+//
+// databaseName 数据库名
+//
+// name 表名称
+//
+// comment 表描述
+//
+// options 表选项，为nil时与 CreateForm 一致
+func (l *Lily) CreateFormWithOptions(databaseName, formName, comment, formType string, options *FormOptions) error {
+	opts := &FormOptions{Compression: CompressionNone, Engine: obtainConf().Engine, Versions: 1}
+	if nil != options && gnomon.StringIsNotEmpty(options.Compression) {
+		opts.Compression = options.Compression
+	}
+	if nil != options && gnomon.StringIsNotEmpty(options.Engine) {
+		opts.Engine = options.Engine
+	}
+	if nil != options && options.Versions > 1 {
+		opts.Versions = options.Versions
+	}
+	switch opts.Compression {
+	default:
+		return ErrCompressionInvalid
+	case CompressionNone, CompressionGzip, CompressionFlate, CompressionSnappy:
+	}
+	if nil == connector.Obtain(opts.Engine) {
+		return ErrEngineInvalid
+	}
+	if opts.Versions > 1 && opts.Engine != EngineFile {
+		return ErrEngineUnsupported
+	}
+	if database := l.obtainDatabase(databaseName); nil != database {
 		switch formType {
 		default:
-			if err := database.createSQL(formName, comment); nil != err {
+			if err := database.createSQL(formName, comment, opts); nil != err {
 				return err
 			}
 			l.syncRPC2Store()
 			return nil
 		case FormTypeDoc:
-			if err := database.createDoc(formName, comment); nil != err {
+			if err := database.createDoc(formName, comment, opts); nil != err {
 				return err
 			}
 			l.syncRPC2Store()
@@ -284,16 +593,18 @@ func (l *Lily) CreateForm(databaseName, formName, comment, formType string) erro
 	return ErrDataIsNil
 }
 
-// CreateKey 新建主键
+// CreateKey 新建唯一索引
+//
+// 写入数据的索引值与其它主键的记录重复时拒绝写入，并返回 *UniqueViolationError
 //
 // databaseName 数据库名
 //
 // name 表名称
 //
-// keyStructure 主键结构名，按照规范结构组成的主键字段名称，由对象结构层级字段通过'.'组成，如'i','in.s'
+// keyStructure 主键结构名，按照规范结构组成的主键字段名称，由对象结构层级字段通过'.'组成，如'i','in.s'，复合唯一索引各字段以','分隔
 func (l *Lily) CreateKey(databaseName, formName string, keyStructure string) error {
-	if database := l.databases[databaseName]; nil != database {
-		if err := database.createIndex(formName, keyStructure); nil != err {
+	if database := l.obtainDatabase(databaseName); nil != database {
+		if err := database.createIndex(formName, keyStructure, true); nil != err {
 			return err
 		}
 		l.syncRPC2Store()
@@ -310,8 +621,8 @@ func (l *Lily) CreateKey(databaseName, formName string, keyStructure string) err
 //
 // keyStructure 索引结构名，按照规范结构组成的索引字段名称，由对象结构层级字段通过'.'组成，如'i','in.s'
 func (l *Lily) CreateIndex(databaseName, formName string, keyStructure string) error {
-	if database := l.databases[databaseName]; nil != database {
-		if err := database.createIndex(formName, keyStructure); nil != err {
+	if database := l.obtainDatabase(databaseName); nil != database {
+		if err := database.createIndex(formName, keyStructure, false); nil != err {
 			return err
 		}
 		l.syncRPC2Store()
@@ -320,6 +631,72 @@ func (l *Lily) CreateIndex(databaseName, formName string, keyStructure string) e
 	return ErrDataIsNil
 }
 
+// CreateCompositeIndex 新建复合索引
+//
+// databaseName 数据库名
+//
+// name 表名称
+//
+// keyStructures 复合索引字段名称集合，至少2个，按顺序组成复合索引key，检索条件覆盖其前缀字段时可采用该索引
+func (l *Lily) CreateCompositeIndex(databaseName, formName string, keyStructures []string) error {
+	keyStructure, err := compositeKeyStructure(keyStructures)
+	if nil != err {
+		return err
+	}
+	return l.CreateIndex(databaseName, formName, keyStructure)
+}
+
+// DropDatabase 删除库
+//
+// 库自 lily.sync 移除后不再接受新的请求，进行中的读写完成后删除库存储目录
+//
+// name 数据库名称
+func (l *Lily) DropDatabase(name string) error {
+	return l.dropDatabase(name)
+}
+
+// DropForm 删除表
+//
+// 表自 lily.sync 移除后不再接受新的请求，进行中的读写完成后删除表存储目录
+//
+// databaseName 数据库名
+//
+// formName 表名
+func (l *Lily) DropForm(databaseName, formName string) error {
+	return l.dropForm(databaseName, formName)
+}
+
+// DropIndex 删除索引，表默认主键及自增ID索引不能被删除
+//
+// databaseName 数据库名
+//
+// formName 表名
+//
+// keyStructure 索引结构名
+func (l *Lily) DropIndex(databaseName, formName, keyStructure string) error {
+	return l.dropIndex(databaseName, formName, keyStructure)
+}
+
+// RenameDatabase 重命名库
+//
+// name 数据库名称
+//
+// newName 新数据库名称
+func (l *Lily) RenameDatabase(name, newName string) error {
+	return l.renameDatabase(name, newName)
+}
+
+// RenameForm 重命名表
+//
+// databaseName 数据库名
+//
+// formName 表名
+//
+// newName 新表名
+func (l *Lily) RenameForm(databaseName, formName, newName string) error {
+	return l.renameForm(databaseName, formName, newName)
+}
+
 // PutD 新增数据
 //
 // 向_default表中新增一条数据，key相同则返回一个Error
@@ -330,10 +707,7 @@ func (l *Lily) CreateIndex(databaseName, formName string, keyStructure string) e
 //
 // 返回 hashKey
 func (l *Lily) PutD(key string, value interface{}) (uint64, error) {
-	if gnomon.StringIsEmpty(key) {
-		return 0, ErrKeyIsNil
-	}
-	return l.databases[sysDatabase].put(defaultForm, key, value, false)
+	return l.PutDWithTTL(key, value, 0)
 }
 
 // SetD 新增数据
@@ -346,10 +720,21 @@ func (l *Lily) PutD(key string, value interface{}) (uint64, error) {
 //
 // 返回 hashKey
 func (l *Lily) SetD(key string, value interface{}) (uint64, error) {
-	if gnomon.StringIsEmpty(key) {
-		return 0, ErrKeyIsNil
-	}
-	return l.databases[sysDatabase].put(defaultForm, key, value, true)
+	return l.SetDWithTTL(key, value, 0)
+}
+
+// PutDWithTTL 新增数据，数据在存活时间后过期
+//
+// ttl 存活时间，0表示永不过期
+func (l *Lily) PutDWithTTL(key string, value interface{}, ttl time.Duration) (uint64, error) {
+	return l.PutWithTTL(sysDatabase, defaultForm, key, value, ttl)
+}
+
+// SetDWithTTL 设置数据，数据在存活时间后过期
+//
+// ttl 存活时间，0表示永不过期
+func (l *Lily) SetDWithTTL(key string, value interface{}, ttl time.Duration) (uint64, error) {
+	return l.SetWithTTL(sysDatabase, defaultForm, key, value, ttl)
 }
 
 // GetD 获取数据
@@ -358,7 +743,7 @@ func (l *Lily) SetD(key string, value interface{}) (uint64, error) {
 //
 // keyStructure 插入数据唯一key
 func (l *Lily) GetD(key string) (interface{}, error) {
-	return l.databases[sysDatabase].get(defaultForm, key)
+	return l.obtainDatabase(sysDatabase).get(defaultForm, key)
 }
 
 // Put 新增数据
@@ -375,13 +760,7 @@ func (l *Lily) GetD(key string) (interface{}, error) {
 //
 // 返回 hashKey
 func (l *Lily) Put(databaseName, formName, key string, value interface{}) (uint64, error) {
-	if gnomon.StringIsEmpty(key) {
-		return 0, ErrKeyIsNil
-	}
-	if nil == l || nil == l.databases[databaseName] {
-		return 0, ErrDataIsNil
-	}
-	return l.databases[databaseName].put(formName, key, value, false)
+	return l.PutWithTTL(databaseName, formName, key, value, 0)
 }
 
 // Set 新增数据
@@ -398,13 +777,39 @@ func (l *Lily) Put(databaseName, formName, key string, value interface{}) (uint6
 //
 // 返回 hashKey
 func (l *Lily) Set(databaseName, formName, key string, value interface{}) (uint64, error) {
+	return l.SetWithTTL(databaseName, formName, key, value, 0)
+}
+
+// PutWithTTL 新增数据，数据在存活时间后过期，过期数据对 Get/Select 不可见，并由后台回收
+//
+// ttl 存活时间，0表示永不过期
+func (l *Lily) PutWithTTL(databaseName, formName, key string, value interface{}, ttl time.Duration) (uint64, error) {
+	return l.put(databaseName, formName, key, value, false, ttl)
+}
+
+// SetWithTTL 设置数据，数据在存活时间后过期，过期数据对 Get/Select 不可见，并由后台回收
+//
+// ttl 存活时间，0表示永不过期
+func (l *Lily) SetWithTTL(databaseName, formName, key string, value interface{}, ttl time.Duration) (uint64, error) {
+	return l.put(databaseName, formName, key, value, true, ttl)
+}
+
+func (l *Lily) put(databaseName, formName, key string, value interface{}, update bool, ttl time.Duration) (uint64, error) {
 	if gnomon.StringIsEmpty(key) {
 		return 0, ErrKeyIsNil
 	}
-	if nil == l || nil == l.databases[databaseName] {
+	if ttl < 0 {
+		return 0, ErrTTLInvalid
+	}
+	db := l.obtainDatabase(databaseName)
+	if nil == db {
 		return 0, ErrDataIsNil
 	}
-	return l.databases[databaseName].put(formName, key, value, true)
+	var expire int64
+	if ttl > 0 {
+		expire = time.Now().Add(ttl).UnixNano()
+	}
+	return db.put(formName, key, value, update, expire)
 }
 
 // Get 获取数据
@@ -417,20 +822,46 @@ func (l *Lily) Set(databaseName, formName, key string, value interface{}) (uint6
 //
 // keyStructure 插入数据唯一key
 func (l *Lily) Get(databaseName, formName, key string) (interface{}, error) {
-	if nil == l || nil == l.databases[databaseName] {
+	db := l.obtainDatabase(databaseName)
+	if nil == db {
 		return 0, ErrDataIsNil
 	}
-	return l.databases[databaseName].get(formName, key)
+	return db.get(formName, key)
+}
+
+// GetVersion 获取数据指定版本
+//
+// 仅能获取表保留版本数以内的版本，该版本为删除操作或已过期时与 Get 返回相同错误
+//
+// version 版本号，每次写入或删除递增
+func (l *Lily) GetVersion(databaseName, formName, key string, version uint64) (interface{}, error) {
+	db := l.obtainDatabase(databaseName)
+	if nil == db {
+		return nil, ErrDataIsNil
+	}
+	return db.getVersion(formName, key, version)
+}
+
+// History 获取数据历史版本，由新至旧，最多返回表保留的版本数
+//
+// 超出保留版本数的旧版本在表压缩时回收
+func (l *Lily) History(databaseName, formName, key string) ([]*RecordVersion, error) {
+	db := l.obtainDatabase(databaseName)
+	if nil == db {
+		return nil, ErrDataIsNil
+	}
+	return db.history(formName, key)
 }
 
 // Remove 删除数据
 //
 // 向指定表中删除一条数据并返回
 func (l *Lily) Remove(databaseName, formName, key string) error {
-	if nil == l || nil == l.databases[databaseName] {
+	db := l.obtainDatabase(databaseName)
+	if nil == db {
 		return ErrDataIsNil
 	}
-	return l.databases[databaseName].remove(formName, key)
+	return db.remove(formName, key)
 }
 
 // Select 获取数据
@@ -441,10 +872,11 @@ func (l *Lily) Remove(databaseName, formName, key string) error {
 //
 // keyStructure 插入数据唯一key
 func (l *Lily) Select(databaseName, formName string, selector *Selector) (int32, interface{}, error) {
-	if nil == l || nil == l.databases[databaseName] {
+	db := l.obtainDatabase(databaseName)
+	if nil == db {
 		return 0, nil, ErrDataIsNil
 	}
-	return l.databases[databaseName].query(formName, selector)
+	return db.query(formName, selector)
 }
 
 // Delete 删除数据
@@ -457,10 +889,222 @@ func (l *Lily) Select(databaseName, formName string, selector *Selector) (int32,
 //
 // selector 条件选择器
 func (l *Lily) Delete(databaseName, formName string, selector *Selector) (int32, error) {
-	if nil == l || nil == l.databases[databaseName] {
+	db := l.obtainDatabase(databaseName)
+	if nil == db {
 		return 0, ErrDataIsNil
 	}
-	return l.databases[databaseName].delete(formName, selector)
+	return db.delete(formName, selector)
+}
+
+// Compact 压缩表数据文件
+//
+// 重写表数据文件，仅保留仍被索引引用的有效记录，并同步重写全部索引文件
+//
+// 压缩期间表可正常读取，写入将等待压缩完成
+//
+// databaseName 数据库名
+//
+// formName 表名
+func (l *Lily) Compact(databaseName, formName string) (*CompactResult, error) {
+	db := l.obtainDatabase(databaseName)
+	if nil == db {
+		return nil, ErrDataIsNil
+	}
+	return db.compact(formName)
+}
+
+// RebuildIndex 重建索引
+//
+// 顺序扫描表数据文件，依据每个key最新且有效的记录重新生成索引文件及内存索引树，用于索引文件丢失或损坏时恢复
+//
+// 重建期间表可正常读取，写入将等待重建完成
+//
+// databaseName 数据库名
+//
+// formName 表名
+//
+// keyStructure 索引结构名，主键及自增ID索引同样适用
+//
+// 返回重建后的索引记录数
+func (l *Lily) RebuildIndex(databaseName, formName, keyStructure string) (int64, error) {
+	db := l.obtainDatabase(databaseName)
+	if nil == db {
+		return 0, ErrDataIsNil
+	}
+	return db.rebuildIndex(formName, keyStructure)
+}
+
+// IndexStatus 获取索引构建状态
+//
+// 新建索引依据表中已有数据在后台回填，回填完成前检索不采用该索引
+//
+// databaseName 数据库名
+//
+// formName 表名
+//
+// keyStructure 索引结构名
+func (l *Lily) IndexStatus(databaseName, formName, keyStructure string) (*IndexStatus, error) {
+	db := l.obtainDatabase(databaseName)
+	if nil == db {
+		return nil, ErrDataIsNil
+	}
+	return db.indexStatus(formName, keyStructure)
+}
+
+// GetFormStats 获取表统计信息，含数据记录数、落盘大小及压缩率
+//
+// databaseName 数据库名
+//
+// formName 表名
+func (l *Lily) GetFormStats(databaseName, formName string) (*FormStats, error) {
+	db := l.obtainDatabase(databaseName)
+	if nil == db {
+		return nil, ErrDataIsNil
+	}
+	return db.formStats(formName)
+}
+
+// RotateKey 轮换数据密钥
+//
+// 生成新版本数据密钥，并以新数据密钥重新加密库中全部表的有效记录，完成后移除旧版本数据密钥
+//
+// 轮换期间表可正常读取，写入将在对应表重新加密期间等待
+//
+// databaseName 数据库名
+func (l *Lily) RotateKey(databaseName string) (*RotateResult, error) {
+	db := l.obtainDatabase(databaseName)
+	if nil == db {
+		return nil, ErrDataIsNil
+	}
+	return db.rotateKey()
+}
+
+// SetSyncMode 设置数据库落盘策略，覆盖配置文件中的 SyncMode
+//
+// databaseName 数据库名
+//
+// syncMode 落盘策略 SyncModeNone/SyncModeInterval/SyncModeAlways，为空时使用配置文件中的 SyncMode
+func (l *Lily) SetSyncMode(databaseName, syncMode string) error {
+	switch syncMode {
+	default:
+		return ErrSyncModeInvalid
+	case "", SyncModeNone, SyncModeInterval, SyncModeAlways:
+	}
+	db := l.obtainDatabase(databaseName)
+	if nil == db {
+		return ErrDataIsNil
+	}
+	// 切换前落盘 interval 落盘策略下尚未落盘的写入
+	l.flush()
+	defer l.lock.Unlock()
+	l.lock.Lock()
+	dv := l.lilyData.Databases[db.getName()]
+	if nil == dv || dv.ID != db.getID() {
+		return ErrDataIsNil
+	}
+	db.setSyncMode(syncMode)
+	dv.SyncMode = syncMode
+	l.storeRPC()
+	return nil
+}
+
+// Backup 备份库
+//
+// 将库或全部库的快照写入 tar.gz 归档文件，包含库表元数据及各表数据分段文件与索引文件
+//
+// 备份期间库可正常读写，各表仅在生成快照的瞬间等待写入
+//
+// databaseName 数据库名，为空时备份全部库
+//
+// path 归档文件路径
+func (l *Lily) Backup(databaseName, path string) (*BackupResult, error) {
+	if nil == l {
+		return nil, ErrDataIsNil
+	}
+	return l.backup(databaseName, path)
+}
+
+// Restore 依据归档文件恢复库
+//
+// 归档中的库将替换同名库，其余库不受影响，恢复时须配置与备份时相同的主密钥
+//
+// path 归档文件路径
+func (l *Lily) Restore(path string) (*BackupResult, error) {
+	if nil == l {
+		return nil, ErrDataIsNil
+	}
+	return l.restore(path)
+}
+
+// Export 导出表数据
+//
+// JSON Lines 格式每行为 {"key":key,"value":value}，CSV 格式首列为 _key，嵌套对象按 '.' 路径展开为列
+//
+// format 导出格式 ExportJSONL/ExportCSV
+//
+// w 导出数据写入对象，返回导出行数
+func (l *Lily) Export(databaseName, formName, format string, w io.Writer) (int64, error) {
+	db := l.obtainDatabase(databaseName)
+	if nil == db {
+		return 0, ErrDataIsNil
+	}
+	return db.export(formName, format, w)
+}
+
+// Import 导入表数据
+//
+// 每行依据key写入表并同步索引，key相同则覆盖，CSV 格式依据单元格内容推断数据类型
+//
+// format 导入格式 ExportJSONL/ExportCSV
+//
+// r 导入数据读取对象
+//
+// reject 无法解析或写入失败的行原样写入该对象，为nil时丢弃
+//
+// progress 每处理1000行及完成时回调，可为nil
+func (l *Lily) Import(databaseName, formName, format string, r io.Reader, reject io.Writer, progress func(*ImportResult)) (*ImportResult, error) {
+	db := l.obtainDatabase(databaseName)
+	if nil == db {
+		return nil, ErrDataIsNil
+	}
+	return db.load(formName, format, r, reject, progress)
+}
+
+// PutBlob 分块存储大数据
+//
+// 大数据按255K分块，每块作为一条数据记录存储，key对应的记录为大数据描述，可通过 Get 获取
+//
+// 全部分块写入后再替换描述记录，key原有大数据的分块随后移除，仅支持默认存储引擎
+//
+// r 大数据读取对象
+func (l *Lily) PutBlob(databaseName, formName, key string, r io.Reader) (*BlobInfo, error) {
+	db := l.obtainDatabase(databaseName)
+	if nil == db {
+		return nil, ErrDataIsNil
+	}
+	return db.putBlob(formName, key, r)
+}
+
+// GetBlob 读取大数据
+//
+// 按顺序将分块写入 w，完成后校验总长度及sha256，分块缺失或校验失败时返回 ErrBlobCorrupt
+//
+// w 大数据写入对象
+func (l *Lily) GetBlob(databaseName, formName, key string, w io.Writer) (*BlobInfo, error) {
+	db := l.obtainDatabase(databaseName)
+	if nil == db {
+		return nil, ErrDataIsNil
+	}
+	return db.getBlob(formName, key, w)
+}
+
+// RemoveBlob 删除大数据描述记录及其全部分块
+func (l *Lily) RemoveBlob(databaseName, formName, key string) error {
+	db := l.obtainDatabase(databaseName)
+	if nil == db {
+		return ErrDataIsNil
+	}
+	return db.removeBlob(formName, key)
 }
 
 // name2id 确保数据库唯一ID不重复
@@ -469,7 +1113,7 @@ func (l *Lily) name2id(name string) string {
 	have := true
 	for have {
 		have = false
-		for _, v := range l.databases {
+		for _, v := range l.getDatabaseMap() {
 			if v.getID() == id {
 				have = true
 				id = gnomon.HashMD516(strings.Join([]string{id, gnomon.StringRandSeq(3)}, ""))
@@ -16,8 +16,17 @@ package lily
 
 import (
 	"encoding/json"
+	"fmt"
+	"github.com/aberic/gnomon"
+	"github.com/aberic/lily/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io/ioutil"
 	"math/rand"
+	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -28,6 +37,23 @@ var (
 	shopperName   = "shop"
 )
 
+// TestMain 测试使用独立的临时工作目录，结束后删除，多次运行互不影响
+func TestMain(m *testing.M) {
+	rootDir, err := ioutil.TempDir("", "lilyDB")
+	if nil != err {
+		panic(err)
+	}
+	onceConf.Do(func() {
+		confInstance = &Conf{RootDir: rootDir}
+		if _, err = confInstance.scanDefault(); nil != err {
+			panic(err)
+		}
+	})
+	code := m.Run()
+	_ = os.RemoveAll(rootDir)
+	os.Exit(code)
+}
+
 func TestLily_Restart(t *testing.T) {
 	l := ObtainLily()
 	l.Restart()
@@ -551,3 +577,1246 @@ func TestQuerySelector6(t *testing.T) {
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func TestLily_Compact(t *testing.T) {
+	var (
+		dbName   = "compact"
+		formName = "record"
+	)
+	l := ObtainLily()
+	l.Start()
+	if _, err := l.CreateDatabase(dbName, "压缩测试"); nil != err {
+		t.Log(err)
+	}
+	if err := l.CreateForm(dbName, formName, "", FormTypeDoc); nil != err {
+		t.Log(err)
+	}
+	for i := 1; i <= 20; i++ {
+		if _, err := l.Set(dbName, formName, strconv.Itoa(i), i); nil != err {
+			t.Fatal(err)
+		}
+	}
+	for i := 1; i <= 10; i++ {
+		if _, err := l.Set(dbName, formName, strconv.Itoa(i), i*100); nil != err {
+			t.Fatal(err)
+		}
+	}
+	for i := 11; i <= 15; i++ {
+		if err := l.Remove(dbName, formName, strconv.Itoa(i)); nil != err {
+			t.Fatal(err)
+		}
+	}
+	result, err := l.Compact(dbName, formName)
+	if nil != err {
+		t.Fatal(err)
+	}
+	t.Log("compact records =", result.Records, "size", result.SizeBefore, "->", result.SizeAfter)
+	if result.SizeAfter >= result.SizeBefore {
+		t.Error("data file should shrink after compact")
+	}
+	for i := 1; i <= 20; i++ {
+		v, err := l.Get(dbName, formName, strconv.Itoa(i))
+		switch {
+		case i <= 10:
+			if nil != err || v.(int64) != int64(i*100) {
+				t.Error("get", i, "=", v, err)
+			}
+		case i <= 15:
+			if nil == err {
+				t.Error("removed key", i, "should not be found")
+			}
+		default:
+			if nil != err || v.(int64) != int64(i) {
+				t.Error("get", i, "=", v, err)
+			}
+		}
+	}
+	if _, err = l.Set(dbName, formName, "21", 21); nil != err {
+		t.Fatal(err)
+	}
+	if v, err := l.Get(dbName, formName, "21"); nil != err {
+		t.Error(err)
+	} else {
+		t.Log("get 21 after compact =", v)
+	}
+}
+
+func TestLily_RebuildIndex(t *testing.T) {
+	var (
+		dbName   = "rebuild"
+		formName = "record"
+	)
+	l := ObtainLily()
+	l.Start()
+	if _, err := l.CreateDatabase(dbName, "重建索引测试"); nil != err {
+		t.Log(err)
+	}
+	if err := l.CreateForm(dbName, formName, "", FormTypeDoc); nil != err {
+		t.Log(err)
+	}
+	if err := l.CreateIndex(dbName, formName, "age"); nil != err {
+		t.Log(err)
+	}
+	for i := 1; i <= 10; i++ {
+		if _, err := l.Set(dbName, formName, strconv.Itoa(i), map[string]interface{}{"age": i}); nil != err {
+			t.Fatal(err)
+		}
+	}
+	if _, err := l.Set(dbName, formName, "1", map[string]interface{}{"age": 100}); nil != err {
+		t.Fatal(err)
+	}
+	if err := l.Remove(dbName, formName, "2"); nil != err {
+		t.Fatal(err)
+	}
+	for _, keyStructure := range []string{indexDefaultID, "age"} {
+		count, err := l.RebuildIndex(dbName, formName, keyStructure)
+		if nil != err {
+			t.Fatal(err)
+		}
+		t.Log("rebuild", keyStructure, "entries =", count)
+		if count != 9 {
+			t.Error("rebuild", keyStructure, "should index 9 records, got", count)
+		}
+	}
+	if v, err := l.Get(dbName, formName, "1"); nil != err {
+		t.Error(err)
+	} else {
+		t.Log("get 1 after rebuild =", v)
+	}
+	if _, err := l.Get(dbName, formName, "2"); nil == err {
+		t.Error("removed key 2 should not be found after rebuild")
+	}
+	if _, err := l.RebuildIndex(dbName, formName, "none"); nil == err {
+		t.Error("rebuild unknown index should fail")
+	}
+}
+
+func TestLily_Segment(t *testing.T) {
+	var (
+		dbName   = "segment"
+		formName = "record"
+		filler   = strings.Repeat("s", 4096)
+	)
+	segmentSize := obtainConf().FormSegmentSize
+	obtainConf().FormSegmentSize = 1
+	defer func() { obtainConf().FormSegmentSize = segmentSize }()
+	l := ObtainLily()
+	l.Start()
+	if _, err := l.CreateDatabase(dbName, "数据分段测试"); nil != err {
+		t.Log(err)
+	}
+	if err := l.CreateForm(dbName, formName, "", FormTypeDoc); nil != err {
+		t.Log(err)
+	}
+	for i := 0; i < 600; i++ {
+		if _, err := l.Set(dbName, formName, strconv.Itoa(i), strconv.Itoa(i)+filler); nil != err {
+			t.Fatal(err)
+		}
+	}
+	fm := l.GetDatabase(dbName).getForms()[formName]
+	t.Log("segments after write =", fm.getSegment()+1)
+	if fm.getSegment() < 2 {
+		t.Fatal("data should roll over to at least 3 segments")
+	}
+	for i := 0; i < 500; i++ {
+		if err := l.Remove(dbName, formName, strconv.Itoa(i)); nil != err {
+			t.Fatal(err)
+		}
+	}
+	result, err := l.Compact(dbName, formName)
+	if nil != err {
+		t.Fatal(err)
+	}
+	t.Log("compact records =", result.Records, "size", result.SizeBefore, "->", result.SizeAfter, "segments =", fm.getSegment()+1)
+	if fm.getSegment() != 0 {
+		t.Error("compacted data should fit in one segment")
+	}
+	restarted := &Lily{lilyData: &api.Lily{Databases: map[string]*api.Database{}}, databases: map[string]Database{}}
+	restarted.Restart()
+	for _, i := range []int{0, 499, 500, 599} {
+		v, err := restarted.Get(dbName, formName, strconv.Itoa(i))
+		if i < 500 {
+			if nil == err {
+				t.Error("removed key", i, "should not be found")
+			}
+			continue
+		}
+		if nil != err || !strings.HasPrefix(v.(string), strconv.Itoa(i)+"s") {
+			t.Error("get", i, "after restart failed", err)
+		}
+	}
+}
+
+func TestLily_Compression(t *testing.T) {
+	var (
+		dbName = "compression"
+		value  = map[string]interface{}{"text": strings.Repeat("lily compression ", 64)}
+	)
+	l := ObtainLily()
+	l.Start()
+	if _, err := l.CreateDatabase(dbName, "压缩测试"); nil != err {
+		t.Log(err)
+	}
+	if err := l.CreateFormWithOptions(dbName, "zip", "", FormTypeDoc, &FormOptions{Compression: "COMPRESSION_ZIP"}); ErrCompressionInvalid != err {
+		t.Error("unknown compression should fail", err)
+	}
+	for _, compression := range []string{CompressionNone, CompressionGzip, CompressionFlate, CompressionSnappy} {
+		formName := strings.ToLower(compression)
+		if err := l.CreateFormWithOptions(dbName, formName, "", FormTypeDoc, &FormOptions{Compression: compression}); nil != err {
+			t.Log(err)
+		}
+		for i := 1; i <= 10; i++ {
+			if _, err := l.Set(dbName, formName, strconv.Itoa(i), value); nil != err {
+				t.Fatal(err)
+			}
+		}
+		if v, err := l.Get(dbName, formName, "5"); nil != err {
+			t.Error(err)
+		} else if v.(map[string]interface{})["text"] != value["text"] {
+			t.Error(compression, "value changed after compression", v)
+		}
+		stats, err := l.GetFormStats(dbName, formName)
+		if nil != err {
+			t.Fatal(err)
+		}
+		t.Log(compression, "records =", stats.Records, "data =", stats.DataSize, "raw =", stats.RawSize, "ratio =", stats.Ratio)
+		if compression == CompressionNone && stats.Ratio != 1 {
+			t.Error("uncompressed form ratio should be 1, got", stats.Ratio)
+		}
+		if compression != CompressionNone && stats.Ratio >= 1 {
+			t.Error(compression, "ratio should be less than 1, got", stats.Ratio)
+		}
+	}
+}
+
+func TestLily_SyncMode(t *testing.T) {
+	dbName := "sync"
+	l := ObtainLily()
+	l.Start()
+	if _, err := l.CreateDatabase(dbName, "落盘策略测试"); nil != err {
+		t.Log(err)
+	}
+	if err := l.CreateForm(dbName, "sync", "", FormTypeDoc); nil != err {
+		t.Log(err)
+	}
+	if err := l.SetSyncMode(dbName, "sometimes"); ErrSyncModeInvalid != err {
+		t.Error("unknown sync mode should fail", err)
+	}
+	for _, syncMode := range []string{SyncModeAlways, SyncModeNone, SyncModeInterval, ""} {
+		if err := l.SetSyncMode(dbName, syncMode); nil != err {
+			t.Fatal(err)
+		}
+		t.Log("sync mode", syncMode, "effective", l.GetDatabase(dbName).getSyncMode())
+		key := "mode_" + syncMode
+		if _, err := l.Set(dbName, "sync", key, map[string]interface{}{"mode": syncMode}); nil != err {
+			t.Fatal(err)
+		}
+		if v, err := l.Get(dbName, "sync", key); nil != err || v.(map[string]interface{})["mode"] != syncMode {
+			t.Error(syncMode, "get failed", v, err)
+		}
+	}
+	if l.GetDatabase(dbName).getSyncMode() != obtainConf().SyncMode {
+		t.Error("empty sync mode should follow conf")
+	}
+	// 切换落盘策略与写入及重命名并发进行
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			if _, err := l.Set(dbName, "sync", "concurrent_"+strconv.Itoa(i), map[string]interface{}{"i": i}); nil != err {
+				t.Error(err)
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			if err := l.SetSyncMode(dbName, []string{SyncModeAlways, SyncModeNone}[i%2]); nil != err {
+				t.Error(err)
+			}
+		}
+	}()
+	wg.Wait()
+	if err := l.SetSyncMode(dbName, ""); nil != err {
+		t.Fatal(err)
+	}
+	if err := l.SetSyncMode("sync_not_exist", SyncModeAlways); ErrDataIsNil != err {
+		t.Error("missing database should fail", err)
+	}
+	l.flush()
+}
+
+func TestLily_TTL(t *testing.T) {
+	l := ObtainLily()
+	l.Start()
+	if _, err := l.SetDWithTTL("session", "lily", -time.Second); ErrTTLInvalid != err {
+		t.Error("negative ttl should fail", err)
+	}
+	if _, err := l.SetDWithTTL("session", map[string]interface{}{"user": "lily"}, 50*time.Millisecond); nil != err {
+		t.Fatal(err)
+	}
+	if _, err := l.SetD("forever", map[string]interface{}{"user": "lily"}); nil != err {
+		t.Fatal(err)
+	}
+	if v, err := l.GetD("session"); nil != err {
+		t.Error("session should not expire yet", err)
+	} else {
+		t.Log("session", v)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if _, err := l.GetD("session"); ErrValueExpired != err {
+		t.Error("session should be expired", err)
+	}
+	_, result, err := l.Select(sysDatabase, defaultForm, &Selector{Conditions: []*condition{{Param: "user", Cond: "eq", Value: "lily"}}})
+	if nil != err {
+		t.Fatal(err)
+	}
+	if is := result.([]interface{}); len(is) != 1 {
+		t.Error("expired session should be hidden from select", is)
+	}
+	l.sweep()
+	if _, err = l.GetD("session"); ErrValueInvalid != err {
+		t.Error("expired session should be reclaimed", err)
+	}
+	if _, err = l.GetD("forever"); nil != err {
+		t.Error("record without ttl should be kept", err)
+	}
+	// 已过期及已回收的记录视为不存在，可再次新增
+	if _, err = l.SetDWithTTL("renew", "old", 50*time.Millisecond); nil != err {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	for _, key := range []string{"renew", "session"} {
+		if _, err = l.PutD(key, "new"); nil != err {
+			t.Error("put on absent record should succeed", key, err)
+		}
+		if v, err := l.GetD(key); nil != err || v != "new" {
+			t.Error("put on absent record should be stored", key, v, err)
+		}
+	}
+	if _, err = l.PutD("forever", "new"); nil == err {
+		t.Error("put on existing record should fail")
+	}
+	// 回收扫描后、标记前重新写入的记录不被标记为无效
+	if _, err = l.SetDWithTTL("rewrite", "old", 50*time.Millisecond); nil != err {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	db := l.GetDatabase(sysDatabase)
+	form := db.getForms()[defaultForm]
+	location, _ := currentLocation(form, "rewrite")
+	if _, err = l.SetD("rewrite", "new"); nil != err {
+		t.Fatal(err)
+	}
+	if expired, err := db.expireData(form, "rewrite", "old", location); nil != err || expired {
+		t.Error("rewritten record should not be expired", expired, err)
+	}
+	if v, err := l.GetD("rewrite"); nil != err || v != "new" {
+		t.Error("rewritten record should be kept", v, err)
+	}
+}
+
+func TestLily_Versions(t *testing.T) {
+	var (
+		dbName   = "versions"
+		formName = "audit"
+	)
+	l := ObtainLily()
+	l.Start()
+	defer func() { _ = l.DropDatabase(dbName) }()
+	if _, err := l.CreateDatabase(dbName, "版本测试"); nil != err {
+		t.Log(err)
+	}
+	if err := l.CreateFormWithOptions(dbName, formName, "", FormTypeDoc, &FormOptions{Versions: 3}); nil != err {
+		t.Log(err)
+	}
+	for i := 1; i <= 4; i++ {
+		if _, err := l.Set(dbName, formName, "balance", int64(i*100)); nil != err {
+			t.Fatal(err)
+		}
+	}
+	if _, err := l.Set(dbName, formName, "other", int64(1)); nil != err {
+		t.Fatal(err)
+	}
+	versions, err := l.History(dbName, formName, "balance")
+	if nil != err {
+		t.Fatal(err)
+	}
+	for _, v := range versions {
+		t.Log("version", v.Version, "value", v.Value, "time", v.Time)
+	}
+	if len(versions) != 3 || versions[0].Version != 4 || versions[2].Version != 2 {
+		t.Error("history should hold the latest 3 versions", len(versions))
+	}
+	if v, err := l.GetVersion(dbName, formName, "balance", 3); nil != err || v.(int64) != 300 {
+		t.Error("version 3 should be 300", v, err)
+	}
+	if _, err = l.GetVersion(dbName, formName, "balance", 1); ErrVersionNotFound != err {
+		t.Error("version 1 is beyond retention", err)
+	}
+	if err = l.Remove(dbName, formName, "balance"); nil != err {
+		t.Fatal(err)
+	}
+	if _, err = l.Set(dbName, formName, "balance", int64(600)); nil != err {
+		t.Fatal(err)
+	}
+	if _, err = l.GetVersion(dbName, formName, "balance", 5); ErrValueInvalid != err {
+		t.Error("version 5 is a delete", err)
+	}
+	result, err := l.Compact(dbName, formName)
+	if nil != err {
+		t.Fatal(err)
+	}
+	t.Log("compact records =", result.Records)
+	if versions, err = l.History(dbName, formName, "balance"); nil != err || len(versions) != 3 || versions[0].Version != 6 || !versions[1].Deleted {
+		t.Error("history should survive compact", versions, err)
+	}
+	if v, err := l.GetVersion(dbName, formName, "balance", 4); nil != err || v.(int64) != 400 {
+		t.Error("version 4 should be 400 after compact", v, err)
+	}
+	if _, err = l.GetVersion(dbName, formName, "balance", 3); ErrVersionNotFound != err {
+		t.Error("version 3 should be reclaimed by compact", err)
+	}
+	restarted := &Lily{lilyData: &api.Lily{Databases: map[string]*api.Database{}}, databases: map[string]Database{}}
+	restarted.Restart()
+	if versions, err = restarted.History(dbName, formName, "balance"); nil != err || len(versions) != 3 {
+		t.Error("history should survive restart", versions, err)
+	}
+	if versions, err = l.History(dbName, formName, "other"); nil != err || len(versions) != 1 {
+		t.Error("other should hold a single version", versions, err)
+	}
+	if err = l.CreateFormWithOptions(dbName, "cache", "", FormTypeDoc, &FormOptions{Engine: EngineMemory, Versions: 2}); ErrEngineUnsupported != err {
+		t.Error("memory engine should not support versions", err)
+	}
+}
+
+func TestLily_OrderedStringIndex(t *testing.T) {
+	var (
+		dbName   = "ordered"
+		formName = "people"
+		names    []string
+	)
+	l := ObtainLily()
+	l.Start()
+	defer func() { _ = l.DropDatabase(dbName) }()
+	if _, err := l.CreateDatabase(dbName, "字符串索引字典序测试"); nil != err {
+		t.Log(err)
+	}
+	if err := l.CreateForm(dbName, formName, "", FormTypeDoc); nil != err {
+		t.Log(err)
+	}
+	for _, keyStructure := range []string{"name", "born"} {
+		if err := l.CreateIndex(dbName, formName, keyStructure); nil != err {
+			t.Log(err)
+		}
+	}
+	// 前8个字节相同的名字及日期落在同一叶子节点，需比较完整字符串
+	for _, i := range rand.Perm(30) {
+		name := "alexander_" + strconv.Itoa(100+i)
+		born := "2020-" + strconv.Itoa(10+i/10) + "-" + strconv.Itoa(10+i%10)
+		names = append(names, name)
+		if _, err := l.Put(dbName, formName, strconv.Itoa(i), map[string]interface{}{"name": name, "born": born}); nil != err {
+			t.Fatal(err)
+		}
+	}
+	query := func(l *Lily, selector *Selector) []string {
+		_, is, err := l.Select(dbName, formName, selector)
+		if nil != err {
+			t.Fatal(err)
+		}
+		var result []string
+		for _, i := range is.([]interface{}) {
+			result = append(result, i.(map[string]interface{})["name"].(string))
+		}
+		return result
+	}
+	check := func(l *Lily) {
+		asc := query(l, &Selector{Sort: &sort{Param: "name", ASC: true}})
+		t.Log("asc =", asc)
+		for i := 1; i < len(asc); i++ {
+			if asc[i-1] >= asc[i] {
+				t.Fatal("names should be in ascending order", asc)
+			}
+		}
+		desc := query(l, &Selector{Sort: &sort{Param: "name", ASC: false}, Skip: 2, Limit: 5})
+		if strings.Join(desc, ",") != "alexander_127,alexander_126,alexander_125,alexander_124,alexander_123" {
+			t.Error("names should be in descending order", desc)
+		}
+		ranged := query(l, &Selector{Conditions: []*condition{
+			{Param: "born", Cond: "gt", Value: "2020-10-17"},
+			{Param: "born", Cond: "lt", Value: "2020-11-12"},
+		}, Sort: &sort{Param: "born", ASC: true}})
+		if strings.Join(ranged, ",") != "alexander_108,alexander_109,alexander_110,alexander_111" {
+			t.Error("born range should compare full strings", ranged)
+		}
+		// 未指定排序时范围检索同样按完整字符串顺序返回
+		if lt := query(l, &Selector{Conditions: []*condition{{Param: "name", Cond: "lt", Value: "alexander_105"}}, Limit: 1}); len(lt) != 1 || lt[0] != "alexander_100" {
+			t.Error("lt with limit should return the smallest name", lt)
+		}
+		if gt := query(l, &Selector{Conditions: []*condition{{Param: "name", Cond: "gt", Value: "alexander_125"}}, Limit: 2}); strings.Join(gt, ",") != "alexander_126,alexander_127" {
+			t.Error("gt with limit should return names in ascending order", gt)
+		}
+		if eq := query(l, &Selector{Conditions: []*condition{{Param: "name", Cond: "eq", Value: "alexander_107"}}}); len(eq) != 1 || eq[0] != "alexander_107" {
+			t.Error("eq should match the full string", eq)
+		}
+		if dif := query(l, &Selector{Conditions: []*condition{{Param: "name", Cond: "dif", Value: "alexander_107"}}}); len(dif) != len(names)-1 {
+			t.Error("dif should exclude only the full string", len(dif))
+		}
+	}
+	check(l)
+	// 旧版散列编码的索引在启动时重建
+	for _, iv := range l.lilyData.Databases[dbName].Forms[formName].Indexes {
+		if iv.KeyStructure != indexDefaultID {
+			iv.Ordered = false
+		}
+	}
+	l.storeRPC()
+	restarted := &Lily{lilyData: &api.Lily{Databases: map[string]*api.Database{}}, databases: map[string]Database{}}
+	restarted.Restart()
+	for _, iv := range restarted.lilyData.Databases[dbName].Forms[formName].Indexes {
+		if legacyIndex(iv) {
+			t.Error("index should be marked ordered after rebuild", iv.KeyStructure)
+		}
+	}
+	check(restarted)
+}
+
+func TestLily_CompositeIndex(t *testing.T) {
+	var (
+		dbName   = "composite"
+		formName = "orders"
+	)
+	l := ObtainLily()
+	l.Start()
+	defer func() { _ = l.DropDatabase(dbName) }()
+	if _, err := l.CreateDatabase(dbName, "复合索引测试"); nil != err {
+		t.Log(err)
+	}
+	if err := l.CreateForm(dbName, formName, "", FormTypeDoc); nil != err {
+		t.Log(err)
+	}
+	for _, keyStructures := range [][]string{{"tenant"}, {"tenant", "tenant"}, {"tenant", ""}, {"tenant", "a,b"}} {
+		if err := l.CreateCompositeIndex(dbName, formName, keyStructures); ErrCompositeInvalid != err {
+			t.Error("composite index should be invalid", keyStructures, err)
+		}
+	}
+	if err := l.CreateIndex(dbName, formName, "tenant,,status"); ErrCompositeInvalid != err {
+		t.Error("composite key structure should be invalid", err)
+	}
+	if err := l.CreateIndex(dbName, formName, "tenant"); nil != err {
+		t.Log(err)
+	}
+	for _, keyStructures := range [][]string{{"tenant", "status", "created"}, {"user.name", "created"}} {
+		if err := l.CreateCompositeIndex(dbName, formName, keyStructures); nil != err {
+			t.Log(err)
+		}
+	}
+	statuses := []string{"open", "closed", "pending"}
+	for i := 0; i < 90; i++ {
+		value := map[string]interface{}{
+			"tenant":  "tenant-" + strconv.Itoa(i%3),
+			"status":  statuses[i/3%3],
+			"created": int64(1000 + i),
+			"user":    map[string]interface{}{"name": "user-" + strconv.Itoa(i%5)},
+		}
+		if _, err := l.Put(dbName, formName, strconv.Itoa(i), value); nil != err {
+			t.Fatal(err)
+		}
+	}
+	query := func(l *Lily, conditions []*condition) (string, []int64) {
+		selector := &Selector{Conditions: conditions, Sort: &sort{Param: "created", ASC: true}, database: l.GetDatabase(dbName), formName: formName}
+		index, _, _, _, err := selector.getIndexCondition()
+		if nil != err || nil == index {
+			t.Fatal("index should be found", err)
+		}
+		_, is, err := l.Select(dbName, formName, selector)
+		if nil != err {
+			t.Fatal(err)
+		}
+		var created []int64
+		for _, i := range is.([]interface{}) {
+			created = append(created, i.(map[string]interface{})["created"].(int64))
+		}
+		return index.getKeyStructure(), created
+	}
+	check := func(l *Lily) {
+		// 覆盖前两个字段
+		index, created := query(l, []*condition{
+			{Param: "tenant", Cond: "eq", Value: "tenant-1"},
+			{Param: "status", Cond: "eq", Value: "closed"},
+		})
+		t.Log(index, created)
+		if index != "tenant,status,created" || len(created) != 10 {
+			t.Error("tenant and status should use the composite index", index, created)
+		}
+		for _, c := range created {
+			if i := c - 1000; i%3 != 1 || statuses[i/3%3] != "closed" {
+				t.Error("unexpected record", c)
+			}
+		}
+		// 覆盖全部字段，最后一个字段为范围条件
+		index, created = query(l, []*condition{
+			{Param: "tenant", Cond: "eq", Value: "tenant-2"},
+			{Param: "status", Cond: "eq", Value: "open"},
+			{Param: "created", Cond: "gt", Value: int64(1020)},
+			{Param: "created", Cond: "lt", Value: int64(1075)},
+		})
+		t.Log(index, created)
+		if index != "tenant,status,created" || fmt.Sprint(created) != "[1029 1038 1047 1056 1065 1074]" {
+			t.Error("range on the last field should use the composite index", index, created)
+		}
+		// 嵌套字段及范围条件
+		index, created = query(l, []*condition{
+			{Param: "user.name", Cond: "eq", Value: "user-3"},
+			{Param: "created", Cond: "lt", Value: int64(1030)},
+		})
+		t.Log(index, created)
+		if index != "user.name,created" || len(created) != 6 || created[0] != 1003 || created[5] != 1028 {
+			t.Error("nested field should use the composite index", index, created)
+		}
+		// 单字段索引覆盖同样多的字段时优先单字段索引
+		if index, created = query(l, []*condition{{Param: "tenant", Cond: "eq", Value: "tenant-0"}}); index != "tenant" || len(created) != 30 {
+			t.Error("single field should use the single field index", index, len(created))
+		}
+	}
+	check(l)
+	restarted := &Lily{lilyData: &api.Lily{Databases: map[string]*api.Database{}}, databases: map[string]Database{}}
+	restarted.Restart()
+	check(restarted)
+}
+
+func TestLily_SecondaryIndex(t *testing.T) {
+	var (
+		dbName   = "secondary"
+		formName = "ticket"
+	)
+	l := ObtainLily()
+	l.Start()
+	defer func() { _ = l.DropDatabase(dbName) }()
+	if _, err := l.CreateDatabase(dbName, "非唯一索引测试"); nil != err {
+		t.Log(err)
+	}
+	if err := l.CreateForm(dbName, formName, "", FormTypeDoc); nil != err {
+		t.Log(err)
+	}
+	if err := l.CreateIndex(dbName, formName, "status"); nil != err {
+		t.Log(err)
+	}
+	// 相同索引值的记录在新增和覆盖时均不冲突
+	for i := 0; i < 20; i++ {
+		status := "open"
+		if i%4 == 0 {
+			status = "closed"
+		}
+		if _, err := l.Put(dbName, formName, strconv.Itoa(i), map[string]interface{}{"status": status, "no": int64(i)}); nil != err {
+			t.Fatal(err)
+		}
+	}
+	query := func(l *Lily, status string) []int64 {
+		_, is, err := l.Select(dbName, formName, &Selector{Conditions: []*condition{{Param: "status", Cond: "eq", Value: status}}})
+		if nil != err {
+			t.Fatal(err)
+		}
+		var nos []int64
+		for _, i := range is.([]interface{}) {
+			nos = append(nos, i.(map[string]interface{})["no"].(int64))
+		}
+		return nos
+	}
+	if open, closed := query(l, "open"), query(l, "closed"); len(open) != 15 || len(closed) != 5 {
+		t.Fatal("every record of the same status should be selected", open, closed)
+	}
+	// 更新索引值及删除后，原索引值下不再返回该记录
+	if _, err := l.Set(dbName, formName, "1", map[string]interface{}{"status": "closed", "no": int64(1)}); nil != err {
+		t.Fatal(err)
+	}
+	if err := l.Remove(dbName, formName, "2"); nil != err {
+		t.Fatal(err)
+	}
+	check := func(l *Lily) {
+		open, closed := query(l, "open"), query(l, "closed")
+		t.Log("open =", open, "closed =", closed)
+		if len(open) != 13 || len(closed) != 6 {
+			t.Error("updated and removed records should leave the open status", open, closed)
+		}
+		for _, no := range open {
+			if no == 1 || no == 2 {
+				t.Error("record", no, "should not be open any more")
+			}
+		}
+		_, is, err := l.Select(dbName, formName, &Selector{Conditions: []*condition{{Param: "status", Cond: "eq", Value: "open"}}, Skip: 10})
+		if nil != err || len(is.([]interface{})) != 3 {
+			t.Error("skip should count current records only", is, err)
+		}
+	}
+	check(l)
+	if _, err := l.Compact(dbName, formName); nil != err {
+		t.Fatal(err)
+	}
+	check(l)
+	restarted := &Lily{lilyData: &api.Lily{Databases: map[string]*api.Database{}}, databases: map[string]Database{}}
+	restarted.Restart()
+	check(restarted)
+	// 仅有自增ID索引的SQL表同样返回全部记录
+	if err := l.CreateForm(dbName, "sql", "", FormTypeSQL); nil != err {
+		t.Log(err)
+	}
+	if err := l.CreateIndex(dbName, "sql", "status"); nil != err {
+		t.Log(err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := l.Put(dbName, "sql", strconv.Itoa(i), map[string]interface{}{"status": "open", "no": int64(i)}); nil != err {
+			t.Fatal(err)
+		}
+	}
+	if _, err := l.Compact(dbName, "sql"); nil != err {
+		t.Fatal(err)
+	}
+	if _, is, err := l.Select(dbName, "sql", &Selector{Conditions: []*condition{{Param: "status", Cond: "eq", Value: "open"}}}); nil != err || len(is.([]interface{})) != 3 {
+		t.Error("records of sql form should be selected by secondary index", is, err)
+	}
+}
+
+func TestLily_SecondaryIndexSQL(t *testing.T) {
+	var (
+		dbName   = "secondary_sql"
+		formName = "rows"
+	)
+	l := ObtainLily()
+	l.Start()
+	defer func() { _ = l.DropDatabase(dbName) }()
+	if _, err := l.CreateDatabase(dbName, "SQL表非唯一索引测试"); nil != err {
+		t.Log(err)
+	}
+	if err := l.CreateForm(dbName, formName, "", FormTypeSQL); nil != err {
+		t.Log(err)
+	}
+	if err := l.CreateIndex(dbName, formName, "status"); nil != err {
+		t.Log(err)
+	}
+	count := func(l *Lily, status int64) int {
+		_, is, err := l.Select(dbName, formName, &Selector{Conditions: []*condition{{Param: "status", Cond: "eq", Value: status}}})
+		if nil != err {
+			t.Fatal(err)
+		}
+		return len(is.([]interface{}))
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := l.Put(dbName, formName, strconv.Itoa(i), map[string]interface{}{"status": int64(1)}); nil != err {
+			t.Fatal(err)
+		}
+	}
+	// 没有主键索引的表中记录更新索引值或删除后，原索引值不再检索到该记录
+	if _, err := l.Set(dbName, formName, "0", map[string]interface{}{"status": int64(2)}); nil != err {
+		t.Fatal(err)
+	}
+	if c, err := l.Delete(dbName, formName, &Selector{Conditions: []*condition{{Param: "status", Cond: "eq", Value: int64(2)}}}); nil != err || c != 1 {
+		t.Fatal("delete status 2 failed", c, err)
+	}
+	if n := count(l, 1); n != 2 {
+		t.Error("updated record should not match its old value", n)
+	}
+	if n := count(l, 2); n != 0 {
+		t.Error("removed record should not match", n)
+	}
+	if _, err := l.Compact(dbName, formName); nil != err {
+		t.Fatal(err)
+	}
+	if n := count(l, 1); n != 2 {
+		t.Error("current records should survive compact", n)
+	}
+	restarted := &Lily{lilyData: &api.Lily{Databases: map[string]*api.Database{}}, databases: map[string]Database{}}
+	restarted.Restart()
+	if n, m := count(restarted, 1), count(restarted, 2); n != 2 || m != 0 {
+		t.Error("current records should be recovered after restart", n, m)
+	}
+}
+
+func TestLily_UniqueIndex(t *testing.T) {
+	var (
+		dbName   = "unique"
+		formName = "account"
+	)
+	l := ObtainLily()
+	l.Start()
+	defer func() { _ = l.DropDatabase(dbName) }()
+	if _, err := l.CreateDatabase(dbName, "唯一索引测试"); nil != err {
+		t.Log(err)
+	}
+	if err := l.CreateForm(dbName, formName, "", FormTypeDoc); nil != err {
+		t.Log(err)
+	}
+	if err := l.CreateKey(dbName, formName, "email"); nil != err {
+		t.Log(err)
+	}
+	if err := l.CreateIndex(dbName, formName, "status"); nil != err {
+		t.Log(err)
+	}
+	account := func(email string) map[string]interface{} {
+		return map[string]interface{}{"email": email, "status": "open"}
+	}
+	violated := func(err error, key string) {
+		violation, ok := err.(*UniqueViolationError)
+		if !ok || violation.Index != "email" || violation.Key != key {
+			t.Fatal("unique index should be violated by", key, err)
+		}
+		if code, err := writeErr(err); code != api.Code_UniqueViolation || status.Code(err) != codes.AlreadyExists {
+			t.Error("violation should be returned as already exists", code, err)
+		}
+	}
+	if _, err := l.Put(dbName, formName, "1", account("a@lily.io")); nil != err {
+		t.Fatal(err)
+	}
+	_, err := l.Put(dbName, formName, "2", account("a@lily.io"))
+	t.Log(err)
+	violated(err, "1")
+	_, err = l.Set(dbName, formName, "2", account("a@lily.io"))
+	violated(err, "1")
+	// 被拒绝的写入不写入数据及任何索引
+	if _, err = l.Get(dbName, formName, "2"); nil == err {
+		t.Error("rejected record should not be written")
+	}
+	if _, is, err := l.Select(dbName, formName, &Selector{Conditions: []*condition{{Param: "status", Cond: "eq", Value: "open"}}}); nil != err || len(is.([]interface{})) != 1 {
+		t.Error("rejected record should not be indexed", is, err)
+	}
+	// 覆盖自身记录不违反约束，更新或删除后原索引值可被其它主键使用
+	if _, err = l.Set(dbName, formName, "1", account("a@lily.io")); nil != err {
+		t.Fatal(err)
+	}
+	if _, err = l.Set(dbName, formName, "1", account("b@lily.io")); nil != err {
+		t.Fatal(err)
+	}
+	if _, err = l.Put(dbName, formName, "2", account("a@lily.io")); nil != err {
+		t.Fatal(err)
+	}
+	if err = l.Remove(dbName, formName, "2"); nil != err {
+		t.Fatal(err)
+	}
+	if _, err = l.Put(dbName, formName, "3", account("a@lily.io")); nil != err {
+		t.Fatal(err)
+	}
+	if _, is, err := l.Select(dbName, formName, &Selector{Conditions: []*condition{{Param: "email", Cond: "eq", Value: "a@lily.io"}}}); nil != err || len(is.([]interface{})) != 1 {
+		t.Error("unique index should return only the current holder", is, err)
+	}
+	restarted := &Lily{lilyData: &api.Lily{Databases: map[string]*api.Database{}}, databases: map[string]Database{}}
+	restarted.Restart()
+	_, err = restarted.Put(dbName, formName, "4", account("b@lily.io"))
+	violated(err, "1")
+	// 重做时违反唯一索引的预写日志操作被丢弃，不影响其它操作的恢复
+	db := restarted.GetDatabase(dbName)
+	form := db.getForms()[formName]
+	if _, err = db.(*database).wal.begin(form.getID(), "5", account("b@lily.io"), true, 0); nil != err {
+		t.Fatal(err)
+	}
+	if err = db.recover(); nil != err {
+		t.Error("rejected redo should be discarded", err)
+	}
+	if _, err = restarted.Get(dbName, formName, "5"); nil == err {
+		t.Error("rejected redo should not be written")
+	}
+}
+
+func TestLily_DropRename(t *testing.T) {
+	var (
+		dbName   = "drop"
+		formName = "order"
+	)
+	l := ObtainLily()
+	l.Start()
+	if _, err := l.CreateDatabase(dbName, "删除及重命名测试"); nil != err {
+		t.Log(err)
+	}
+	for _, name := range []string{formName, "temp"} {
+		if err := l.CreateForm(dbName, name, "", FormTypeDoc); nil != err {
+			t.Log(err)
+		}
+		if err := l.CreateIndex(dbName, name, "status"); nil != err {
+			t.Log(err)
+		}
+		for i := 0; i < 10; i++ {
+			if _, err := l.Put(dbName, name, strconv.Itoa(i), map[string]interface{}{"status": "open"}); nil != err {
+				t.Fatal(err)
+			}
+		}
+	}
+	for _, err := range []error{
+		l.DropDatabase(sysDatabase),
+		l.DropForm(sysDatabase, defaultForm),
+		l.RenameDatabase(sysDatabase, "other"),
+		l.RenameForm(sysDatabase, defaultForm, "other"),
+	} {
+		if ErrSystemProtected != err {
+			t.Error("system database and default form should be protected", err)
+		}
+	}
+	if err := l.DropIndex(dbName, formName, indexDefaultID); ErrIndexPrimary != err {
+		t.Error("primary index should not be dropped", err)
+	}
+	// 删除表期间进行中的写入不应破坏其它表
+	db := l.GetDatabase(dbName)
+	temp := db.getForms()["temp"]
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				_, _ = l.Put(dbName, "temp", strconv.Itoa(i*100+j), map[string]interface{}{"status": "open"})
+			}
+		}(i)
+	}
+	if err := l.DropForm(dbName, "temp"); nil != err {
+		t.Fatal(err)
+	}
+	wg.Wait()
+	if gnomon.FilePathExists(pathFormDir(db.getID(), temp.getID())) {
+		t.Error("dropped form dir should be removed")
+	}
+	if _, err := l.Get(dbName, "temp", "1"); nil == err {
+		t.Error("dropped form should not be readable")
+	}
+	// 删除前已获取表对象、获得表写锁前表被删除的写入被拒绝，不再重新创建表存储目录
+	if _, err := temp.getStorage().Put("late", map[string]interface{}{"status": "open"}, false, 0); ErrFormDropped != err {
+		t.Error("write to dropped form should be rejected", err)
+	}
+	if gnomon.FilePathExists(pathFormDir(db.getID(), temp.getID())) {
+		t.Error("rejected write should not recreate dropped form dir")
+	}
+	var statusID string
+	for id, idx := range db.getForms()[formName].getIndexes() {
+		if idx.getKeyStructure() == "status" {
+			statusID = id
+		}
+	}
+	if err := l.DropIndex(dbName, formName, "status"); nil != err {
+		t.Fatal(err)
+	}
+	if gnomon.FilePathExists(pathFormIndexFile(db.getID(), db.getForms()[formName].getID(), statusID)) {
+		t.Error("dropped index file should be removed")
+	}
+	if err := l.CreateIndex(dbName, formName, "status"); nil != err {
+		t.Error("dropped index should be able to be created again", err)
+	}
+	waitIndex(t, l, dbName, formName, "status")
+	if err := l.RenameForm(dbName, formName, "invoice"); nil != err {
+		t.Fatal(err)
+	}
+	if err := l.RenameDatabase(dbName, "renamed"); nil != err {
+		t.Fatal(err)
+	}
+	if err := l.RenameDatabase("renamed", sysDatabase); ErrSystemProtected != err {
+		t.Error("database should not be renamed to system database", err)
+	}
+	check := func(l *Lily) {
+		if nil != l.GetDatabase(dbName) {
+			t.Error("database should be renamed")
+		}
+		if v, err := l.Get("renamed", "invoice", "3"); nil != err {
+			t.Error("renamed form should be readable", v, err)
+		}
+		if _, err := l.Get("renamed", formName, "3"); nil == err {
+			t.Error("old form name should not be readable")
+		}
+		if nil != l.GetDatabase("renamed").getForms()["temp"] {
+			t.Error("dropped form should not be recovered")
+		}
+	}
+	check(l)
+	restarted := &Lily{lilyData: &api.Lily{Databases: map[string]*api.Database{}}, databases: map[string]Database{}}
+	restarted.Restart()
+	check(restarted)
+	if err := l.DropDatabase("renamed"); nil != err {
+		t.Fatal(err)
+	}
+	if gnomon.FilePathExists(filepath.Join(obtainConf().DataDir, db.getID())) || nil != l.GetDatabase("renamed") {
+		t.Error("dropped database should be removed")
+	}
+}
+
+func TestLily_CreateRenameConcurrent(t *testing.T) {
+	var (
+		names   = []string{"create_a", "create_b"}
+		created []string
+		wg      sync.WaitGroup
+	)
+	l := ObtainLily()
+	l.Start()
+	if _, err := l.CreateDatabase(names[0], "新建与重命名并发测试"); nil != err {
+		t.Log(err)
+	}
+	db := l.GetDatabase(names[0])
+	defer func() { _ = l.DropDatabase(db.getName()) }()
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			_ = l.RenameDatabase(names[i%2], names[(i+1)%2])
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		formName := strconv.Itoa(i)
+		if err := l.CreateForm(db.getName(), formName, "", FormTypeDoc); nil == err {
+			created = append(created, formName)
+		}
+	}
+	close(stop)
+	wg.Wait()
+	// 新建成功的表均以当前库名同步到 pb.Lily
+	l.lock.Lock()
+	dv := l.lilyData.Databases[db.getName()]
+	l.lock.Unlock()
+	t.Log("created =", created)
+	for _, formName := range created {
+		if nil == dv.Forms[formName] || len(dv.Forms[formName].Indexes) != 1 {
+			t.Error("created form should be synced to catalog", formName)
+		}
+	}
+}
+
+// waitIndex 等待索引回填结束并返回构建状态
+func waitIndex(t *testing.T, l *Lily, dbName, formName, keyStructure string) *IndexStatus {
+	for i := 0; i < 500; i++ {
+		status, err := l.IndexStatus(dbName, formName, keyStructure)
+		if nil != err {
+			t.Fatal(err)
+		}
+		if status.State != IndexBuilding {
+			return status
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("index", keyStructure, "should be built in time")
+	return nil
+}
+
+func TestLily_IndexBackfill(t *testing.T) {
+	var (
+		dbName   = "backfill"
+		formName = "member"
+		sqlName  = "visit"
+	)
+	l := ObtainLily()
+	l.Start()
+	if _, err := l.CreateDatabase(dbName, "索引回填测试"); nil != err {
+		t.Log(err)
+	}
+	if err := l.CreateForm(dbName, formName, "", FormTypeDoc); nil != err {
+		t.Log(err)
+	}
+	if err := l.CreateForm(dbName, sqlName, "", FormTypeSQL); nil != err {
+		t.Log(err)
+	}
+	// 新建索引前写入的数据，其中部分缺少索引字段，部分已删除
+	for i := 0; i < 2500; i++ {
+		member := map[string]interface{}{"level": int64(i % 5), "mail": strconv.Itoa(i % 10)}
+		if i%100 == 0 {
+			member = map[string]interface{}{"mail": "none"}
+		}
+		if _, err := l.Put(dbName, formName, strconv.Itoa(i), member); nil != err {
+			t.Fatal(err)
+		}
+		if _, err := l.Put(dbName, sqlName, strconv.Itoa(i), map[string]interface{}{"level": int64(i % 5)}); nil != err {
+			t.Fatal(err)
+		}
+	}
+	if err := l.Remove(dbName, formName, "1"); nil != err {
+		t.Fatal(err)
+	}
+	query := func(l *Lily, formName string, level int64) int {
+		_, is, err := l.Select(dbName, formName, &Selector{Conditions: []*condition{{Param: "level", Cond: "eq", Value: level}}, Limit: 10000})
+		if nil != err {
+			t.Fatal(err)
+		}
+		return len(is.([]interface{}))
+	}
+	for _, name := range []string{formName, sqlName} {
+		if err := l.CreateIndex(dbName, name, "level"); nil != err {
+			t.Fatal(err)
+		}
+		status := waitIndex(t, l, dbName, name, "level")
+		t.Log(name, "status =", status)
+		if status.State != IndexReady || status.Total != 2500 || status.Done != 2500 {
+			t.Error("index should be backfilled from every existing record", status)
+		}
+	}
+	if count := query(l, formName, 1); count != 499 {
+		t.Error("existing records should be selected by the backfilled index", count)
+	}
+	if count := query(l, sqlName, 1); count != 500 {
+		t.Error("existing sql records should be selected by the backfilled index", count)
+	}
+	// 回填完成前检索不采用该索引
+	form := l.GetDatabase(dbName).getForms()[formName]
+	for _, idx := range form.getIndexes() {
+		if idx.getKeyStructure() == "level" {
+			idx.setStatus(&IndexStatus{State: IndexBuilding})
+			s := &Selector{Conditions: []*condition{{Param: "level", Cond: "eq", Value: int64(1)}}, database: l.GetDatabase(dbName), formName: formName}
+			if index, _, _, _, err := s.getIndex(); nil != err || index.getKeyStructure() == "level" {
+				t.Error("building index should not be used by selector", err)
+			}
+			if count := query(l, formName, 1); count != 499 {
+				t.Error("records should be selected without the building index", count)
+			}
+			idx.setStatus(&IndexStatus{State: IndexReady})
+		}
+	}
+	// 已有数据违反唯一约束时回填失败，且不再拒绝写入
+	if err := l.CreateKey(dbName, formName, "mail"); nil != err {
+		t.Fatal(err)
+	}
+	if status := waitIndex(t, l, dbName, formName, "mail"); status.State != IndexFailed || gnomon.StringIsEmpty(status.Err) {
+		t.Error("duplicate records should fail the unique index backfill", status)
+	}
+	if _, err := l.Put(dbName, formName, "mail", map[string]interface{}{"mail": "1"}); nil != err {
+		t.Error("failed unique index should not reject writes", err)
+	}
+	if err := l.DropIndex(dbName, formName, "mail"); nil != err {
+		t.Fatal(err)
+	}
+	// 回填时跳过已被更新的旧记录，旧记录中的索引值不违反唯一约束
+	if _, err := l.Set(dbName, sqlName, "1", map[string]interface{}{"code": "a"}); nil != err {
+		t.Fatal(err)
+	}
+	for _, code := range []string{"a", "b"} {
+		if _, err := l.Set(dbName, sqlName, "0", map[string]interface{}{"code": code}); nil != err {
+			t.Fatal(err)
+		}
+	}
+	if err := l.CreateKey(dbName, sqlName, "code"); nil != err {
+		t.Fatal(err)
+	}
+	if status := waitIndex(t, l, dbName, sqlName, "code"); status.State != IndexReady {
+		t.Error("updated records should not fail the unique index backfill", status)
+	}
+	if _, is, err := l.Select(dbName, sqlName, &Selector{Conditions: []*condition{{Param: "code", Cond: "eq", Value: "a"}}}); nil != err || len(is.([]interface{})) != 1 {
+		t.Error("backfilled unique index should select only the current record", is, err)
+	}
+	// 重启前未完成的回填在重启后重新回填
+	for _, iv := range l.lilyData.Databases[dbName].Forms[formName].Indexes {
+		if iv.KeyStructure == "level" {
+			iv.Building = true
+		}
+	}
+	l.syncRPC2Store()
+	restarted := &Lily{lilyData: &api.Lily{Databases: map[string]*api.Database{}}, databases: map[string]Database{}}
+	restarted.Restart()
+	if status := waitIndex(t, restarted, dbName, formName, "level"); status.State != IndexReady || status.Done != status.Total {
+		t.Error("unfinished backfill should be resumed after restart", status)
+	}
+	if count := query(restarted, formName, 1); count != 499 {
+		t.Error("resumed index should select every existing record", count)
+	}
+	if err := l.DropDatabase(dbName); nil != err {
+		t.Fatal(err)
+	}
+}
+
+func TestLily_MultikeyIndex(t *testing.T) {
+	var (
+		dbName   = "multikey"
+		formName = "article"
+	)
+	l := ObtainLily()
+	l.Start()
+	if _, err := l.CreateDatabase(dbName, "数组字段索引测试"); nil != err {
+		t.Log(err)
+	}
+	if err := l.CreateForm(dbName, formName, "", FormTypeDoc); nil != err {
+		t.Log(err)
+	}
+	if err := l.CreateIndex(dbName, formName, "tags"); nil != err {
+		t.Fatal(err)
+	}
+	if err := l.CreateKey(dbName, formName, "codes"); nil != err {
+		t.Fatal(err)
+	}
+	articles := []map[string]interface{}{
+		{"no": int64(0), "tags": []interface{}{"go", "database-a", "database-b"}, "codes": []interface{}{int64(1), int64(2)}},
+		{"no": int64(1), "tags": []interface{}{"go", "rust", "go"}},
+		{"no": int64(2), "tags": []interface{}{"rust"}},
+		{"no": int64(3), "tags": "go"},
+		{"no": int64(4), "tags": []interface{}{"database-a"}},
+		{"no": int64(5)},
+	}
+	for i, article := range articles {
+		if _, err := l.Put(dbName, formName, strconv.Itoa(i), article); nil != err {
+			t.Fatal(err)
+		}
+	}
+	query := func(l *Lily, cond string, value interface{}) []int64 {
+		_, is, err := l.Select(dbName, formName, &Selector{Conditions: []*condition{{Param: "tags", Cond: cond, Value: value}}})
+		if nil != err {
+			t.Fatal(err)
+		}
+		var nos []int64
+		for _, i := range is.([]interface{}) {
+			nos = append(nos, i.(map[string]interface{})["no"].(int64))
+		}
+		return nos
+	}
+	check := func(l *Lily) {
+		// 数组任一元素满足条件即满足，同一记录仅返回一次
+		if nos := query(l, "eq", "go"); len(nos) != 3 {
+			t.Error("every article tagged go should be selected once", nos)
+		}
+		if nos := query(l, "eq", "database-a"); len(nos) != 2 {
+			t.Error("article with elements sharing the same index prefix should be selected once", nos)
+		}
+		if nos := query(l, "gt", "a"); len(nos) != 5 {
+			t.Error("range query should select every tagged article once", nos)
+		}
+		if nos := query(l, "dif", "go"); len(nos) != 2 {
+			t.Error("dif should select articles not containing go", nos)
+		}
+	}
+	check(l)
+	// 数组中每个元素均不能被其它主键的记录占用
+	_, err := l.Put(dbName, formName, "6", map[string]interface{}{"no": int64(6), "codes": []interface{}{int64(3), int64(2)}})
+	if violation, ok := err.(*UniqueViolationError); !ok || violation.Key != "0" {
+		t.Error("unique array element should be violated", err)
+	}
+	if _, err = l.Put(dbName, formName, "6", map[string]interface{}{"no": int64(6), "codes": []interface{}{int64(3), int64(4)}}); nil != err {
+		t.Error("unique array elements should be accepted", err)
+	}
+	// 移除数组元素后不再经由该元素检索到记录
+	if _, err = l.Set(dbName, formName, "1", map[string]interface{}{"no": int64(1), "tags": []interface{}{"rust"}}); nil != err {
+		t.Fatal(err)
+	}
+	if nos := query(l, "eq", "go"); len(nos) != 2 {
+		t.Error("removed element should not select the article any more", nos)
+	}
+	if _, err = l.Set(dbName, formName, "1", map[string]interface{}{"no": int64(1), "tags": []interface{}{"go", "rust"}}); nil != err {
+		t.Fatal(err)
+	}
+	if _, err = l.RebuildIndex(dbName, formName, "tags"); nil != err {
+		t.Fatal(err)
+	}
+	check(l)
+	// 已有数据的数组字段同样可被回填
+	if err = l.CreateIndex(dbName, formName, "codes"); ErrIndexExist != err {
+		t.Error("codes index should exist", err)
+	}
+	if err = l.DropIndex(dbName, formName, "tags"); nil != err {
+		t.Fatal(err)
+	}
+	if err = l.CreateIndex(dbName, formName, "tags"); nil != err {
+		t.Fatal(err)
+	}
+	if status := waitIndex(t, l, dbName, formName, "tags"); status.State != IndexReady {
+		t.Fatal("tags index should be backfilled", status)
+	}
+	check(l)
+	restarted := &Lily{lilyData: &api.Lily{Databases: map[string]*api.Database{}}, databases: map[string]Database{}}
+	restarted.Restart()
+	check(restarted)
+	if err = l.DropDatabase(dbName); nil != err {
+		t.Fatal(err)
+	}
+}
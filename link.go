@@ -21,13 +21,18 @@ import (
 type link struct {
 	preNode        Nodal // box 所属 node
 	md516Key       string
-	seekStartIndex int64 // 索引最终存储在文件中的起始位置
-	seekStart      int64 // value最终存储在文件中的起始位置
-	seekLast       int   // value最终存储在文件中的持续长度
+	seekStartIndex int64  // 索引最终存储在文件中的起始位置
+	segment        uint32 // value所在数据分段文件序号
+	seekStart      int64  // value最终存储在文件中的起始位置
+	seekLast       int    // value最终存储在文件中的持续长度
 	tLock          sync.RWMutex
 }
 
 func (l *link) setMD5Key(md5Key string) {
+	if l.md516Key == md5Key {
+		return
+	}
+	l.preNode.(Leaf).rekeyLink(l, l.md516Key, md5Key)
 	l.md516Key = md5Key
 }
 
@@ -35,6 +40,10 @@ func (l *link) setSeekStartIndex(seek int64) {
 	l.seekStartIndex = seek
 }
 
+func (l *link) setSegment(segment uint32) {
+	l.segment = segment
+}
+
 func (l *link) setSeekStart(seek int64) {
 	l.seekStart = seek
 }
@@ -55,6 +64,10 @@ func (l *link) getSeekStartIndex() int64 {
 	return l.seekStartIndex
 }
 
+func (l *link) getSegment() uint32 {
+	return l.segment
+}
+
 func (l *link) getSeekStart() int64 {
 	return l.seekStart
 }
@@ -97,7 +110,21 @@ func (l *link) put(key string, hashKey uint64) *indexBack {
 
 func (l *link) get() *readResult {
 	index := l.preNode.getIndex()
-	return store().read(pathFormDataFile(index.getForm().getDatabase().getID(), index.getForm().getID()), l.seekStart, l.seekLast)
+	swap := index.getForm().getSwapLocker()
+	defer swap.rUnLock()
+	swap.rLock()
+	database := index.getForm().getDatabase()
+	return store().read(pathFormDataFile(database.getID(), index.getForm().getID(), l.segment), l.seekStart, l.seekLast, database.getKeyring())
+}
+
+// getRecord 读取链表指向的数据记录，不校验记录是否有效或过期
+func (l *link) getRecord() (*valueData, error) {
+	index := l.preNode.getIndex()
+	swap := index.getForm().getSwapLocker()
+	defer swap.rUnLock()
+	swap.rLock()
+	database := index.getForm().getDatabase()
+	return store().readRecord(pathFormDataFile(database.getID(), index.getForm().getID(), l.segment), l.seekStart, l.seekLast, database.getKeyring())
 }
 
 // getFormIndexFilePath 获取表索引文件路径
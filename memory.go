@@ -0,0 +1,171 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"errors"
+	"github.com/aberic/lily/connector"
+	"github.com/vmihailenco/msgpack"
+	sort2 "sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+func init() {
+	if err := connector.Register(&memoryEngine{}); nil != err {
+		panic(err)
+	}
+}
+
+// memoryEngine 内存存储引擎
+//
+// 数据仅保存在内存中，不创建任何表文件，服务重启后表结构保留而数据丢失，适用于测试及缓存
+type memoryEngine struct{}
+
+func (e *memoryEngine) Name() string {
+	return EngineMemory
+}
+
+func (e *memoryEngine) Open(meta *connector.FormMeta) (connector.Storage, error) {
+	return &memoryStorage{sql: meta.FormType == FormTypeSQL, values: map[string]*memoryValue{}}, nil
+}
+
+// memoryStorage 内存存储引擎的表存储对象
+//
+// 数据以 msgpack 编码保存，读取时解码，与默认存储引擎读取得到的数据类型一致，且调用方修改读取结果不影响已存储数据
+type memoryStorage struct {
+	sql    bool                    // 是否关系型表，关系型表每次存储自增ID
+	autoID uint64                  // 自增ID
+	seq    uint64                  // 写入序号，用于按写入顺序遍历
+	values map[string]*memoryValue // key对应存储数据
+	lock   sync.RWMutex
+}
+
+// memoryValue 内存存储数据
+type memoryValue struct {
+	seq    uint64 // 最后写入序号
+	expire int64  // 过期时间，unix纳秒，0表示永不过期
+	data   []byte // msgpack 编码数据
+}
+
+func (s *memoryStorage) Put(key string, value interface{}, update bool, expire int64) (uint64, error) {
+	data, err := msgpack.Marshal(value)
+	if nil != err {
+		return 0, err
+	}
+	defer s.lock.Unlock()
+	s.lock.Lock()
+	if mv, ok := s.values[key]; ok && !update && !mv.expired(time.Now().UnixNano()) {
+		return 0, errors.New(strings.Join([]string{"data ", key, " already exist"}, ""))
+	}
+	s.seq++
+	s.values[key] = &memoryValue{seq: s.seq, expire: expire, data: data}
+	if s.sql {
+		s.autoID++
+	}
+	return s.autoID, nil
+}
+
+func (s *memoryStorage) Get(key string) (interface{}, error) {
+	s.lock.RLock()
+	mv, ok := s.values[key]
+	s.lock.RUnlock()
+	if !ok {
+		return nil, errors.New(strings.Join([]string{"link key", key, "is nil"}, " "))
+	}
+	if mv.expired(time.Now().UnixNano()) {
+		return nil, ErrValueExpired
+	}
+	return mv.value()
+}
+
+func (s *memoryStorage) Delete(key string) error {
+	defer s.lock.Unlock()
+	s.lock.Lock()
+	mv, ok := s.values[key]
+	if !ok {
+		return errors.New(strings.Join([]string{"link key", key, "is nil"}, " "))
+	}
+	if mv.expired(time.Now().UnixNano()) {
+		return ErrValueExpired
+	}
+	delete(s.values, key)
+	return nil
+}
+
+// Scan 按写入顺序遍历，遍历开始时的数据快照
+func (s *memoryStorage) Scan(fn func(key string, value interface{}) bool) error {
+	now := time.Now().UnixNano()
+	s.lock.RLock()
+	keys := make([]string, 0, len(s.values))
+	mvs := make(map[string]*memoryValue, len(s.values))
+	for key, mv := range s.values {
+		if mv.expired(now) {
+			continue
+		}
+		keys = append(keys, key)
+		mvs[key] = mv
+	}
+	s.lock.RUnlock()
+	sort2.Slice(keys, func(i, j int) bool { return mvs[keys[i]].seq < mvs[keys[j]].seq })
+	for _, key := range keys {
+		value, err := mvs[key].value()
+		if nil != err {
+			return err
+		}
+		if !fn(key, value) {
+			break
+		}
+	}
+	return nil
+}
+
+// Expire 移除已过期的数据
+func (s *memoryStorage) Expire() (int64, error) {
+	var (
+		now   = time.Now().UnixNano()
+		count int64
+	)
+	defer s.lock.Unlock()
+	s.lock.Lock()
+	for key, mv := range s.values {
+		if mv.expired(now) {
+			delete(s.values, key)
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Close 释放表数据
+func (s *memoryStorage) Close() error {
+	defer s.lock.Unlock()
+	s.lock.Lock()
+	s.values = map[string]*memoryValue{}
+	return nil
+}
+
+func (mv *memoryValue) expired(now int64) bool {
+	return mv.expire > 0 && mv.expire <= now
+}
+
+func (mv *memoryValue) value() (interface{}, error) {
+	var value interface{}
+	if err := msgpack.Unmarshal(mv.data, &value); nil != err {
+		return nil, err
+	}
+	return value, nil
+}
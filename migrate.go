@@ -0,0 +1,114 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"github.com/aberic/gnomon"
+	"github.com/aberic/gnomon/log"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// MigrateReport 索引文件格式迁移报告
+type MigrateReport struct {
+	Indexes  int // Indexes 检查的索引文件数
+	Migrated int // Migrated 需要迁移或已迁移为当前格式的索引文件数
+	Entries  int // Entries 迁移的索引记录数
+	Skipped  int // Skipped 残缺或校验失败而未迁移的索引记录数
+}
+
+// Migrate 离线将所有库表的旧版文本索引文件原地转换为当前的二进制格式
+//
+// 须在 lily 停止时执行，check 为 true 时仅统计需要迁移的索引文件，不做修改
+//
+// 残缺或校验失败的索引记录不被迁移，如需依据数据文件找回可在启动后重建索引
+func Migrate(check bool) (*MigrateReport, error) {
+	report := &MigrateReport{}
+	if !gnomon.FilePathExists(obtainConf().LilyBootstrapFilePath) {
+		return report, nil
+	}
+	lily, _, _, err := loadCatalog(obtainConf().LilyBootstrapFilePath)
+	if nil != err {
+		return nil, err
+	}
+	for _, db := range lily.Databases {
+		for _, fm := range db.Forms {
+			// 默认存储引擎以外的表没有索引文件
+			if gnomon.StringIsNotEmpty(fm.Engine) && fm.Engine != EngineFile {
+				continue
+			}
+			for _, idx := range fm.Indexes {
+				indexPath := pathFormIndexFile(db.ID, fm.ID, idx.ID)
+				if !gnomon.FilePathExists(indexPath) {
+					continue
+				}
+				report.Indexes++
+				migrated, entries, skipped, err := migrateIndex(indexPath, check)
+				if nil != err {
+					return nil, err
+				}
+				if migrated {
+					report.Migrated++
+					report.Entries += entries
+					report.Skipped += skipped
+				}
+			}
+		}
+	}
+	return report, nil
+}
+
+// migrateIndex 将单个旧版索引文件转换为当前格式，返回是否需要迁移及迁移和跳过的索引记录数
+func migrateIndex(indexPath string, check bool) (migrated bool, entries, skipped int, err error) {
+	var indexData []byte
+	if indexData, err = ioutil.ReadFile(indexPath); nil != err {
+		return
+	}
+	format, err := detectIndexFormat(indexData)
+	if ErrIndexCorrupt == err {
+		// 文件头残缺的索引文件在启动时截断，无需迁移
+		log.Warn("index header is torn, skip it", log.Field("path", indexPath))
+		return false, 0, 0, nil
+	}
+	if nil != err || format.current() {
+		return
+	}
+	buf := newIndexBuffer()
+	for position := 0; position < len(indexData); position += format.entryLen {
+		if position+format.entryLen > len(indexData) { // 尾部残缺
+			skipped++
+			break
+		}
+		record, err := parseIndexEntry(indexData[position : position+format.entryLen])
+		if nil != err {
+			skipped++
+			continue
+		}
+		buf.Write(indexEntry(record.hashKey, record.md516Key, record.segment, record.seekStart, record.seekLast))
+		entries++
+	}
+	if check {
+		return true, entries, skipped, nil
+	}
+	tmpPath := strings.Join([]string{indexPath, ".migrate"}, "")
+	if err = writeFileSync(tmpPath, buf.Bytes()); nil != err {
+		return
+	}
+	if err = os.Rename(tmpPath, indexPath); nil != err {
+		return
+	}
+	return true, entries, skipped, nil
+}
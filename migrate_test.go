@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"bytes"
+	"github.com/aberic/lily/api"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLily_Migrate(t *testing.T) {
+	var (
+		dbName   = "migrate"
+		formName = "record"
+	)
+	l := ObtainLily()
+	l.Start()
+	defer func() { _ = l.DropDatabase(dbName) }()
+	if _, err := l.CreateDatabase(dbName, "索引格式迁移测试"); nil != err {
+		t.Log(err)
+	}
+	if err := l.CreateForm(dbName, formName, "", FormTypeDoc); nil != err {
+		t.Log(err)
+	}
+	for i := 0; i < 100; i++ {
+		if _, err := l.Put(dbName, formName, strconv.Itoa(i), strconv.Itoa(i)+"v"); nil != err {
+			t.Fatal(err)
+		}
+	}
+	db := l.GetDatabase(dbName)
+	fm := db.getForms()[formName]
+	var indexPath string
+	for id := range fm.getIndexes() {
+		indexPath = pathFormIndexFile(db.getID(), fm.getID(), id)
+	}
+	data, err := ioutil.ReadFile(indexPath)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte(indexFileMagic)) || (len(data)-indexHeaderLen)%indexEntryLen != 0 {
+		t.Fatal("new index file should be binary", len(data))
+	}
+	// 改写为旧版文本索引文件，并追加一条残缺的索引记录
+	var builder strings.Builder
+	for position := indexHeaderLen; position < len(data); position += indexEntryLen {
+		record, err := parseIndexEntry(data[position : position+indexEntryLen])
+		if nil != err {
+			t.Fatal(err)
+		}
+		builder.WriteString(legacyIndexEntry(record.hashKey, record.md516Key, record.seekStart, record.seekLast))
+	}
+	builder.WriteString(strings.Repeat("z", indexTextEntryLen/2))
+	if err = ioutil.WriteFile(indexPath, []byte(builder.String()), 0644); nil != err {
+		t.Fatal(err)
+	}
+	store().invalidate(indexPath)
+	report, err := Migrate(true)
+	if nil != err {
+		t.Fatal(err)
+	}
+	t.Log("check report =", report)
+	if report.Migrated < 1 || report.Entries < 100 || report.Skipped < 1 {
+		t.Error("legacy index file should need to be migrated", report)
+	}
+	if data, _ = ioutil.ReadFile(indexPath); bytes.HasPrefix(data, []byte(indexFileMagic)) {
+		t.Error("check should not modify index file")
+	}
+	if report, err = Migrate(false); nil != err {
+		t.Fatal(err)
+	}
+	t.Log("migrate report =", report)
+	if data, _ = ioutil.ReadFile(indexPath); !bytes.HasPrefix(data, []byte(indexFileMagic)) || len(data) != indexHeaderLen+100*indexEntryLen {
+		t.Error("index file should be migrated to binary", len(data))
+	}
+	if report, err = Migrate(false); nil != err || report.Migrated != 0 {
+		t.Error("migrated index file should be skipped", report, err)
+	}
+	restarted := &Lily{lilyData: &api.Lily{Databases: map[string]*api.Database{}}, databases: map[string]Database{}}
+	restarted.Restart()
+	for _, i := range []int{0, 50, 99} {
+		if v, err := restarted.Get(dbName, formName, strconv.Itoa(i)); nil != err || v != strconv.Itoa(i)+"v" {
+			t.Error("record", i, "should be readable after migrate", v, err)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// customKey 自定义索引key及其hashKey
+type customKey struct {
+	key     string
+	hashKey uint64
+}
+
+// multikeys 数组字段的索引key集合，每个元素各对应一个索引key
+//
+// 不支持索引的元素及重复元素被忽略，字段不是数组时返回nil
+func multikeys(value reflect.Value) []*customKey {
+	if value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return nil
+	}
+	var (
+		customKeys []*customKey
+		exist      = map[string]bool{}
+	)
+	for i := 0; i < value.Len(); i++ {
+		item := value.Index(i)
+		if item.Kind() == reflect.Interface {
+			item = item.Elem()
+		}
+		key, hashKey, valid := valueType2index(&item)
+		if !valid {
+			continue
+		}
+		if item.Kind() == reflect.Bool { // 与 type2index 保持一致
+			key = strconv.FormatBool(item.Bool())
+		}
+		if exist[key] {
+			continue
+		}
+		exist[key] = true
+		customKeys = append(customKeys, &customKey{key: key, hashKey: hashKey})
+	}
+	return customKeys
+}
+
+// arrayField 存储数据中指定字段是否为数组
+func (s *Selector) arrayField(param string, value interface{}) bool {
+	_, isArray := s.getValueFromParams(strings.Split(param, "."), value).([]interface{})
+	return isArray
+}
+
+// conditionArray 数组字段条件判断，任一元素满足条件即满足
+//
+// 不等条件须全部元素均与条件值不等，即数组不包含条件值
+func (s *Selector) conditionArray(cond string, paramType int, paramValue interface{}, items []interface{}) bool {
+	for _, item := range items {
+		match := s.conditionItem(cond, paramType, paramValue, item)
+		if cond == "dif" && !match {
+			return false
+		}
+		if cond != "dif" && match {
+			return true
+		}
+	}
+	return cond == "dif"
+}
+
+// duplicate 记录是否已计入结果集，数组字段索引中同一记录可能经由多个元素被检索到，仅首次计入
+func (s *Selector) duplicate(index Index, key string) bool {
+	if index.isPrimary() {
+		return false
+	}
+	if nil == s.seen {
+		s.seen = map[string]bool{}
+	}
+	if s.seen[key] {
+		return true
+	}
+	s.seen[key] = true
+	return false
+}
@@ -35,6 +35,7 @@ type node struct {
 	preNode     Nodal  // node 所属 trolley
 	nodes       []Nodal
 	links       []Link
+	linkMap     map[string]Link // linkMap 叶子节点下md5后key对应的链表对象
 	pLock       sync.RWMutex
 }
 
@@ -82,8 +83,8 @@ func (n *node) get(key string, hashKey, flexibleKey uint64) *readResult {
 		nextFlexibleKey = flexibleKey - uint64(nextDegree)*distance
 	} else {
 		//gnomon.Log().Debug("box-get", gnomon.Log().Field("key", key))
-		if realIndex, exist := n.existLink(key); exist {
-			return n.links[realIndex].get()
+		if link := n.existLink(key); nil != link && link.getSeekStartIndex() != -1 { // 索引从未成功落盘的链表视为不存在
+			return link.get()
 		}
 		return &readResult{err: errors.New(strings.Join([]string{"link key", key, "is nil"}, " "))}
 	}
@@ -93,6 +94,18 @@ func (n *node) get(key string, hashKey, flexibleKey uint64) *readResult {
 	return &readResult{err: errors.New(strings.Join([]string{"node key", key, "is nil"}, " "))}
 }
 
+func (n *node) getLink(key string, flexibleKey uint64) Link {
+	if n.level < 5 {
+		distance := levelDistance(n.level)
+		nextDegree := uint16(flexibleKey / distance)
+		if realIndex, err := n.existNode(nextDegree); nil == err {
+			return n.nodes[realIndex].getLink(key, flexibleKey-uint64(nextDegree)*distance)
+		}
+		return nil
+	}
+	return n.existLink(key)
+}
+
 func (n *node) existNode(index uint16) (realIndex int, err error) {
 	return binaryMatchData(index, n)
 }
@@ -133,6 +146,7 @@ func (n *node) createLeaf(index uint16) Nodal {
 			index:       n.index,
 			preNode:     n,
 			links:       []Link{},
+			linkMap:     map[string]Link{},
 		}
 		return n.appendNodal(index, leaf)
 	}
@@ -140,24 +154,34 @@ func (n *node) createLeaf(index uint16) Nodal {
 }
 
 func (n *node) createLink(key string) (Link, bool) {
+	md516Key := gnomon.HashMD516(key)
 	defer n.unLock()
 	n.lock()
-	if pos, exist := n.existLink(key); exist {
-		return n.links[pos], true
+	if link, exist := n.linkMap[md516Key]; exist {
+		return link, true
 	}
-	link := &link{preNode: n, seekStartIndex: -1}
+	link := &link{preNode: n, md516Key: md516Key, seekStartIndex: -1}
 	n.links = append(n.links, link)
+	n.linkMap[md516Key] = link
 	return link, false
 }
 
-func (n *node) existLink(key string) (int, bool) {
-	for index, link := range n.links {
-		//gnomon.Log().Debug("existLink", gnomon.Log().Field("link.md516Key", link.getMD516Key()), gnomon.Log().Field("md516", gnomon.CryptoHash().MD516(key)))
-		if strings.EqualFold(link.getMD516Key(), gnomon.HashMD516(key)) {
-			return index, true
-		}
+// existLink 获取key对应的链表对象，不存在时返回nil
+func (n *node) existLink(key string) Link {
+	md516Key := gnomon.HashMD516(key)
+	defer n.rUnLock()
+	n.rLock()
+	return n.linkMap[md516Key]
+}
+
+// rekeyLink 链表md5后key变更时同步更新链表集合
+func (n *node) rekeyLink(ln Link, oldMD5Key, newMD5Key string) {
+	defer n.unLock()
+	n.lock()
+	if n.linkMap[oldMD5Key] == ln {
+		delete(n.linkMap, oldMD5Key)
 	}
-	return 0, false
+	n.linkMap[newMD5Key] = ln
 }
 
 func (n *node) appendNodal(index uint16, nodal Nodal) Nodal {
@@ -189,6 +213,26 @@ func (n *node) getLinks() []Link {
 	return n.links
 }
 
+func (n *node) removeLink(ln Link) {
+	defer n.unLock()
+	n.lock()
+	if n.linkMap[ln.getMD516Key()] == ln {
+		delete(n.linkMap, ln.getMD516Key())
+	}
+	for i, l := range n.links {
+		if l == ln {
+			links := make([]Link, 0, len(n.links)-1)
+			links = append(links, n.links[:i]...)
+			n.links = append(links, n.links[i+1:]...)
+			return
+		}
+	}
+}
+
+func (n *node) getLevel() uint8 {
+	return n.level
+}
+
 func (n *node) getDegreeIndex() uint16 {
 	return n.degreeIndex
 }
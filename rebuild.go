@@ -0,0 +1,164 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"errors"
+	"github.com/aberic/gnomon/log"
+	"io"
+	"os"
+	"strings"
+)
+
+// rebuildIndex 依据表数据文件重建索引
+//
+// 重建期间持有表写锁，写入将等待重建完成
+func (d *database) rebuildIndex(formName, keyStructure string) (int64, error) {
+	form := d.getForms()[formName]
+	if nil == form {
+		return 0, formIsInvalid(formName)
+	}
+	if form.getEngine() != EngineFile {
+		return 0, ErrEngineUnsupported
+	}
+	defer form.unLock()
+	form.lock()
+	if form.isDropped() {
+		return 0, ErrFormDropped
+	}
+	var idx Index
+	for _, index := range form.getIndexes() {
+		if index.getKeyStructure() == keyStructure {
+			idx = index
+			break
+		}
+	}
+	if nil == idx {
+		return 0, errors.New(strings.Join([]string{"index", keyStructure, "not found"}, " "))
+	}
+	records, err := scanLiveRecords(d.id, form.getID(), d.keys)
+	if nil != err {
+		return 0, err
+	}
+	count, err := idx.rebuild(records)
+	if nil != err {
+		return 0, err
+	}
+	log.Info("rebuild index",
+		log.Field("form", formName),
+		log.Field("index", keyStructure),
+		log.Field("records", len(records)),
+		log.Field("entries", count))
+	return count, nil
+}
+
+// scanLiveRecords 按分段顺序扫描表全部数据分段文件，返回每个key最新且有效的记录，按写入顺序排列
+func scanLiveRecords(dataID, formID string, keys *keyring) ([]*scannedRecord, error) {
+	var (
+		latest  = map[string]*scannedRecord{} // key对应最新记录
+		ordered []*scannedRecord
+	)
+	for _, segment := range formSegments(dataID, formID) {
+		records, err := scanSegment(pathFormDataFile(dataID, formID, segment), segment, keys)
+		if nil != err {
+			return nil, err
+		}
+		for _, record := range records {
+			latest[record.vd.K] = record
+		}
+		ordered = append(ordered, records...)
+	}
+	var records []*scannedRecord
+	for _, record := range ordered {
+		if latest[record.vd.K] == record && record.vd.I {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+// scanCurrentRecords 按分段顺序逐条扫描表全部数据分段文件，回调每个key当前且有效的记录，fn 返回 false 时停止扫描
+//
+// 依据表主键索引或 recordHeads 确认记录是否为key当前记录，无需读取全部记录，调用方持有表读锁
+func scanCurrentRecords(form Form, fn func(record *scannedRecord) bool) error {
+	dataID, keys := form.getDatabase().getID(), form.getDatabase().getKeyring()
+	for _, segment := range formSegments(dataID, form.getID()) {
+		next, err := eachSegmentRecord(pathFormDataFile(dataID, form.getID(), segment), segment, keys, func(record *scannedRecord) bool {
+			if !record.vd.I {
+				return true
+			}
+			if location, ok := currentLocation(form, record.vd.K); !ok || location != (recordLocation{segment: record.segment, seekStart: record.seekStart}) {
+				return true
+			}
+			return fn(record)
+		})
+		if nil != err {
+			return err
+		}
+		if !next {
+			return nil
+		}
+	}
+	return nil
+}
+
+// scanSegment 顺序扫描单个数据分段文件
+func scanSegment(dataPath string, segment uint32, keys *keyring) ([]*scannedRecord, error) {
+	var records []*scannedRecord
+	if _, err := eachSegmentRecord(dataPath, segment, keys, func(record *scannedRecord) bool {
+		records = append(records, record)
+		return true
+	}); nil != err {
+		return nil, err
+	}
+	return records, nil
+}
+
+// eachSegmentRecord 顺序扫描单个数据分段文件并逐条回调，fn 返回 false 时停止扫描并返回 false
+//
+// 校验失败的记录将被跳过，尾部残缺的记录视为崩溃时未写完并忽略，缺少数据密钥时返回错误
+func eachSegmentRecord(dataPath string, segment uint32, keys *keyring, fn func(record *scannedRecord) bool) (bool, error) {
+	file, err := os.OpenFile(dataPath, os.O_RDONLY, 0644)
+	if nil != err {
+		if os.IsNotExist(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	defer func() { _ = file.Close() }()
+	scanner := newRecordScanner(file, keys)
+	for {
+		record, err := scanner.next()
+		if io.EOF == err {
+			break
+		}
+		if ErrDataKeyNotFound == err {
+			return false, err
+		}
+		if ErrRecordCorrupt == err && nil != record {
+			log.Warn("record is corrupt, skip", log.Field("path", dataPath), log.Field("seekStart", record.seekStart))
+			continue
+		}
+		if nil != err {
+			log.Warn("data tail is torn, ignore the rest", log.Field("path", dataPath), log.Err(err))
+			break
+		}
+		record.segment = segment
+		if !fn(record) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -16,23 +16,30 @@ package lily
 
 import (
 	"errors"
+	"github.com/aberic/gnomon"
 	"github.com/aberic/gnomon/log"
 	"github.com/aberic/lily/api"
+	"github.com/aberic/lily/connector"
 	"reflect"
+	sort2 "sort"
 	"strings"
 )
 
+// errLinkStale 非主键索引链表指向的记录已不是主键当前记录
+var errLinkStale = errors.New("index link is stale")
+
 // Selector 检索选择器
 //
 // 查询顺序 scope -> match -> conditions -> skip -> sort -> limit
 type Selector struct {
-	Conditions []*condition `json:"conditions"` // Conditions 条件查询
-	Skip       uint32       `json:"skip"`       // Skip 结果集跳过数量
-	Sort       *sort        `json:"sort"`       // Sort 排序方式
-	Limit      uint32       `json:"limit"`      // Limit 结果集顺序数量
-	database   Database     // database 数据库对象
-	formName   string       // formName 表名
-	delete     bool         // 是否删除检索结果
+	Conditions []*condition    `json:"conditions"` // Conditions 条件查询
+	Skip       uint32          `json:"skip"`       // Skip 结果集跳过数量
+	Sort       *sort           `json:"sort"`       // Sort 排序方式
+	Limit      uint32          `json:"limit"`      // Limit 结果集顺序数量
+	database   Database        // database 数据库对象
+	formName   string          // formName 表名
+	delete     bool            // 是否删除检索结果
+	seen       map[string]bool // 已计入结果集的记录主键
 }
 
 // condition 条件查询
@@ -52,7 +59,7 @@ type condition struct {
 	//
 	// key可取'i','in.s'
 	Param string      `json:"param"`
-	Cond  string      `json:"cond"`  // 条件 gt/lt/eq/dif 大于/小于/等于/不等
+	Cond  string      `json:"cond"`  // 条件 gt/lt/eq/dif 大于/小于/等于/不等，数组字段任一元素满足即满足，dif 表示不包含
 	Value interface{} `json:"value"` // 比较对象，支持int、string、float和bool
 }
 
@@ -84,13 +91,22 @@ func (s *Selector) exec() (int32, []interface{}, error) {
 		is        []interface{}
 		err       error
 	)
-	if index, leftQuery, nc, pcs, err = s.getIndex(); nil != err {
-		return 0, nil, err
+	form := s.database.getForms()[s.formName]
+	if nil == form {
+		return 0, nil, formIsInvalid(s.formName)
 	}
-	log.Debug("query", log.Field("index", index.getKeyStructure()))
+	s.seen = nil
 	if s.Limit == 0 {
 		s.Limit = 1000
 	}
+	// 默认存储引擎以外的表没有索引树，遍历表全部数据检索
+	if form.getEngine() != EngineFile {
+		return s.scanQuery(form.getStorage())
+	}
+	if index, leftQuery, nc, pcs, err = s.getIndex(); nil != err {
+		return 0, nil, err
+	}
+	log.Debug("query", log.Field("index", index.getKeyStructure()))
 	if leftQuery {
 		count, is = s.leftQueryIndex(index, nc, pcs)
 	} else {
@@ -99,6 +115,49 @@ func (s *Selector) exec() (int32, []interface{}, error) {
 	return count, is, nil
 }
 
+// scanQuery 遍历表存储对象全部数据检索
+func (s *Selector) scanQuery(storage connector.Storage) (int32, []interface{}, error) {
+	var (
+		count int32
+		skip  = s.Skip
+		keys  []string
+		is    = make([]interface{}, 0)
+		pcs   = make(map[string]*paramCondition)
+	)
+	for _, condition := range s.Conditions {
+		if paramType, paramValue, support := s.formatParam(condition.Value); support {
+			pcs[s.pcMapName(condition)] = &paramCondition{paramType: paramType, paramValue: paramValue}
+		}
+	}
+	err := storage.Scan(func(key string, value interface{}) bool {
+		if !s.conditionNoIndexLeaf(nil, pcs, value) {
+			return true
+		}
+		count++
+		if skip > 0 {
+			skip--
+			return true
+		}
+		keys = append(keys, key)
+		is = append(is, value)
+		return uint32(len(is)) < s.Limit
+	})
+	if nil != err {
+		return 0, nil, err
+	}
+	if s.delete {
+		for _, key := range keys {
+			if err = storage.Delete(key); nil != err {
+				return 0, nil, err
+			}
+		}
+	}
+	if s.Sort == nil {
+		return count, is, nil
+	}
+	return count, s.shellSort(is), nil
+}
+
 // getIndex 根据检索条件获取使用索引对象
 //
 // index 已获取索引对象
@@ -114,12 +173,18 @@ func (s *Selector) getIndex() (index Index, leftQuery bool, nc *nodeCondition, p
 		return idx, leftQuery, nc, pcs, err
 	}
 	for _, idx := range s.database.getForms()[s.formName].getIndexes() { // 如果存在排序查询，则优先排序查询
+		if !idx.isReady() { // 回填完成前的索引缺少已有数据，不采用
+			continue
+		}
 		if s.Sort != nil && s.Sort.Param == idx.getKeyStructure() {
 			return idx, s.Sort.ASC, nc, pcs, nil
 		}
 	}
 	// 取值默认索引来进行查询操作
 	for _, idx := range s.database.getForms()[s.formName].getIndexes() {
+		if !idx.isReady() {
+			continue
+		}
 		log.Debug("getIndex", log.Field("index", index))
 		return idx, true, nc, pcs, nil
 	}
@@ -135,6 +200,9 @@ func (s *Selector) getIndexCondition() (index Index, leftQuery bool, nc *nodeCon
 	leftQuery = true
 	for _, condition := range s.Conditions { // 遍历检索条件
 		for _, idx := range s.database.getForms()[s.formName].getIndexes() {
+			if !idx.isReady() { // 回填完成前的索引缺少已有数据，不采用
+				continue
+			}
 			if condition.Param == idx.getKeyStructure() { // 匹配条件是否存在已有索引
 				if nil != s.Sort && s.Sort.Param == idx.getKeyStructure() { // 如果有，则继续判断该索引是否存在排序需求
 					index = idx
@@ -169,6 +237,10 @@ func (s *Selector) getIndexCondition() (index Index, leftQuery bool, nc *nodeCon
 			nc = ncNow
 		}
 	}
+	// 复合索引覆盖多个字段，或没有可用的单字段索引时，优先采用复合索引
+	if idx, cnc, covered := s.getCompositeCondition(); nil != idx && (covered > 1 || nil == index) {
+		index, leftQuery, nc = idx, true, cnc
+	}
 	return
 }
 
@@ -254,15 +326,27 @@ func (s *Selector) leftQueryLeaf(skip, limit uint32, leaf Leaf, ns *nodeConditio
 		if limit >= s.Limit {
 			return skip, limit, 0, is
 		}
-		for _, link := range leaf.getLinks() {
-			if nil == pcs || len(pcs) == 0 {
-				if skip > 0 {
-					skip--
-					continue
+		links := leaf.getLinks()
+		results := s.sortedLeafResults(leaf, links, ns, pcs)
+		size := len(links)
+		if nil != results {
+			size = len(results)
+		}
+		for i := 0; i < size; i++ {
+			var rs *readResult
+			if nil != results {
+				rs = results[i]
+			} else {
+				// 非主键索引可能存在遗留链表，须读取校验后才能计入跳过数量
+				if (nil == pcs || len(pcs) == 0) && leaf.getIndex().isPrimary() {
+					if skip > 0 {
+						skip--
+						continue
+					}
 				}
+				rs = readLink(links[i])
 			}
-			rs := link.get()
-			if nil == rs.err && s.conditionNoIndexLeaf(ns, pcs, rs.value) {
+			if s.matchResult(ns, pcs, rs) && !s.duplicate(leaf.getIndex(), rs.key) {
 				count++
 				if skip > 0 {
 					skip--
@@ -271,10 +355,12 @@ func (s *Selector) leftQueryLeaf(skip, limit uint32, leaf Leaf, ns *nodeConditio
 				limit++
 				if s.delete {
 					form := leaf.getIndex().getForm()
-					indexes := form.getIndexes() // 获取表索引ID集合
-					_, _ = s.database.insertDataWithIndexInfo(form, rs.key, indexes, rs.value, true, false)
+					_, _ = s.database.insertDataWithIndexInfo(form, rs.key, rs.value, true, false, 0)
 				}
 				is = append(is, rs.value)
+				if limit >= s.Limit {
+					break
+				}
 			}
 		}
 	}
@@ -354,21 +440,31 @@ func (s *Selector) rightQueryLeaf(skip, limit uint32, leaf Leaf, ns *nodeConditi
 		count int32
 		is    = make([]interface{}, 0)
 	)
-	links := leaf.getLinks()
-	lenLink := len(links)
 	if (nil != ns && s.leafConditions(leaf, ns.nss)) || nil == ns { // 满足等于与不等于条件
 		if limit >= s.Limit {
 			return skip, limit, 0, is
 		}
-		for i := lenLink - 1; i >= 0; i-- {
-			if nil == pcs || len(pcs) == 0 {
-				if skip > 0 {
-					skip--
-					continue
+		links := leaf.getLinks()
+		results := s.sortedLeafResults(leaf, links, ns, pcs)
+		size := len(links)
+		if nil != results {
+			size = len(results)
+		}
+		for i := size - 1; i >= 0; i-- {
+			var rs *readResult
+			if nil != results {
+				rs = results[i]
+			} else {
+				// 非主键索引可能存在遗留链表，须读取校验后才能计入跳过数量
+				if (nil == pcs || len(pcs) == 0) && leaf.getIndex().isPrimary() {
+					if skip > 0 {
+						skip--
+						continue
+					}
 				}
+				rs = readLink(links[i])
 			}
-			rs := links[i].get()
-			if nil == rs.err && s.conditionNoIndexLeaf(ns, pcs, rs.value) {
+			if s.matchResult(ns, pcs, rs) && !s.duplicate(leaf.getIndex(), rs.key) {
 				count++
 				if skip > 0 {
 					skip--
@@ -377,16 +473,108 @@ func (s *Selector) rightQueryLeaf(skip, limit uint32, leaf Leaf, ns *nodeConditi
 				limit++
 				if s.delete {
 					form := leaf.getIndex().getForm()
-					indexes := form.getIndexes() // 获取表索引ID集合
-					_, _ = s.database.insertDataWithIndexInfo(form, rs.key, indexes, rs.value, true, false)
+					_, _ = s.database.insertDataWithIndexInfo(form, rs.key, rs.value, true, false, 0)
 				}
 				is = append(is, rs.value)
+				if limit >= s.Limit {
+					break
+				}
 			}
 		}
 	}
 	return skip, limit, count, is
 }
 
+// sortedLeafResults 叶子节点内链表顺序可能与索引顺序不一致时，读取叶子节点全部链表数据，仅保留满足条件的数据并按各自完整索引key升序排列
+//
+// 同一叶子节点内的字符串及复合索引hashKey相同，链表按写入顺序排列，需比较完整索引key确定先后，无需排序时返回nil
+func (s *Selector) sortedLeafResults(leaf Leaf, links []Link, ns *nodeCondition, pcs map[string]*paramCondition) []*readResult {
+	if len(links) < 2 || !s.orderedLeaf(leaf.getIndex()) {
+		return nil
+	}
+	var (
+		results = make([]*readResult, 0)
+		keys    []string
+	)
+	for _, link := range links {
+		rs := readLink(link)
+		if !s.matchResult(ns, pcs, rs) {
+			continue
+		}
+		results = append(results, rs)
+		keys = append(keys, linkIndexKey(leaf.getIndex(), link, rs))
+	}
+	sort2.Stable(&leafResults{results: results, keys: keys})
+	return results
+}
+
+// matchResult 链表数据是否有效且满足检索条件，大数据分块记录不参与检索
+func (s *Selector) matchResult(ns *nodeCondition, pcs map[string]*paramCondition, rs *readResult) bool {
+	return nil == rs.err && !isBlobChunk(rs.key) && s.conditionNoIndexLeaf(ns, pcs, rs.value)
+}
+
+// orderedLeaf 检索是否依赖索引叶子节点内链表的先后顺序
+//
+// 排序字段为该索引、复合索引以及字符串范围条件时，叶子节点内不同索引key的先后决定结果，其余情况叶子节点内索引key相同或无需有序
+func (s *Selector) orderedLeaf(index Index) bool {
+	if index.isPrimary() {
+		return false
+	}
+	if (nil != s.Sort && s.Sort.Param == index.getKeyStructure()) || nil != compositeFields(index.getKeyStructure()) {
+		return true
+	}
+	for _, cond := range s.Conditions {
+		if cond.Param == index.getKeyStructure() && (cond.Cond == "gt" || cond.Cond == "lt") && !exactIndexValue(cond.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+// linkIndexKey 链表对应的完整索引key，数组字段中链表的索引key为链表key所对应的元素
+func linkIndexKey(index Index, link Link, rs *readResult) string {
+	customKeys, err := customIndexKeys(index, rs.value)
+	if nil != err {
+		return ""
+	}
+	for _, ck := range customKeys {
+		if gnomon.HashMD516(postingKey(index, ck.key, rs.key)) == link.getMD516Key() {
+			return ck.key
+		}
+	}
+	return ""
+}
+
+// readLink 读取叶子节点链表指向的数据
+//
+// 非主键索引中记录被更新为其它索引值或被删除后，原索引值下的链表仍指向旧记录，此类链表不再是key当前记录而被跳过
+func readLink(ln Link) *readResult {
+	rs := ln.get()
+	if idx := ln.getNodal().getIndex(); nil == rs.err && !idx.isPrimary() && !currentLink(idx.getForm(), rs.key, ln) {
+		return &readResult{key: rs.key, err: errLinkStale}
+	}
+	return rs
+}
+
+// leafResults 叶子节点链表数据排序对象
+type leafResults struct {
+	results []*readResult
+	keys    []string // keys 链表对应的完整索引key
+}
+
+func (l *leafResults) Len() int {
+	return len(l.results)
+}
+
+func (l *leafResults) Less(i, j int) bool {
+	return l.keys[i] < l.keys[j]
+}
+
+func (l *leafResults) Swap(i, j int) {
+	l.results[i], l.results[j] = l.results[j], l.results[i]
+	l.keys[i], l.keys[j] = l.keys[j], l.keys[i]
+}
+
 // nodeConditions 判断当前条件集合是否满足
 func (s *Selector) nodeConditions(node Nodal, nss []*nodeSelector) bool {
 	for _, ns := range nss {
@@ -409,6 +597,10 @@ func (s *Selector) leafConditions(node Nodal, nss []*nodeSelector) bool {
 
 // conditionNode 判断当前条件是否满足
 func (s *Selector) conditionNode(node Nodal, ns *nodeSelector) bool {
+	if ns != nil && ns.keyRange {
+		min, max := nodalKeyRange(node)
+		return max >= ns.min && min <= ns.max
+	}
 	if ns != nil {
 		for _, cond := range s.Conditions {
 			if cond != ns.cond {
@@ -419,6 +611,9 @@ func (s *Selector) conditionNode(node Nodal, ns *nodeSelector) bool {
 				return s.conditionGT(node, ns)
 			case "lt":
 				return s.conditionLT(node, ns)
+			case "eq":
+				min, max := nodalKeyRange(node)
+				return min <= ns.hashKey && ns.hashKey <= max
 			}
 		}
 	}
@@ -427,6 +622,9 @@ func (s *Selector) conditionNode(node Nodal, ns *nodeSelector) bool {
 
 // conditionLeaf 判断当前条件是否满足
 func (s *Selector) conditionLeaf(node Nodal, ns *nodeSelector) bool {
+	if ns != nil && ns.keyRange {
+		return s.conditionNode(node, ns)
+	}
 	if ns != nil {
 		for _, cond := range s.Conditions {
 			if cond != ns.cond {
@@ -434,15 +632,23 @@ func (s *Selector) conditionLeaf(node Nodal, ns *nodeSelector) bool {
 			}
 			switch cond.Cond {
 			case "eq":
-				return ns.level == 5 && ns.degreeIndex == node.getDegreeIndex()
+				return s.conditionNode(node, ns)
 			case "dif":
-				return ns.level == 5 && ns.degreeIndex != node.getDegreeIndex()
+				// 字符串索引叶子节点内可能存在前8个字节相同的其它字符串
+				min, max := nodalKeyRange(node)
+				return !exactIndexValue(cond.Value) || min != ns.hashKey || max != ns.hashKey
 			}
 		}
 	}
 	return true
 }
 
+// exactIndexValue 索引hashKey是否与条件值一一对应，字符串仅以前8个字节组成hashKey，需比较完整字符串
+func exactIndexValue(value interface{}) bool {
+	_, isString := value.(string)
+	return !isString
+}
+
 // paramCondition 参数条件结构
 type paramCondition struct {
 	paramType  int         // paramType 参数类型
@@ -457,7 +663,8 @@ func (s *Selector) pcMapName(cond *condition) string {
 // conditionNoIndexLeaf 判断当前条件是否满足
 func (s *Selector) conditionNoIndexLeaf(ns *nodeCondition, pcs map[string]*paramCondition, value interface{}) bool {
 	for _, cond := range s.Conditions {
-		if nil != ns && cond.Param == ns.nss[0].cond.Param {
+		// 复合索引仅确定hashKey范围，数组字段经由其它元素被检索到，全部条件均需比较完整值
+		if nil != ns && !ns.nss[0].keyRange && cond.Param == ns.nss[0].cond.Param && exactIndexValue(cond.Value) && !s.arrayField(cond.Param, value) {
 			continue
 		}
 		pc := pcs[s.pcMapName(cond)]
@@ -471,24 +678,20 @@ func (s *Selector) conditionNoIndexLeaf(ns *nodeCondition, pcs map[string]*param
 	return true
 }
 
-// conditionGT 条件大于判断
+// conditionGT 条件大于判断，节点内存在大于条件hashKey的key即满足
+//
+// 字符串条件hashKey相同的节点同样满足，由完整字符串比较确定结果
 func (s *Selector) conditionGT(node Nodal, ns *nodeSelector) bool {
-	switch ns.level {
-	default:
-		return ns.degreeIndex < node.getDegreeIndex()
-	case 1, 2, 3, 4:
-		return ns.degreeIndex <= node.getDegreeIndex()
-	}
+	_, max := nodalKeyRange(node)
+	return max > ns.hashKey || (max == ns.hashKey && !exactIndexValue(ns.cond.Value))
 }
 
-// conditionLT 条件小于判断
+// conditionLT 条件小于判断，节点内存在小于条件hashKey的key即满足
+//
+// 字符串条件hashKey相同的节点同样满足，由完整字符串比较确定结果
 func (s *Selector) conditionLT(node Nodal, ns *nodeSelector) bool {
-	switch ns.level {
-	default:
-		return ns.degreeIndex > node.getDegreeIndex()
-	case 1, 2, 3, 4:
-		return ns.degreeIndex >= node.getDegreeIndex()
-	}
+	min, _ := nodalKeyRange(node)
+	return min < ns.hashKey || (min == ns.hashKey && !exactIndexValue(ns.cond.Value))
 }
 
 const (
@@ -527,6 +730,14 @@ func (s *Selector) conditionValue(cond string, params []string, paramType int, p
 	if value = s.getValueFromParams(params, objValue); nil == value {
 		return false
 	}
+	if items, isArray := value.([]interface{}); isArray {
+		return s.conditionArray(cond, paramType, paramValue, items)
+	}
+	return s.conditionItem(cond, paramType, paramValue, value)
+}
+
+// conditionItem 判断单个字段值是否满足当前条件
+func (s *Selector) conditionItem(cond string, paramType int, paramValue, value interface{}) bool {
 	switch value := value.(type) {
 	default:
 		return false
@@ -653,9 +864,9 @@ func (s *Selector) shellAsc(is []interface{}) []interface{} {
 	for gap > 0 {
 		for i := gap; i < length; i++ {
 			tempI := is[i]
-			temp := s.hashKeyFromValue(params, is[i])
+			temp := s.getValueFromParams(params, is[i])
 			preIndex := i - gap
-			for preIndex >= 0 && s.hashKeyFromValue(params, is[preIndex]) > temp {
+			for preIndex >= 0 && compareIndexValue(s.getValueFromParams(params, is[preIndex]), temp) > 0 {
 				is[preIndex+gap] = is[preIndex]
 				preIndex -= gap
 			}
@@ -674,9 +885,9 @@ func (s *Selector) shellDesc(is []interface{}) []interface{} {
 	for gap > 0 {
 		for i := gap; i < length; i++ {
 			tempI := is[i]
-			temp := s.hashKeyFromValue(params, is[i])
+			temp := s.getValueFromParams(params, is[i])
 			preIndex := i - gap
-			for preIndex >= 0 && s.hashKeyFromValue(params, is[preIndex]) < temp {
+			for preIndex >= 0 && compareIndexValue(s.getValueFromParams(params, is[preIndex]), temp) < 0 {
 				is[preIndex+gap] = is[preIndex]
 				preIndex -= gap
 			}
@@ -687,37 +898,6 @@ func (s *Selector) shellDesc(is []interface{}) []interface{} {
 	return is
 }
 
-// hashKeyFromValue 通过Param获取该参数所属hashKey
-func (s *Selector) hashKeyFromValue(params []string, value interface{}) uint64 {
-	hashKey, support := s.getInterValue(params, value)
-	if !support {
-		return 0
-	}
-	return hashKey
-}
-
-// getInterValue 根据索引描述和当前检索到的value对象获取当前value对象所在索引的hashKey
-func (s *Selector) getInterValue(params []string, value interface{}) (hashKey uint64, support bool) {
-	reflectObj := reflect.ValueOf(value) // 反射对象，通过reflectObj获取存储在里面的值，还可以去改变值
-	if reflectObj.Kind() == reflect.Map {
-		interMap := value.(map[string]interface{})
-		lenParams := len(params)
-		var valueResult interface{}
-		for i, param := range params {
-			if i == lenParams-1 {
-				valueResult = interMap[param]
-				break
-			}
-			interMap = interMap[param].(map[string]interface{})
-		}
-		//log.Debug("getInterValue", log.Field("valueResult", valueResult))
-		checkValue := reflect.ValueOf(valueResult)
-		return value2hashKey(&checkValue)
-	}
-	log.Debug("getInterValue", log.Field("kind", reflectObj.Kind()), log.Field("support", false))
-	return 0, false
-}
-
 // getValueFromParams 根据索引描述获取当前value
 func (s *Selector) getValueFromParams(params []string, value interface{}) interface{} {
 	reflectObj := reflect.ValueOf(value) // 反射对象，通过reflectObj获取存储在里面的值，还可以去改变值
@@ -751,14 +931,14 @@ func (s *Selector) getConditionNode(nc *nodeCondition, cond *condition) {
 		return
 	}
 
-	nodeLevel1 := &nodeSelector{level: 1, degreeIndex: 0, cond: cond}
+	nodeLevel1 := &nodeSelector{level: 1, degreeIndex: 0, hashKey: hashKey, cond: cond}
 	nc.nss = append(nc.nss, nodeLevel1)
 	flexibleKey = hashKey
 	distance = levelDistance(nodeLevel1.level)
 	nextDegree = uint16(flexibleKey / distance)
 	nextFlexibleKey = flexibleKey - uint64(nextDegree)*distance
 
-	nodeLevel2 := &nodeSelector{level: 2, degreeIndex: nextDegree, cond: cond}
+	nodeLevel2 := &nodeSelector{level: 2, degreeIndex: nextDegree, hashKey: hashKey, cond: cond}
 	nodeLevel1.nextNode = nodeLevel2
 	if nil == nc.nextNode {
 		nc.nextNode = &nodeCondition{nss: []*nodeSelector{}}
@@ -769,7 +949,7 @@ func (s *Selector) getConditionNode(nc *nodeCondition, cond *condition) {
 	nextDegree = uint16(flexibleKey / distance)
 	nextFlexibleKey = flexibleKey - uint64(nextDegree)*distance
 
-	nodeLevel3 := &nodeSelector{level: 3, degreeIndex: nextDegree, cond: cond}
+	nodeLevel3 := &nodeSelector{level: 3, degreeIndex: nextDegree, hashKey: hashKey, cond: cond}
 	nodeLevel2.nextNode = nodeLevel3
 	if nil == nc.nextNode.nextNode {
 		nc.nextNode.nextNode = &nodeCondition{nss: []*nodeSelector{}}
@@ -780,7 +960,7 @@ func (s *Selector) getConditionNode(nc *nodeCondition, cond *condition) {
 	nextDegree = uint16(flexibleKey / distance)
 	nextFlexibleKey = flexibleKey - uint64(nextDegree)*distance
 
-	nodeLevel4 := &nodeSelector{level: 4, degreeIndex: nextDegree, cond: cond}
+	nodeLevel4 := &nodeSelector{level: 4, degreeIndex: nextDegree, hashKey: hashKey, cond: cond}
 	nodeLevel3.nextNode = nodeLevel4
 	if nil == nc.nextNode.nextNode.nextNode {
 		nc.nextNode.nextNode.nextNode = &nodeCondition{nss: []*nodeSelector{}}
@@ -790,7 +970,7 @@ func (s *Selector) getConditionNode(nc *nodeCondition, cond *condition) {
 	distance = levelDistance(nodeLevel4.level)
 	nextDegree = uint16(flexibleKey / distance)
 
-	nodeLevel5 := &nodeSelector{level: 5, degreeIndex: nextDegree, cond: cond}
+	nodeLevel5 := &nodeSelector{level: 5, degreeIndex: nextDegree, hashKey: hashKey, cond: cond}
 	nodeLevel4.nextNode = nodeLevel5
 	nodeLevel3.nextNode = nodeLevel4
 	if nil == nc.nextNode.nextNode.nextNode.nextNode {
@@ -809,8 +989,12 @@ type nodeCondition struct {
 type nodeSelector struct {
 	level       uint8  // 当前节点所在树层级
 	degreeIndex uint16 // 当前节点所在集合中的索引下标，该坐标不一定在数组中的正确位置，但一定是逻辑正确的
+	hashKey     uint64 // 条件值对应的索引hashKey
 	nextNode    *nodeSelector
 	cond        *condition
+	keyRange    bool   // keyRange 是否为复合索引hashKey范围，此时不对应单个条件
+	min         uint64 // min 复合索引hashKey范围最小值
+	max         uint64 // max 复合索引hashKey范围最大值
 }
 
 func (s *Selector) formatAPI(apiSelector *api.Selector) error {
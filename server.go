@@ -15,11 +15,17 @@
 package lily
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
+	"github.com/aberic/gnomon/log"
 	"github.com/aberic/lily/api"
 	"github.com/vmihailenco/msgpack"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gopkg.in/yaml.v3"
+	"io"
+	"time"
 )
 
 // APIServer APIServer
@@ -52,23 +58,25 @@ func (l *APIServer) CreateDatabase(ctx context.Context, req *api.ReqCreateDataba
 		return &api.RespDatabase{Code: api.Code_Fail, ErrMsg: err.Error()}, err
 	}
 	apiDB := &api.Database{
-		ID:      db.getID(),
-		Name:    db.getName(),
-		Comment: db.getComment(),
-		Forms:   l.formatForms(db),
+		ID:       db.getID(),
+		Name:     db.getName(),
+		Comment:  db.getComment(),
+		SyncMode: db.getSyncMode(),
+		Forms:    l.formatForms(db),
 	}
 	return &api.RespDatabase{Code: api.Code_Success, Database: apiDB}, nil
 }
 
 // CreateForm 创建表
 func (l *APIServer) CreateForm(ctx context.Context, req *api.ReqCreateForm) (*api.Resp, error) {
-	if err := ObtainLily().CreateForm(req.DatabaseName, req.Name, req.Comment, FormatFormType(req.FormType)); nil != err {
+	options := &FormOptions{Compression: FormatCompression(req.Compression), Engine: req.Engine, Versions: req.Versions}
+	if err := ObtainLily().CreateFormWithOptions(req.DatabaseName, req.Name, req.Comment, FormatFormType(req.FormType), options); nil != err {
 		return &api.Resp{Code: api.Code_Fail, ErrMsg: err.Error()}, err
 	}
 	return &api.Resp{Code: api.Code_Success}, nil
 }
 
-// CreateKey 新建主键
+// CreateKey 新建唯一索引
 func (l *APIServer) CreateKey(ctx context.Context, req *api.ReqCreateKey) (*api.Resp, error) {
 	if err := ObtainLily().CreateKey(req.DatabaseName, req.FormName, req.KeyStructure); nil != err {
 		return &api.Resp{Code: api.Code_Fail, ErrMsg: err.Error()}, err
@@ -76,9 +84,55 @@ func (l *APIServer) CreateKey(ctx context.Context, req *api.ReqCreateKey) (*api.
 	return &api.Resp{Code: api.Code_Success}, nil
 }
 
-// CreateIndex 新建索引
+// CreateIndex 新建索引，KeyStructures 不为空时新建复合索引
 func (l *APIServer) CreateIndex(ctx context.Context, req *api.ReqCreateIndex) (*api.Resp, error) {
-	if err := ObtainLily().CreateIndex(req.DatabaseName, req.FormName, req.KeyStructure); nil != err {
+	var err error
+	if len(req.KeyStructures) > 0 {
+		err = ObtainLily().CreateCompositeIndex(req.DatabaseName, req.FormName, req.KeyStructures)
+	} else {
+		err = ObtainLily().CreateIndex(req.DatabaseName, req.FormName, req.KeyStructure)
+	}
+	if nil != err {
+		return &api.Resp{Code: api.Code_Fail, ErrMsg: err.Error()}, err
+	}
+	return &api.Resp{Code: api.Code_Success}, nil
+}
+
+// DropDatabase 删除库
+func (l *APIServer) DropDatabase(ctx context.Context, req *api.ReqDropDatabase) (*api.Resp, error) {
+	if err := ObtainLily().DropDatabase(req.Name); nil != err {
+		return &api.Resp{Code: api.Code_Fail, ErrMsg: err.Error()}, err
+	}
+	return &api.Resp{Code: api.Code_Success}, nil
+}
+
+// DropForm 删除表
+func (l *APIServer) DropForm(ctx context.Context, req *api.ReqDropForm) (*api.Resp, error) {
+	if err := ObtainLily().DropForm(req.DatabaseName, req.FormName); nil != err {
+		return &api.Resp{Code: api.Code_Fail, ErrMsg: err.Error()}, err
+	}
+	return &api.Resp{Code: api.Code_Success}, nil
+}
+
+// DropIndex 删除索引
+func (l *APIServer) DropIndex(ctx context.Context, req *api.ReqDropIndex) (*api.Resp, error) {
+	if err := ObtainLily().DropIndex(req.DatabaseName, req.FormName, req.KeyStructure); nil != err {
+		return &api.Resp{Code: api.Code_Fail, ErrMsg: err.Error()}, err
+	}
+	return &api.Resp{Code: api.Code_Success}, nil
+}
+
+// RenameDatabase 重命名库
+func (l *APIServer) RenameDatabase(ctx context.Context, req *api.ReqRenameDatabase) (*api.Resp, error) {
+	if err := ObtainLily().RenameDatabase(req.Name, req.NewName); nil != err {
+		return &api.Resp{Code: api.Code_Fail, ErrMsg: err.Error()}, err
+	}
+	return &api.Resp{Code: api.Code_Success}, nil
+}
+
+// RenameForm 重命名表
+func (l *APIServer) RenameForm(ctx context.Context, req *api.ReqRenameForm) (*api.Resp, error) {
+	if err := ObtainLily().RenameForm(req.DatabaseName, req.FormName, req.NewName); nil != err {
 		return &api.Resp{Code: api.Code_Fail, ErrMsg: err.Error()}, err
 	}
 	return &api.Resp{Code: api.Code_Success}, nil
@@ -99,8 +153,9 @@ func (l *APIServer) PutD(ctx context.Context, req *api.ReqPutD) (*api.RespPutD,
 	}
 	v = string(req.Value)
 PUT:
-	if hashKey, err = ObtainLily().PutD(req.Key, v); nil != err {
-		return &api.RespPutD{Code: api.Code_Fail, ErrMsg: err.Error()}, err
+	if hashKey, err = ObtainLily().PutDWithTTL(req.Key, v, time.Duration(req.TTL)*time.Millisecond); nil != err {
+		code, err := writeErr(err)
+		return &api.RespPutD{Code: code, ErrMsg: err.Error()}, err
 	}
 	return &api.RespPutD{Code: api.Code_Success, HashKey: hashKey}, nil
 }
@@ -120,8 +175,9 @@ func (l *APIServer) SetD(ctx context.Context, req *api.ReqSetD) (*api.RespSetD,
 	}
 	v = string(req.Value)
 PUT:
-	if hashKey, err = ObtainLily().SetD(req.Key, v); nil != err {
-		return &api.RespSetD{Code: api.Code_Fail, ErrMsg: err.Error()}, err
+	if hashKey, err = ObtainLily().SetDWithTTL(req.Key, v, time.Duration(req.TTL)*time.Millisecond); nil != err {
+		code, err := writeErr(err)
+		return &api.RespSetD{Code: code, ErrMsg: err.Error()}, err
 	}
 	return &api.RespSetD{Code: api.Code_Success, HashKey: hashKey}, nil
 }
@@ -157,8 +213,9 @@ func (l *APIServer) Put(ctx context.Context, req *api.ReqPut) (*api.RespPut, err
 	}
 	v = string(req.Value)
 PUT:
-	if hashKey, err = ObtainLily().Put(req.DatabaseName, req.FormName, req.Key, v); nil != err {
-		return &api.RespPut{Code: api.Code_Fail, ErrMsg: err.Error()}, err
+	if hashKey, err = ObtainLily().PutWithTTL(req.DatabaseName, req.FormName, req.Key, v, time.Duration(req.TTL)*time.Millisecond); nil != err {
+		code, err := writeErr(err)
+		return &api.RespPut{Code: code, ErrMsg: err.Error()}, err
 	}
 	return &api.RespPut{Code: api.Code_Success, HashKey: hashKey}, nil
 }
@@ -178,8 +235,9 @@ func (l *APIServer) Set(ctx context.Context, req *api.ReqSet) (*api.RespSet, err
 	}
 	v = string(req.Value)
 PUT:
-	if hashKey, err = ObtainLily().Set(req.DatabaseName, req.FormName, req.Key, v); nil != err {
-		return &api.RespSet{Code: api.Code_Fail, ErrMsg: err.Error()}, err
+	if hashKey, err = ObtainLily().SetWithTTL(req.DatabaseName, req.FormName, req.Key, v, time.Duration(req.TTL)*time.Millisecond); nil != err {
+		code, err := writeErr(err)
+		return &api.RespSet{Code: code, ErrMsg: err.Error()}, err
 	}
 	return &api.RespSet{Code: api.Code_Success, HashKey: hashKey}, nil
 }
@@ -200,6 +258,41 @@ func (l *APIServer) Get(ctx context.Context, req *api.ReqGet) (*api.RespGet, err
 	return &api.RespGet{Code: api.Code_Success, Value: data}, nil
 }
 
+// GetVersion 获取数据指定版本
+func (l *APIServer) GetVersion(ctx context.Context, req *api.ReqGetVersion) (*api.RespGet, error) {
+	var (
+		v    interface{}
+		data []byte
+		err  error
+	)
+	if v, err = ObtainLily().GetVersion(req.DatabaseName, req.FormName, req.Key, req.Version); nil != err {
+		return &api.RespGet{Code: api.Code_Fail, ErrMsg: err.Error()}, err
+	}
+	if data, err = msgpack.Marshal(v); nil != err {
+		return &api.RespGet{Code: api.Code_Fail, ErrMsg: err.Error()}, err
+	}
+	return &api.RespGet{Code: api.Code_Success, Value: data}, nil
+}
+
+// History 获取数据历史版本
+func (l *APIServer) History(ctx context.Context, req *api.ReqHistory) (*api.RespHistory, error) {
+	versions, err := ObtainLily().History(req.DatabaseName, req.FormName, req.Key)
+	if nil != err {
+		return &api.RespHistory{Code: api.Code_Fail, ErrMsg: err.Error()}, err
+	}
+	resp := &api.RespHistory{Code: api.Code_Success}
+	for _, version := range versions {
+		v := &api.Version{Version: version.Version, Deleted: version.Deleted, Time: version.Time, Expire: version.Expire}
+		if !version.Deleted {
+			if v.Value, err = msgpack.Marshal(version.Value); nil != err {
+				return &api.RespHistory{Code: api.Code_Fail, ErrMsg: err.Error()}, err
+			}
+		}
+		resp.Versions = append(resp.Versions, v)
+	}
+	return resp, nil
+}
+
 // Select 获取数据
 func (l *APIServer) Select(ctx context.Context, req *api.ReqSelect) (*api.RespSelect, error) {
 	var (
@@ -245,10 +338,273 @@ func (l *APIServer) Delete(ctx context.Context, req *api.ReqDelete) (*api.RespDe
 	return &api.RespDelete{Code: api.Code_Success, Count: count}, nil
 }
 
+// Compact 压缩表数据文件
+func (l *APIServer) Compact(ctx context.Context, req *api.ReqCompact) (*api.RespCompact, error) {
+	if req.Background {
+		go func() {
+			if _, err := ObtainLily().Compact(req.DatabaseName, req.FormName); nil != err {
+				log.Error("compact failed", log.Field("database", req.DatabaseName), log.Field("form", req.FormName), log.Err(err))
+			}
+		}()
+		return &api.RespCompact{Code: api.Code_Success}, nil
+	}
+	result, err := ObtainLily().Compact(req.DatabaseName, req.FormName)
+	if nil != err {
+		return &api.RespCompact{Code: api.Code_Fail, ErrMsg: err.Error()}, err
+	}
+	return &api.RespCompact{Code: api.Code_Success, Records: result.Records, SizeBefore: result.SizeBefore, SizeAfter: result.SizeAfter}, nil
+}
+
+// RotateKey 轮换数据密钥并重新加密库数据
+func (l *APIServer) RotateKey(ctx context.Context, req *api.ReqRotateKey) (*api.RespRotateKey, error) {
+	if req.Background {
+		go func() {
+			if _, err := ObtainLily().RotateKey(req.DatabaseName); nil != err {
+				log.Error("rotate key failed", log.Field("database", req.DatabaseName), log.Err(err))
+			}
+		}()
+		return &api.RespRotateKey{Code: api.Code_Success}, nil
+	}
+	result, err := ObtainLily().RotateKey(req.DatabaseName)
+	if nil != err {
+		return &api.RespRotateKey{Code: api.Code_Fail, ErrMsg: err.Error()}, err
+	}
+	return &api.RespRotateKey{Code: api.Code_Success, KeyVersion: result.KeyVersion, Records: result.Records}, nil
+}
+
+// SetSyncMode 设置数据库落盘策略
+func (l *APIServer) SetSyncMode(ctx context.Context, req *api.ReqSetSyncMode) (*api.Resp, error) {
+	if err := ObtainLily().SetSyncMode(req.DatabaseName, req.SyncMode); nil != err {
+		return &api.Resp{Code: api.Code_Fail, ErrMsg: err.Error()}, err
+	}
+	return &api.Resp{Code: api.Code_Success}, nil
+}
+
+// Backup 备份库
+func (l *APIServer) Backup(ctx context.Context, req *api.ReqBackup) (*api.RespBackup, error) {
+	result, err := ObtainLily().Backup(req.DatabaseName, req.Path)
+	if nil != err {
+		return &api.RespBackup{Code: api.Code_Fail, ErrMsg: err.Error()}, err
+	}
+	return &api.RespBackup{Code: api.Code_Success, Databases: result.Databases, Forms: result.Forms, Files: result.Files, Size: result.Size}, nil
+}
+
+// Restore 依据归档文件恢复库
+func (l *APIServer) Restore(ctx context.Context, req *api.ReqRestore) (*api.RespBackup, error) {
+	result, err := ObtainLily().Restore(req.Path)
+	if nil != err {
+		return &api.RespBackup{Code: api.Code_Fail, ErrMsg: err.Error()}, err
+	}
+	return &api.RespBackup{Code: api.Code_Success, Databases: result.Databases, Forms: result.Forms, Files: result.Files, Size: result.Size}, nil
+}
+
+// streamChunkSize 流式导入导出时单个数据分块大小
+const streamChunkSize = 64 * 1024
+
+// exportWriter 将导出数据作为分块发送
+type exportWriter struct {
+	stream api.LilyAPI_ExportServer
+}
+
+func (e *exportWriter) Write(p []byte) (int, error) {
+	if err := e.stream.Send(&api.RespExport{Code: api.Code_Success, Data: p}); nil != err {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// rejectWriter 将无法导入的行作为分块发送
+type rejectWriter struct {
+	stream api.LilyAPI_ImportServer
+}
+
+func (r *rejectWriter) Write(p []byte) (int, error) {
+	if err := r.stream.Send(&api.RespImport{Code: api.Code_Success, Reject: p}); nil != err {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// streamReader 将首个请求及后续请求的数据分块依次写入管道，供读取方按流读取
+//
+// recv 返回 io.EOF 时管道正常关闭，读取方提前关闭管道时停止写入
+func streamReader(first []byte, recv func() ([]byte, error)) *io.PipeReader {
+	reader, writer := io.Pipe()
+	go func() {
+		data := first
+		for {
+			if len(data) > 0 {
+				if _, err := writer.Write(data); nil != err {
+					return
+				}
+			}
+			var err error
+			if data, err = recv(); nil != err {
+				if io.EOF == err {
+					_ = writer.Close()
+				} else {
+					_ = writer.CloseWithError(err)
+				}
+				return
+			}
+		}
+	}()
+	return reader
+}
+
+// Export 导出表数据
+func (l *APIServer) Export(req *api.ReqExport, stream api.LilyAPI_ExportServer) error {
+	w := bufio.NewWriterSize(&exportWriter{stream: stream}, streamChunkSize)
+	rows, err := ObtainLily().Export(req.DatabaseName, req.FormName, req.Format, w)
+	if nil == err {
+		err = w.Flush()
+	}
+	if nil != err {
+		_ = stream.Send(&api.RespExport{Code: api.Code_Fail, ErrMsg: err.Error()})
+		return err
+	}
+	return stream.Send(&api.RespExport{Code: api.Code_Success, Rows: rows, Done: true})
+}
+
+// Import 导入表数据
+//
+// 首个请求携带库表名称及格式，后续请求数据分块依次写入管道供导入读取
+func (l *APIServer) Import(stream api.LilyAPI_ImportServer) error {
+	first, err := stream.Recv()
+	if nil != err {
+		return err
+	}
+	reader := streamReader(first.Data, func() ([]byte, error) {
+		req, err := stream.Recv()
+		if nil != err {
+			return nil, err
+		}
+		return req.Data, nil
+	})
+	defer func() { _ = reader.Close() }()
+	reject := bufio.NewWriterSize(&rejectWriter{stream: stream}, streamChunkSize)
+	result, err := ObtainLily().Import(first.DatabaseName, first.FormName, first.Format, reader, reject, func(result *ImportResult) {
+		_ = stream.Send(&api.RespImport{Code: api.Code_Success, Rows: result.Rows, Imported: result.Imported, Rejected: result.Rejected})
+	})
+	if nil == err {
+		err = reject.Flush()
+	}
+	if nil != err {
+		_ = stream.Send(&api.RespImport{Code: api.Code_Fail, ErrMsg: err.Error()})
+		return err
+	}
+	return stream.Send(&api.RespImport{Code: api.Code_Success, Rows: result.Rows, Imported: result.Imported, Rejected: result.Rejected, Done: true})
+}
+
+// writeErr 写入数据失败时的响应码及返回错误，违反唯一索引时返回 AlreadyExists 状态
+func writeErr(err error) (api.Code, error) {
+	if violation, ok := err.(*UniqueViolationError); ok {
+		return api.Code_UniqueViolation, status.Error(codes.AlreadyExists, violation.Error())
+	}
+	return api.Code_Fail, err
+}
+
+// blobWriter 将大数据作为分块发送
+type blobWriter struct {
+	stream api.LilyAPI_GetBlobServer
+}
+
+func (b *blobWriter) Write(p []byte) (int, error) {
+	if err := b.stream.Send(&api.RespGetBlob{Code: api.Code_Success, Data: p}); nil != err {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// blob2RPC 大数据描述转rpc对象
+func blob2RPC(info *BlobInfo) *api.Blob {
+	return &api.Blob{Key: info.Key, ID: info.ID, Size: info.Size, Chunks: info.Chunks, ChunkSize: info.ChunkSize, SHA256: info.SHA256}
+}
+
+// PutBlob 分块存储大数据
+//
+// 首个请求携带库表名称及key，后续请求数据分块依次写入管道供存储读取
+func (l *APIServer) PutBlob(stream api.LilyAPI_PutBlobServer) error {
+	first, err := stream.Recv()
+	if nil != err {
+		return err
+	}
+	reader := streamReader(first.Data, func() ([]byte, error) {
+		req, err := stream.Recv()
+		if nil != err {
+			return nil, err
+		}
+		return req.Data, nil
+	})
+	defer func() { _ = reader.Close() }()
+	info, err := ObtainLily().PutBlob(first.DatabaseName, first.FormName, first.Key, reader)
+	if nil != err {
+		return stream.SendAndClose(&api.RespBlob{Code: api.Code_Fail, ErrMsg: err.Error()})
+	}
+	return stream.SendAndClose(&api.RespBlob{Code: api.Code_Success, Blob: blob2RPC(info)})
+}
+
+// GetBlob 读取大数据
+func (l *APIServer) GetBlob(req *api.ReqBlob, stream api.LilyAPI_GetBlobServer) error {
+	w := bufio.NewWriterSize(&blobWriter{stream: stream}, streamChunkSize)
+	info, err := ObtainLily().GetBlob(req.DatabaseName, req.FormName, req.Key, w)
+	if nil == err {
+		err = w.Flush()
+	}
+	if nil != err {
+		_ = stream.Send(&api.RespGetBlob{Code: api.Code_Fail, ErrMsg: err.Error()})
+		return err
+	}
+	return stream.Send(&api.RespGetBlob{Code: api.Code_Success, Blob: blob2RPC(info), Done: true})
+}
+
+// RemoveBlob 删除大数据
+func (l *APIServer) RemoveBlob(ctx context.Context, req *api.ReqBlob) (*api.Resp, error) {
+	if err := ObtainLily().RemoveBlob(req.DatabaseName, req.FormName, req.Key); nil != err {
+		return &api.Resp{Code: api.Code_Fail, ErrMsg: err.Error()}, err
+	}
+	return &api.Resp{Code: api.Code_Success}, nil
+}
+
+// RebuildIndex 重建索引
+func (l *APIServer) RebuildIndex(ctx context.Context, req *api.ReqRebuildIndex) (*api.RespRebuildIndex, error) {
+	count, err := ObtainLily().RebuildIndex(req.DatabaseName, req.FormName, req.KeyStructure)
+	if nil != err {
+		return &api.RespRebuildIndex{Code: api.Code_Fail, ErrMsg: err.Error()}, err
+	}
+	return &api.RespRebuildIndex{Code: api.Code_Success, Count: count}, nil
+}
+
+// GetIndexStatus 获取索引构建状态
+func (l *APIServer) GetIndexStatus(ctx context.Context, req *api.ReqIndexStatus) (*api.RespIndexStatus, error) {
+	status, err := ObtainLily().IndexStatus(req.DatabaseName, req.FormName, req.KeyStructure)
+	if nil != err {
+		return &api.RespIndexStatus{Code: api.Code_Fail, ErrMsg: err.Error()}, err
+	}
+	return &api.RespIndexStatus{Code: api.Code_Success, State: status.State, Total: status.Total, Done: status.Done, ErrMsg: status.Err}, nil
+}
+
+// GetFormStats 获取表统计信息
+func (l *APIServer) GetFormStats(ctx context.Context, req *api.ReqFormStats) (*api.RespFormStats, error) {
+	stats, err := ObtainLily().GetFormStats(req.DatabaseName, req.FormName)
+	if nil != err {
+		return &api.RespFormStats{Code: api.Code_Fail, ErrMsg: err.Error()}, err
+	}
+	return &api.RespFormStats{
+		Code:        api.Code_Success,
+		Compression: FormatCompression2API(stats.Compression),
+		Segments:    stats.Segments,
+		Records:     stats.Records,
+		DataSize:    stats.DataSize,
+		RawSize:     stats.RawSize,
+		Ratio:       stats.Ratio,
+	}, nil
+}
+
 func (l *APIServer) formatDBs(dbs []Database) []*api.Database {
 	var respDBs []*api.Database
 	for _, db := range dbs {
-		respDBs = append(respDBs, &api.Database{ID: db.getID(), Name: db.getName(), Comment: db.getComment(), Forms: l.formatForms(db)})
+		respDBs = append(respDBs, &api.Database{ID: db.getID(), Name: db.getName(), Comment: db.getComment(), SyncMode: db.getSyncMode(), Forms: l.formatForms(db)})
 	}
 	return respDBs
 }
@@ -257,11 +613,14 @@ func (l *APIServer) formatForms(db Database) map[string]*api.Form {
 	var fms = make(map[string]*api.Form)
 	for _, form := range db.getForms() {
 		fms[form.getID()] = &api.Form{
-			ID:       form.getID(),
-			Name:     form.getName(),
-			Comment:  form.getComment(),
-			FormType: FormatFormType2API(form.getFormType()),
-			Indexes:  l.formatIndexes(form),
+			ID:          form.getID(),
+			Name:        form.getName(),
+			Comment:     form.getComment(),
+			FormType:    FormatFormType2API(form.getFormType()),
+			Indexes:     l.formatIndexes(form),
+			Compression: FormatCompression2API(form.getCompression()),
+			Engine:      form.getEngine(),
+			Versions:    form.getVersions(),
 		}
 	}
 	return fms
@@ -271,11 +630,14 @@ func (l *APIServer) formatFormArr(db Database) []*api.Form {
 	var fms []*api.Form
 	for _, form := range db.getForms() {
 		fms = append(fms, &api.Form{
-			ID:       form.getID(),
-			Name:     form.getName(),
-			Comment:  form.getComment(),
-			FormType: FormatFormType2API(form.getFormType()),
-			Indexes:  l.formatIndexes(form),
+			ID:          form.getID(),
+			Name:        form.getName(),
+			Comment:     form.getComment(),
+			FormType:    FormatFormType2API(form.getFormType()),
+			Indexes:     l.formatIndexes(form),
+			Compression: FormatCompression2API(form.getCompression()),
+			Engine:      form.getEngine(),
+			Versions:    form.getVersions(),
 		})
 	}
 	return fms
@@ -301,10 +663,38 @@ func FormatFormType2API(ft string) api.FormType {
 	}
 }
 
+// FormatCompression rpc压缩方式转压缩方式
+func FormatCompression(compression api.Compression) string {
+	switch compression {
+	default:
+		return CompressionNone
+	case api.Compression_Gzip:
+		return CompressionGzip
+	case api.Compression_Flate:
+		return CompressionFlate
+	case api.Compression_Snappy:
+		return CompressionSnappy
+	}
+}
+
+// FormatCompression2API 压缩方式转rpc压缩方式
+func FormatCompression2API(compression string) api.Compression {
+	switch compression {
+	default:
+		return api.Compression_None
+	case CompressionGzip:
+		return api.Compression_Gzip
+	case CompressionFlate:
+		return api.Compression_Flate
+	case CompressionSnappy:
+		return api.Compression_Snappy
+	}
+}
+
 func (l *APIServer) formatIndexes(fm Form) map[string]*api.Index {
 	var idx = make(map[string]*api.Index)
 	for _, index := range fm.getIndexes() {
-		idx[index.getID()] = &api.Index{ID: index.getID(), Primary: index.isPrimary(), KeyStructure: index.getKeyStructure()}
+		idx[index.getID()] = &api.Index{ID: index.getID(), Primary: index.isPrimary(), KeyStructure: index.getKeyStructure(), Unique: index.isUnique(), Building: !index.isReady()}
 	}
 	return idx
 }
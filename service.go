@@ -16,7 +16,10 @@ package lily
 
 import (
 	"context"
+	"errors"
 	"github.com/aberic/lily/api"
+	"io"
+	"time"
 )
 
 //var ServerURL = "localhost:19877"
@@ -71,6 +74,53 @@ func CreateDoc(serverURL, dbName, name, comment string) error {
 	return err
 }
 
+// CreateFormWithCompression 按指定压缩方式创建表
+func CreateFormWithCompression(serverURL, dbName, name, comment, formType, compression string) error {
+	return CreateFormWithOptions(serverURL, dbName, name, comment, formType, &FormOptions{Compression: compression})
+}
+
+// CreateFormWithOptions 按表选项创建表
+func CreateFormWithOptions(serverURL, dbName, name, comment, formType string, options *FormOptions) error {
+	req := &api.ReqCreateForm{DatabaseName: dbName, Name: name, Comment: comment, FormType: FormatFormType2API(formType)}
+	if nil != options {
+		req.Compression = FormatCompression2API(options.Compression)
+		req.Engine = options.Engine
+		req.Versions = options.Versions
+	}
+	_, err := createForm(serverURL, req)
+	return err
+}
+
+// DropDatabase 删除库
+func DropDatabase(serverURL, name string) error {
+	_, err := dropDatabase(serverURL, &api.ReqDropDatabase{Name: name})
+	return err
+}
+
+// DropForm 删除表
+func DropForm(serverURL, dbName, name string) error {
+	_, err := dropForm(serverURL, &api.ReqDropForm{DatabaseName: dbName, FormName: name})
+	return err
+}
+
+// DropIndex 删除索引
+func DropIndex(serverURL, dbName, formName, keyStructure string) error {
+	_, err := dropIndex(serverURL, &api.ReqDropIndex{DatabaseName: dbName, FormName: formName, KeyStructure: keyStructure})
+	return err
+}
+
+// RenameDatabase 重命名库
+func RenameDatabase(serverURL, name, newName string) error {
+	_, err := renameDatabase(serverURL, &api.ReqRenameDatabase{Name: name, NewName: newName})
+	return err
+}
+
+// RenameForm 重命名表
+func RenameForm(serverURL, dbName, name, newName string) error {
+	_, err := renameForm(serverURL, &api.ReqRenameForm{DatabaseName: dbName, FormName: name, NewName: newName})
+	return err
+}
+
 // PutD 新增数据
 func PutD(serverURL, key, value string) (*api.RespPutD, error) {
 	res, err := putD(serverURL, &api.ReqPutD{Key: key, Value: []byte(value)})
@@ -89,6 +139,24 @@ func SetD(serverURL, key, value string) (*api.RespSetD, error) {
 	return res.(*api.RespSetD), err
 }
 
+// PutDWithTTL 新增数据，数据在存活时间后过期
+func PutDWithTTL(serverURL, key, value string, ttl time.Duration) (*api.RespPutD, error) {
+	res, err := putD(serverURL, &api.ReqPutD{Key: key, Value: []byte(value), TTL: int64(ttl / time.Millisecond)})
+	if nil != err {
+		return nil, err
+	}
+	return res.(*api.RespPutD), err
+}
+
+// SetDWithTTL 设置数据，数据在存活时间后过期
+func SetDWithTTL(serverURL, key, value string, ttl time.Duration) (*api.RespSetD, error) {
+	res, err := setD(serverURL, &api.ReqSetD{Key: key, Value: []byte(value), TTL: int64(ttl / time.Millisecond)})
+	if nil != err {
+		return nil, err
+	}
+	return res.(*api.RespSetD), err
+}
+
 // GetD 获取数据
 func GetD(serverURL, key string) (*api.RespGetD, error) {
 	res, err := getD(serverURL, &api.ReqGetD{Key: key})
@@ -116,6 +184,24 @@ func Set(serverURL, databaseName, formName, key, value string) (*api.RespSet, er
 	return res.(*api.RespSet), err
 }
 
+// PutWithTTL 新增数据，数据在存活时间后过期
+func PutWithTTL(serverURL, databaseName, formName, key, value string, ttl time.Duration) (*api.RespPut, error) {
+	res, err := put(serverURL, &api.ReqPut{DatabaseName: databaseName, FormName: formName, Key: key, Value: []byte(value), TTL: int64(ttl / time.Millisecond)})
+	if nil != err {
+		return nil, err
+	}
+	return res.(*api.RespPut), err
+}
+
+// SetWithTTL 设置数据，数据在存活时间后过期
+func SetWithTTL(serverURL, databaseName, formName, key, value string, ttl time.Duration) (*api.RespSet, error) {
+	res, err := set(serverURL, &api.ReqSet{DatabaseName: databaseName, FormName: formName, Key: key, Value: []byte(value), TTL: int64(ttl / time.Millisecond)})
+	if nil != err {
+		return nil, err
+	}
+	return res.(*api.RespSet), err
+}
+
 // Get 获取数据
 func Get(serverURL, databaseName, formName, key string) (*api.RespGet, error) {
 	res, err := get(serverURL, &api.ReqGet{DatabaseName: databaseName, FormName: formName, Key: key})
@@ -125,6 +211,24 @@ func Get(serverURL, databaseName, formName, key string) (*api.RespGet, error) {
 	return res.(*api.RespGet), err
 }
 
+// GetVersion 获取数据指定版本
+func GetVersion(serverURL, databaseName, formName, key string, version uint64) (*api.RespGet, error) {
+	res, err := getVersion(serverURL, &api.ReqGetVersion{DatabaseName: databaseName, FormName: formName, Key: key, Version: version})
+	if nil != err {
+		return nil, err
+	}
+	return res.(*api.RespGet), err
+}
+
+// History 获取数据历史版本
+func History(serverURL, databaseName, formName, key string) (*api.RespHistory, error) {
+	res, err := history(serverURL, &api.ReqHistory{DatabaseName: databaseName, FormName: formName, Key: key})
+	if nil != err {
+		return nil, err
+	}
+	return res.(*api.RespHistory), err
+}
+
 // Select 获取数据
 func Select(serverURL, databaseName, formName string, selector *api.Selector) (*api.RespSelect, error) {
 	res, err := query(serverURL, &api.ReqSelect{DatabaseName: databaseName, FormName: formName, Selector: selector})
@@ -143,6 +247,278 @@ func Delete(serverURL, databaseName, formName string, selector *api.Selector) (*
 	return res.(*api.Resp), err
 }
 
+// Compact 压缩表数据文件
+//
+// background 是否后台执行，后台执行时立即返回
+func Compact(serverURL, databaseName, formName string, background bool) (*api.RespCompact, error) {
+	res, err := compact(serverURL, &api.ReqCompact{DatabaseName: databaseName, FormName: formName, Background: background})
+	if nil != err {
+		return nil, err
+	}
+	return res.(*api.RespCompact), nil
+}
+
+// RotateKey 轮换数据密钥并重新加密库数据
+func RotateKey(serverURL, databaseName string, background bool) (*api.RespRotateKey, error) {
+	res, err := rotateKey(serverURL, &api.ReqRotateKey{DatabaseName: databaseName, Background: background})
+	if nil != err {
+		return nil, err
+	}
+	return res.(*api.RespRotateKey), nil
+}
+
+// SetSyncMode 设置数据库落盘策略
+//
+// syncMode 落盘策略 none/interval/always，为空时使用服务端配置文件中的 SyncMode
+func SetSyncMode(serverURL, databaseName, syncMode string) error {
+	_, err := setSyncMode(serverURL, &api.ReqSetSyncMode{DatabaseName: databaseName, SyncMode: syncMode})
+	return err
+}
+
+// Backup 备份库
+//
+// databaseName 数据库名称，为空时备份全部库
+//
+// path 服务端归档文件路径
+func Backup(serverURL, databaseName, path string) (*api.RespBackup, error) {
+	res, err := backup(serverURL, &api.ReqBackup{DatabaseName: databaseName, Path: path})
+	if nil != err {
+		return nil, err
+	}
+	return res.(*api.RespBackup), nil
+}
+
+// Restore 依据归档文件恢复库
+//
+// path 服务端归档文件路径
+func Restore(serverURL, path string) (*api.RespBackup, error) {
+	res, err := restore(serverURL, &api.ReqRestore{Path: path})
+	if nil != err {
+		return nil, err
+	}
+	return res.(*api.RespBackup), nil
+}
+
+// Export 导出表数据
+//
+// format 导出格式 ExportJSONL/ExportCSV
+//
+// w 导出数据写入对象，返回导出行数
+func Export(serverURL, databaseName, formName, format string, w io.Writer) (int64, error) {
+	stream, err := getClient(serverURL).Export(context.Background(), &api.ReqExport{DatabaseName: databaseName, FormName: formName, Format: format})
+	if nil != err {
+		return 0, err
+	}
+	for {
+		resp, err := stream.Recv()
+		if nil != err {
+			return 0, err
+		}
+		if resp.Code == api.Code_Fail {
+			return 0, errors.New(resp.ErrMsg)
+		}
+		if len(resp.Data) > 0 {
+			if _, err = w.Write(resp.Data); nil != err {
+				return 0, err
+			}
+		}
+		if resp.Done {
+			return resp.Rows, nil
+		}
+	}
+}
+
+// Import 导入表数据
+//
+// format 导入格式 ExportJSONL/ExportCSV
+//
+// r 导入数据读取对象
+//
+// reject 无法导入的行写入对象，为nil时丢弃
+//
+// progress 导入进度回调，可为nil
+func Import(serverURL, databaseName, formName, format string, r io.Reader, reject io.Writer, progress func(*api.RespImport)) (*api.RespImport, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := getClient(serverURL).Import(ctx)
+	if nil != err {
+		return nil, err
+	}
+	// 读取或发送失败时取消请求，避免服务端等待后续数据分块
+	sendErr := make(chan error, 1)
+	go func() {
+		var (
+			buf  = make([]byte, streamChunkSize)
+			req  = &api.ReqImport{DatabaseName: databaseName, FormName: formName, Format: format}
+			sent bool
+		)
+		for {
+			n, err := r.Read(buf)
+			if n > 0 {
+				req.Data = buf[:n]
+				if err := stream.Send(req); nil != err {
+					sendErr <- err
+					cancel()
+					return
+				}
+				req, sent = &api.ReqImport{}, true
+			}
+			if io.EOF == err {
+				break
+			}
+			if nil != err {
+				sendErr <- err
+				cancel()
+				return
+			}
+		}
+		if !sent {
+			if err := stream.Send(req); nil != err {
+				sendErr <- err
+				cancel()
+				return
+			}
+		}
+		sendErr <- stream.CloseSend()
+	}()
+	for {
+		resp, err := stream.Recv()
+		if nil != err {
+			select {
+			case e := <-sendErr:
+				if nil != e {
+					return nil, e
+				}
+			default:
+			}
+			return nil, err
+		}
+		if resp.Code == api.Code_Fail {
+			return resp, errors.New(resp.ErrMsg)
+		}
+		if len(resp.Reject) > 0 {
+			if nil != reject {
+				if _, err = reject.Write(resp.Reject); nil != err {
+					return nil, err
+				}
+			}
+			continue
+		}
+		if resp.Done {
+			return resp, <-sendErr
+		}
+		if nil != progress {
+			progress(resp)
+		}
+	}
+}
+
+// PutBlob 分块存储大数据
+//
+// r 大数据读取对象
+func PutBlob(serverURL, databaseName, formName, key string, r io.Reader) (*api.Blob, error) {
+	stream, err := getClient(serverURL).PutBlob(context.Background())
+	if nil != err {
+		return nil, err
+	}
+	var (
+		buf  = make([]byte, streamChunkSize)
+		req  = &api.ReqPutBlob{DatabaseName: databaseName, FormName: formName, Key: key}
+		sent bool
+	)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 || (io.EOF == err && !sent) {
+			req.Data = buf[:n]
+			// 服务端提前结束时发送返回 io.EOF，结果由 CloseAndRecv 获取
+			if err := stream.Send(req); io.EOF == err {
+				break
+			} else if nil != err {
+				return nil, err
+			}
+			req, sent = &api.ReqPutBlob{}, true
+		}
+		if io.EOF == err {
+			break
+		}
+		if nil != err {
+			_ = stream.CloseSend()
+			return nil, err
+		}
+	}
+	resp, err := stream.CloseAndRecv()
+	if nil != err {
+		return nil, err
+	}
+	if resp.Code == api.Code_Fail {
+		return nil, errors.New(resp.ErrMsg)
+	}
+	return resp.Blob, nil
+}
+
+// GetBlob 读取大数据
+//
+// w 大数据写入对象
+func GetBlob(serverURL, databaseName, formName, key string, w io.Writer) (*api.Blob, error) {
+	stream, err := getClient(serverURL).GetBlob(context.Background(), &api.ReqBlob{DatabaseName: databaseName, FormName: formName, Key: key})
+	if nil != err {
+		return nil, err
+	}
+	for {
+		resp, err := stream.Recv()
+		if nil != err {
+			return nil, err
+		}
+		if resp.Code == api.Code_Fail {
+			return nil, errors.New(resp.ErrMsg)
+		}
+		if len(resp.Data) > 0 {
+			if _, err = w.Write(resp.Data); nil != err {
+				return nil, err
+			}
+		}
+		if resp.Done {
+			return resp.Blob, nil
+		}
+	}
+}
+
+// RemoveBlob 删除大数据
+func RemoveBlob(serverURL, databaseName, formName, key string) (*api.Resp, error) {
+	res, err := removeBlob(serverURL, &api.ReqBlob{DatabaseName: databaseName, FormName: formName, Key: key})
+	if nil != err {
+		return nil, err
+	}
+	return res.(*api.Resp), nil
+}
+
+// RebuildIndex 重建索引
+func RebuildIndex(serverURL, databaseName, formName, keyStructure string) (*api.RespRebuildIndex, error) {
+	res, err := rebuildIndex(serverURL, &api.ReqRebuildIndex{DatabaseName: databaseName, FormName: formName, KeyStructure: keyStructure})
+	if nil != err {
+		return nil, err
+	}
+	return res.(*api.RespRebuildIndex), nil
+}
+
+// GetIndexStatus 获取索引构建状态
+func GetIndexStatus(serverURL, databaseName, formName, keyStructure string) (*api.RespIndexStatus, error) {
+	res, err := getIndexStatus(serverURL, &api.ReqIndexStatus{DatabaseName: databaseName, FormName: formName, KeyStructure: keyStructure})
+	if nil != err {
+		return nil, err
+	}
+	return res.(*api.RespIndexStatus), nil
+}
+
+// GetFormStats 获取表统计信息
+func GetFormStats(serverURL, databaseName, formName string) (*api.RespFormStats, error) {
+	res, err := getFormStats(serverURL, &api.ReqFormStats{DatabaseName: databaseName, FormName: formName})
+	if nil != err {
+		return nil, err
+	}
+	return res.(*api.RespFormStats), nil
+}
+
 // getConf 获取数据库引擎对象
 func getConf(serverURL string, req *api.ReqConf) (interface{}, error) {
 	return getClient(serverURL).GetConf(context.Background(), req)
@@ -168,6 +544,31 @@ func createForm(serverURL string, req *api.ReqCreateForm) (interface{}, error) {
 	return getClient(serverURL).CreateForm(context.Background(), req)
 }
 
+// dropDatabase 删除库
+func dropDatabase(serverURL string, req *api.ReqDropDatabase) (interface{}, error) {
+	return getClient(serverURL).DropDatabase(context.Background(), req)
+}
+
+// dropForm 删除表
+func dropForm(serverURL string, req *api.ReqDropForm) (interface{}, error) {
+	return getClient(serverURL).DropForm(context.Background(), req)
+}
+
+// dropIndex 删除索引
+func dropIndex(serverURL string, req *api.ReqDropIndex) (interface{}, error) {
+	return getClient(serverURL).DropIndex(context.Background(), req)
+}
+
+// renameDatabase 重命名库
+func renameDatabase(serverURL string, req *api.ReqRenameDatabase) (interface{}, error) {
+	return getClient(serverURL).RenameDatabase(context.Background(), req)
+}
+
+// renameForm 重命名表
+func renameForm(serverURL string, req *api.ReqRenameForm) (interface{}, error) {
+	return getClient(serverURL).RenameForm(context.Background(), req)
+}
+
 // putD 新增数据
 func putD(serverURL string, req *api.ReqPutD) (interface{}, error) {
 	return getClient(serverURL).PutD(context.Background(), req)
@@ -198,6 +599,16 @@ func get(serverURL string, req *api.ReqGet) (interface{}, error) {
 	return getClient(serverURL).Get(context.Background(), req)
 }
 
+// getVersion 获取数据指定版本
+func getVersion(serverURL string, req *api.ReqGetVersion) (interface{}, error) {
+	return getClient(serverURL).GetVersion(context.Background(), req)
+}
+
+// history 获取数据历史版本
+func history(serverURL string, req *api.ReqHistory) (interface{}, error) {
+	return getClient(serverURL).History(context.Background(), req)
+}
+
 // query 获取数据
 func query(serverURL string, req *api.ReqSelect) (interface{}, error) {
 	return getClient(serverURL).Select(context.Background(), req)
@@ -212,3 +623,48 @@ func remove(serverURL string, req *api.ReqRemove) (interface{}, error) {
 func del(serverURL string, req *api.ReqDelete) (interface{}, error) {
 	return getClient(serverURL).Delete(context.Background(), req)
 }
+
+// compact 压缩表数据文件
+func compact(serverURL string, req *api.ReqCompact) (interface{}, error) {
+	return getClient(serverURL).Compact(context.Background(), req)
+}
+
+// rebuildIndex 重建索引
+func rebuildIndex(serverURL string, req *api.ReqRebuildIndex) (interface{}, error) {
+	return getClient(serverURL).RebuildIndex(context.Background(), req)
+}
+
+// getIndexStatus 获取索引构建状态
+func getIndexStatus(serverURL string, req *api.ReqIndexStatus) (interface{}, error) {
+	return getClient(serverURL).GetIndexStatus(context.Background(), req)
+}
+
+// getFormStats 获取表统计信息
+func getFormStats(serverURL string, req *api.ReqFormStats) (interface{}, error) {
+	return getClient(serverURL).GetFormStats(context.Background(), req)
+}
+
+// rotateKey 轮换数据密钥
+func rotateKey(serverURL string, req *api.ReqRotateKey) (interface{}, error) {
+	return getClient(serverURL).RotateKey(context.Background(), req)
+}
+
+// setSyncMode 设置数据库落盘策略
+func setSyncMode(serverURL string, req *api.ReqSetSyncMode) (interface{}, error) {
+	return getClient(serverURL).SetSyncMode(context.Background(), req)
+}
+
+// backup 备份库
+func backup(serverURL string, req *api.ReqBackup) (interface{}, error) {
+	return getClient(serverURL).Backup(context.Background(), req)
+}
+
+// restore 依据归档文件恢复库
+func restore(serverURL string, req *api.ReqRestore) (interface{}, error) {
+	return getClient(serverURL).Restore(context.Background(), req)
+}
+
+// removeBlob 删除大数据
+func removeBlob(serverURL string, req *api.ReqBlob) (interface{}, error) {
+	return getClient(serverURL).RemoveBlob(context.Background(), req)
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+// FormStats 表统计信息
+type FormStats struct {
+	Compression string  // Compression 表数据压缩方式
+	Segments    int64   // Segments 数据分段文件数
+	Records     int64   // Records 数据文件中的记录数，含已失效记录
+	DataSize    int64   // DataSize 数据记录落盘总大小
+	RawSize     int64   // RawSize 数据记录未压缩时总大小
+	Ratio       float64 // Ratio 压缩率，DataSize/RawSize，无记录时为1
+}
+
+// formStats 扫描表全部数据分段文件统计表信息
+//
+// 统计期间持有表读锁，写入将等待统计完成
+func (d *database) formStats(formName string) (*FormStats, error) {
+	form := d.getForms()[formName]
+	if nil == form {
+		return nil, formIsInvalid(formName)
+	}
+	if form.getEngine() != EngineFile {
+		return nil, ErrEngineUnsupported
+	}
+	defer form.rUnLock()
+	form.rLock()
+	stats := &FormStats{Compression: form.getCompression(), Ratio: 1}
+	for _, segment := range formSegments(d.id, form.getID()) {
+		records, err := scanSegment(pathFormDataFile(d.id, form.getID(), segment), segment, d.keys)
+		if nil != err {
+			return nil, err
+		}
+		stats.Segments++
+		for _, record := range records {
+			stats.Records++
+			stats.DataSize += int64(record.seekLast)
+			stats.RawSize += int64(record.rawLast)
+		}
+	}
+	if stats.RawSize > 0 {
+		stats.Ratio = float64(stats.DataSize) / float64(stats.RawSize)
+	}
+	return stats, nil
+}
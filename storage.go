@@ -16,14 +16,15 @@ package lily
 
 import (
 	"bufio"
+	"encoding/binary"
 	"errors"
 	"github.com/aberic/gnomon"
 	"github.com/aberic/gnomon/log"
 	"github.com/vmihailenco/msgpack"
+	"hash/crc32"
 	"io"
-	"os"
-	"strings"
 	"sync"
+	"time"
 )
 
 var (
@@ -31,19 +32,54 @@ var (
 	ErrValueType = errors.New("value type error")
 	// ErrValueInvalid value is invalid
 	ErrValueInvalid = errors.New("value is invalid")
+	// ErrValueExpired value is expired
+	ErrValueExpired = errors.New("value is expired")
+	// ErrRecordCorrupt 数据记录残缺或校验失败
+	ErrRecordCorrupt = errors.New("record is corrupt")
+	// ErrIndexCorrupt 索引记录残缺或校验失败
+	ErrIndexCorrupt = errors.New("index entry is corrupt")
+)
+
+const (
+	// recordMagic 数据记录头标识，msgpack 未使用该字节，据此与无记录头的旧版数据区分
+	recordMagic byte = 0xc1
+	// recordHeadLen 数据记录头长度，1字节标识 + 1字节标记 + 4字节大端长度 + 4字节crc32
+	recordHeadLen = 10
+	// recordSealed 记录头标记中的加密位，记录内容以库数据密钥加密
+	recordSealed byte = 0x10
 )
 
 type valueData struct {
 	K string      // key
 	I bool        // 是否有效
 	V interface{} // 存储数据
+	E int64       // 过期时间，unix纳秒，0表示永不过期
+	N uint64      // 版本号，自1开始递增，删除同样产生新版本
+	T int64       // 写入时间，unix纳秒
+	S uint32      // 上一版本所在数据分段文件序号
+	O int64       // 上一版本起始seek
+	L int         // 上一版本持续seek，0表示无上一版本
+}
+
+// expired 记录是否已过期
+func (vd *valueData) expired(now int64) bool {
+	return vd.E > 0 && vd.E <= now
+}
+
+// version 记录版本号，无版本号的旧版数据视为第1版
+func (vd *valueData) version() uint64 {
+	if vd.N == 0 {
+		return 1
+	}
+	return vd.N
 }
 
 // writeResult 数据存储结果
 type writeResult struct {
-	seekStartIndex int64 // 索引最终存储在文件中的起始位置
-	seekStart      int64 // 16位起始seek
-	seekLast       int   // 8位持续seek
+	seekStartIndex int64  // 索引最终存储在文件中的起始位置
+	segment        uint32 // 数据所在分段文件序号
+	seekStart      int64  // 16位起始seek
+	seekLast       int    // 8位持续seek
 	err            error
 }
 
@@ -62,168 +98,383 @@ var (
 func store() *storage {
 	onceStorage.Do(func() {
 		if nil == stg {
-			stg = &storage{limitOpenFileChan: make(chan int, obtainConf().LimitOpenFile)}
+			stg = &storage{files: newFileCache(int(obtainConf().LimitOpenFile))}
 		}
 	})
 	return stg
 }
 
 type storage struct {
-	limitOpenFileChan chan int // limitOpenFileChan 限制打开文件描述符次数
+	files *fileCache // files 数据分段文件及索引文件句柄缓存，容量为 LimitOpenFile
 }
 
-func (s *storage) storeIndex(ib IndexBack, wf *writeResult) *writeResult {
+// storeIndex 存储索引
+//
+// syncMode 索引所属库的落盘策略
+func (s *storage) storeIndex(ib IndexBack, wf *writeResult, syncMode string) *writeResult {
 	var (
-		file *os.File
+		file *cachedFile
 		err  error
 	)
 	defer ib.getLocker().unLock()
 	ib.getLocker().lock()
 	md5Key := gnomon.HashMD516(ib.getKey()) // hash(keyStructure) 会发生碰撞，因此这里存储md5结果进行反向验证
-	// 写入11位key及16位md5后key
-	appendStr := strings.Join([]string{gnomon.StringPrefixSupplementZero(gnomon.ScaleUint64ToDDuoString(ib.getHashKey()), 11), md5Key}, "")
 	//log.Debug("storeIndex",
 	//	log.Field("appendStr", appendStr),
 	//	log.Field("formIndexFilePath", ib.getFormIndexFilePath()),
 	//	log.Field("seekStartIndex", ib.getLink().getSeekStartIndex()))
-	defer func() {
-		if nil != file {
-			<-s.limitOpenFileChan
-			_ = file.Close()
-		}
-	}()
 	// 将获取到的索引存储位置传入。如果为0，则表示没有存储过；如果不为0，则覆盖旧的存储记录
-	if file, err = s.openFile(ib.getFormIndexFilePath(), os.O_CREATE|os.O_RDWR); nil != err {
+	if file, err = s.files.acquire(ib.getFormIndexFilePath(), true); nil != err {
 		log.Error("storeIndex", log.Err(err))
 		return &writeResult{err: err}
 	}
+	defer s.files.release(file)
 	var seekEnd int64
+	entry := indexEntry(ib.getHashKey(), md5Key, wf.segment, wf.seekStart, wf.seekLast)
 	//log.Debug("running", log.Field("type", "moldIndex"), log.Field("seekStartIndex", it.link.getSeekStartIndex()))
 	if ib.getLink().getSeekStartIndex() == -1 {
-		if seekEnd, err = file.Seek(0, io.SeekEnd); nil != err {
-			log.Error("storeIndex", log.Err(err))
+		// 新建的索引文件先写入文件头
+		if err = file.appendHeader(indexFileHeader()); nil != err {
 			return &writeResult{err: err}
 		}
+		seekEnd, err = file.append(entry)
 		//log.Debug("running", log.Field("it.link.seekStartIndex == -1", seekEnd))
 	} else {
-		if seekEnd, err = file.Seek(ib.getLink().getSeekStartIndex(), io.SeekStart); nil != err { // 寻址到原索引起始位置
-			log.Error("storeIndex", log.Err(err))
-			return &writeResult{err: err}
-		}
+		seekEnd = ib.getLink().getSeekStartIndex() // 覆盖原索引起始位置
+		err = file.writeAt(entry, seekEnd)
 		//log.Debug("running", log.Field("seekStartIndex", it.link.getSeekStartIndex()), log.Field("it.link.seekStartIndex != -1", seekEnd))
 	}
-	// 写入11位key及16位md5后key及5位起始seek和4位持续seek
-	if _, err = file.WriteString(strings.Join([]string{appendStr,
-		gnomon.StringPrefixSupplementZero(gnomon.ScaleInt64ToDDuoString(wf.seekStart), 11),
-		gnomon.StringPrefixSupplementZero(gnomon.ScaleIntToDDuoString(wf.seekLast), 4)}, "")); nil != err {
+	if nil == err {
+		err = file.persist(syncMode)
+	}
+	if nil != err {
 		//log.Error("running", log.Field("seekStartIndex", seekEnd), log.Err(err))
 		return &writeResult{err: err}
 	}
 	//log.Debug("storeIndex", log.Field("ib.getKey()", ib.getKey()), log.Field("md516Key", md516Key), log.Field("seekStartIndex", wf.seekStartIndex))
 	ib.getLink().setSeekStartIndex(seekEnd)
 	ib.getLink().setMD5Key(md5Key)
+	ib.getLink().setSegment(wf.segment)
 	ib.getLink().setSeekStart(wf.seekStart)
 	ib.getLink().setSeekLast(wf.seekLast)
 	//log.Debug("running", log.Field("it.link.seekStartIndex", seekEnd), log.Err(err))
 	return &writeResult{
 		seekStartIndex: seekEnd,
+		segment:        wf.segment,
 		seekStart:      wf.seekStart,
 		seekLast:       wf.seekLast,
 		err:            err}
 }
 
+// recordScanner 顺序扫描表数据文件
+//
+// 带记录头的记录依据长度定位下一条，旧版无记录头的记录依据 msgpack 解码长度定位下一条
+type recordScanner struct {
+	reader  *bufio.Reader
+	decoder *msgpack.Decoder
+	keys    *keyring // 库数据密钥环，用于解密加密的记录
+	offset  int64    // 下一条记录在文件中的起始位置
+}
+
+// scannedRecord 扫描得到的数据记录
+type scannedRecord struct {
+	segment   uint32 // 记录所在数据分段文件序号
+	seekStart int64  // 记录在文件中的起始位置
+	seekLast  int    // 记录在文件中的持续长度
+	rawLast   int    // 记录未压缩时的持续长度
+	vd        *valueData
+}
+
+func newRecordScanner(reader io.Reader, keys *keyring) *recordScanner {
+	rs := &recordScanner{reader: bufio.NewReader(reader), keys: keys}
+	rs.decoder = msgpack.NewDecoder(rs)
+	return rs
+}
+
+// Read 供 msgpack 解码旧版记录时读取，同步累计已读长度
+func (rs *recordScanner) Read(p []byte) (int, error) {
+	n, err := rs.reader.Read(p)
+	rs.offset += int64(n)
+	return n, err
+}
+
+// ReadByte 供 msgpack 解码旧版记录时读取，同步累计已读长度
+func (rs *recordScanner) ReadByte() (byte, error) {
+	b, err := rs.reader.ReadByte()
+	if nil == err {
+		rs.offset++
+	}
+	return b, err
+}
+
+// UnreadByte 供 msgpack 解码旧版记录时回退，同步回退已读长度
+func (rs *recordScanner) UnreadByte() error {
+	err := rs.reader.UnreadByte()
+	if nil == err {
+		rs.offset--
+	}
+	return err
+}
+
+// next 读取下一条记录，读取完毕返回 io.EOF
+//
+// 带记录头但校验失败的记录返回 ErrRecordCorrupt 及记录位置，可继续读取后续记录；
+// 其它错误表示文件尾部残缺，无法继续读取
+func (rs *recordScanner) next() (*scannedRecord, error) {
+	seekStart := rs.offset
+	first, err := rs.reader.Peek(1)
+	if nil != err {
+		return nil, err
+	}
+	if first[0] != recordMagic { // 旧版数据无记录头
+		vd := &valueData{}
+		if err = rs.decoder.Decode(vd); nil != err {
+			return nil, ErrRecordCorrupt
+		}
+		seekLast := int(rs.offset - seekStart)
+		return &scannedRecord{seekStart: seekStart, seekLast: seekLast, rawLast: seekLast, vd: vd}, nil
+	}
+	head, err := rs.reader.Peek(recordHeadLen)
+	if nil != err {
+		return nil, io.ErrUnexpectedEOF
+	}
+	data := make([]byte, recordHeadLen+int(binary.BigEndian.Uint32(head[2:6])))
+	if _, err = io.ReadFull(rs, data); nil != err {
+		return nil, io.ErrUnexpectedEOF
+	}
+	record := &scannedRecord{seekStart: seekStart, seekLast: len(data)}
+	var payloadLen int
+	if record.vd, payloadLen, err = decodeRecordRaw(data, rs.keys); nil != err {
+		return record, err
+	}
+	record.rawLast = recordHeadLen + payloadLen
+	return record, nil
+}
+
 // storeData 存储具体内容
 //
-// path 存储文件路径
+// form 数据所属表，调用方持有表写锁
 //
-// value 存储具体内容
+// vd 存储记录，I 为 false 表示该记录不可用，即删除
 //
-// valid 存储有效性，如无效则表示改记录不可用，即删除
-func (s *storage) storeData(key, path string, value interface{}, valid bool) *writeResult {
+// 当前数据分段文件写入本条记录后将超过配置大小时，滚动至新的数据分段文件
+func (s *storage) storeData(form Form, vd *valueData) *writeResult {
 	var (
-		file      *os.File
+		file      *cachedFile
+		segment   = form.getSegment()
 		seekStart int64
 		seekLast  int
 		data      []byte
 		err       error
 	)
 	// 存储数据外包装数据属性
-	vd := &valueData{K: key, I: valid, V: value}
-	if data, err = msgpack.Marshal(vd); nil != err {
+	if data, err = encodeRecord(vd, compression2codec(form.getCompression()), form.getDatabase().getKeyring()); nil != err {
 		return &writeResult{err: err}
 	}
-	defer func() {
-		if nil != file {
-			<-s.limitOpenFileChan
-			_ = file.Close()
-		}
-	}()
-	if file, err = s.openFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND); nil != err {
+	dataID := form.getDatabase().getID()
+	if file, err = s.files.acquire(pathFormDataFile(dataID, form.getID(), segment), true); nil != err {
 		log.Error("storeData", log.Err(err))
 		return &writeResult{err: err}
 	}
-	seekStart, err = file.Seek(0, io.SeekEnd)
-	if err != nil {
+	if size := file.getSize(); size > 0 && size+int64(len(data)) > obtainConf().segmentSize() {
+		s.files.release(file)
+		segment++
+		if file, err = s.files.acquire(pathFormDataFile(dataID, form.getID(), segment), true); nil != err {
+			log.Error("storeData", log.Err(err))
+			return &writeResult{err: err}
+		}
+		form.setSegment(segment)
+	}
+	defer s.files.release(file)
+	if seekStart, err = file.append(data); nil != err {
 		log.Debug("storeData", log.Err(err))
 		return &writeResult{err: err}
 	}
-	if seekLast, err = file.Write(data); nil != err {
-		log.Debug("storeData", log.Err(err))
+	if err = file.persist(form.getDatabase().getSyncMode()); nil != err {
+		log.Error("storeData", log.Err(err))
 		return &writeResult{err: err}
 	}
+	seekLast = len(data)
 	return &writeResult{
+		segment:   segment,
 		seekStart: seekStart,
 		seekLast:  seekLast,
 		err:       err,
 	}
 }
 
-func (s *storage) read(filePath string, seekStart int64, seekLast int) *readResult {
-	var (
-		file *os.File
-		err  error
-	)
-	defer func() {
-		if nil != file {
-			<-s.limitOpenFileChan
-			_ = file.Close()
-		}
-	}()
-	//log.Debug("read", log.Field("filePath", filePath), log.Field("seekStart", seekStart), log.Field("seekLast", seekLast))
-	file, err = s.openFile(filePath, os.O_RDONLY)
-	if err != nil {
-		//log.Error("read", log.Err(err))
+func (s *storage) read(filePath string, seekStart int64, seekLast int, keys *keyring) *readResult {
+	vd, err := s.readRecord(filePath, seekStart, seekLast, keys)
+	if nil != err {
 		return &readResult{err: err}
 	}
-	_, err = file.Seek(seekStart, io.SeekStart) //表示文件的起始位置，从第seekStart个字符往后读取
+	if !vd.I {
+		return &readResult{err: ErrValueInvalid}
+	}
+	// 已过期但尚未回收的记录视为不存在
+	if vd.expired(time.Now().UnixNano()) {
+		return &readResult{err: ErrValueExpired}
+	}
+	return &readResult{key: vd.K, value: vd.V}
+}
+
+// readRecord 读取并解析指定位置的数据记录，不校验记录是否有效或过期
+func (s *storage) readRecord(filePath string, seekStart int64, seekLast int, keys *keyring) (*valueData, error) {
+	//log.Debug("read", log.Field("filePath", filePath), log.Field("seekStart", seekStart), log.Field("seekLast", seekLast))
+	file, err := s.files.acquire(filePath, false)
 	if err != nil {
 		//log.Error("read", log.Err(err))
-		return &readResult{err: err}
+		return nil, err
 	}
-	inputReader := bufio.NewReader(file)
-	var bytes []byte
-	if bytes, err = inputReader.Peek(seekLast); nil != err {
+	defer s.files.release(file)
+	// 按记录实际长度读取，记录可能超过默认缓冲区大小
+	bytes := make([]byte, seekLast)
+	if err = file.readAt(bytes, seekStart); nil != err {
 		//log.Error("read", log.Err(err))
-		return &readResult{err: err}
+		return nil, err
 	}
-	var value interface{}
-	if err = msgpack.Unmarshal(bytes, &value); nil != err {
-		//log.Error("read", log.Err(err))
-		return &readResult{err: err}
+	return decodeRecord(bytes, keys)
+}
+
+// encodeRecord 组装一条数据记录
+//
+// 记录头 + msgpack(valueData)，msgpack 内容按 codec 压缩，压缩后未变小则不压缩
+//
+// keys 启用加密时压缩后的内容以当前数据密钥加密
+//
+// 记录头标记低4位记录压缩方式，加密位记录是否加密，crc32校验落盘内容
+func encodeRecord(vd *valueData, codec byte, keys *keyring) ([]byte, error) {
+	payload, err := msgpack.Marshal(vd)
+	if nil != err {
+		return nil, err
 	}
-	switch value.(type) {
-	default:
-		return &readResult{err: ErrValueType}
-	case map[string]interface{}:
-		valueMap := value.(map[string]interface{})
-		if valueMap["I"].(bool) {
-			return &readResult{key: valueMap["K"].(string), value: valueMap["V"], err: err}
+	if codec != codecNone {
+		var compressed []byte
+		if compressed, err = compress(codec, payload); nil != err {
+			return nil, err
 		}
-		return &readResult{err: ErrValueInvalid}
+		if len(compressed) < len(payload) {
+			payload = compressed
+		} else {
+			codec = codecNone
+		}
+	}
+	return sealRecord(codec, payload, keys)
+}
+
+// sealRecord 为压缩后的记录内容组装记录头，启用加密时先加密
+func sealRecord(codec byte, body []byte, keys *keyring) ([]byte, error) {
+	flag := codec & codecMask
+	if keys.enabled() {
+		var err error
+		if body, err = keys.seal(body); nil != err {
+			return nil, err
+		}
+		flag |= recordSealed
+	}
+	data := make([]byte, recordHeadLen+len(body))
+	data[0] = recordMagic
+	data[1] = flag
+	binary.BigEndian.PutUint32(data[2:6], uint32(len(body)))
+	binary.BigEndian.PutUint32(data[6:10], crc32.ChecksumIEEE(body))
+	copy(data[recordHeadLen:], body)
+	return data, nil
+}
+
+// openRecord 校验带记录头的数据记录，返回记录头标记及解密后的记录内容
+func openRecord(data []byte, keys *keyring) (byte, []byte, error) {
+	if len(data) < recordHeadLen {
+		return 0, nil, ErrRecordCorrupt
+	}
+	body := data[recordHeadLen:]
+	if int(binary.BigEndian.Uint32(data[2:6])) != len(body) || crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(data[6:10]) {
+		return 0, nil, ErrRecordCorrupt
+	}
+	if data[1]&recordSealed == 0 {
+		return data[1], body, nil
+	}
+	body, err := keys.open(body)
+	if ErrDataKeyNotFound == err {
+		return 0, nil, err
 	}
+	if nil != err {
+		return 0, nil, ErrRecordCorrupt
+	}
+	return data[1], body, nil
+}
+
+// resealRecord 以当前数据密钥重新加密数据记录，返回是否重新加密
+//
+// 未启用加密或已是当前数据密钥加密的记录原样返回
+func resealRecord(data []byte, keys *keyring) ([]byte, bool, error) {
+	if !keys.enabled() {
+		return data, false, nil
+	}
+	if len(data) == 0 || data[0] != recordMagic { // 旧版数据无记录头
+		sealed, err := sealRecord(codecNone, data, keys)
+		return sealed, nil == err, err
+	}
+	if len(data) >= recordHeadLen && data[1]&recordSealed != 0 && sealedVersion(data[recordHeadLen:]) == keys.current() {
+		return data, false, nil
+	}
+	flag, body, err := openRecord(data, keys)
+	if nil != err {
+		return nil, false, err
+	}
+	sealed, err := sealRecord(flag&codecMask, body, keys)
+	return sealed, nil == err, err
+}
+
+// recordPayload 校验一条数据记录并返回解密解压后的 msgpack 内容，兼容无记录头的旧版数据
+func recordPayload(data []byte, keys *keyring) ([]byte, error) {
+	if len(data) == 0 || data[0] != recordMagic { // 旧版数据无记录头
+		return data, nil
+	}
+	flag, body, err := openRecord(data, keys)
+	if nil != err {
+		return nil, err
+	}
+	if body, err = decompress(flag&codecMask, body); nil != err {
+		return nil, ErrRecordCorrupt
+	}
+	return body, nil
+}
+
+// decodeRecord 校验并解析一条数据记录，兼容无记录头的旧版数据
+func decodeRecord(data []byte, keys *keyring) (*valueData, error) {
+	vd, _, err := decodeRecordRaw(data, keys)
+	return vd, err
+}
+
+// decodeRecordRaw 校验并解析一条数据记录，同时返回记录解压后的 msgpack 内容长度
+func decodeRecordRaw(data []byte, keys *keyring) (*valueData, int, error) {
+	payload, err := recordPayload(data, keys)
+	if nil != err {
+		return nil, 0, err
+	}
+	vd := &valueData{}
+	if err = msgpack.Unmarshal(payload, vd); nil != err {
+		return nil, 0, ErrRecordCorrupt
+	}
+	return vd, len(payload), nil
+}
+
+// flush 将 interval 落盘策略下写入的数据及索引落盘
+func (s *storage) flush() {
+	s.files.flush()
+}
+
+// invalidate 文件被替换、删除或截断后淘汰缓存的句柄
+func (s *storage) invalidate(filePath string) {
+	s.files.invalidate(filePath)
+}
+
+// invalidateDir 目录被替换或删除后淘汰目录下全部文件缓存的句柄
+func (s *storage) invalidateDir(dir string) {
+	s.files.invalidateDir(dir)
 }
 
-func (s *storage) openFile(filePath string, flag int) (*os.File, error) {
-	s.limitOpenFileChan <- 1
-	return os.OpenFile(filePath, flag, 0644)
+// close 关闭全部缓存的句柄
+func (s *storage) close() {
+	s.files.close()
 }
@@ -67,6 +67,10 @@ func (s *sql) first(array []string) error {
 		return s.use(array)
 	case firstCreate:
 		return s.create(array)
+	case firstDrop:
+		return s.drop(array)
+	case firstRename:
+		return s.rename(array)
 	case firstPutD:
 		return s.putD(array)
 	case firstSetD:
@@ -206,6 +210,77 @@ func (s *sql) createDoc(array []string) error {
 	return sqlSyntaxErr
 }
 
+// drop 'drop database {name}'、'drop table {name}'、'drop doc {name}'、'drop index {form} {keyStructure}'
+func (s *sql) drop(array []string) error {
+	if len(array) < 3 {
+		return sqlSyntaxParamsCountInvalidErr
+	}
+	switch array[1] {
+	default:
+		return sqlSyntaxErr
+	case "database":
+		if len(array) != 3 {
+			return sqlSyntaxParamsCountInvalidErr
+		}
+		if err := DropDatabase(s.serverURL, array[2]); nil != err {
+			return executeErr(err.Error())
+		}
+		if s.databaseName == array[2] {
+			s.databaseName = ""
+		}
+		return nil
+	case "table", "doc":
+		if len(array) != 3 {
+			return sqlSyntaxParamsCountInvalidErr
+		}
+		if gnomon.StringIsEmpty(s.databaseName) {
+			return sqlDatabaseIsNilErr
+		}
+		if err := DropForm(s.serverURL, s.databaseName, array[2]); nil != err {
+			return executeErr(err.Error())
+		}
+		return nil
+	case "index":
+		if len(array) != 4 {
+			return sqlSyntaxParamsCountInvalidErr
+		}
+		if gnomon.StringIsEmpty(s.databaseName) {
+			return sqlDatabaseIsNilErr
+		}
+		if err := DropIndex(s.serverURL, s.databaseName, array[2], array[3]); nil != err {
+			return executeErr(err.Error())
+		}
+		return nil
+	}
+}
+
+// rename 'rename database {name} {newName}'、'rename table {name} {newName}'、'rename doc {name} {newName}'
+func (s *sql) rename(array []string) error {
+	if len(array) != 4 {
+		return sqlSyntaxParamsCountInvalidErr
+	}
+	switch array[1] {
+	default:
+		return sqlSyntaxErr
+	case "database":
+		if err := RenameDatabase(s.serverURL, array[2], array[3]); nil != err {
+			return executeErr(err.Error())
+		}
+		if s.databaseName == array[2] {
+			s.databaseName = array[3]
+		}
+		return nil
+	case "table", "doc":
+		if gnomon.StringIsEmpty(s.databaseName) {
+			return sqlDatabaseIsNilErr
+		}
+		if err := RenameForm(s.serverURL, s.databaseName, array[2], array[3]); nil != err {
+			return executeErr(err.Error())
+		}
+		return nil
+	}
+}
+
 func (s *sql) putD(array []string) error {
 	if len(array) < 3 {
 		return sqlSyntaxParamsCountInvalidErr
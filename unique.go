@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"github.com/aberic/gnomon"
+	"strings"
+)
+
+// UniqueViolationError 写入数据的索引值与唯一索引中其它主键的记录重复
+type UniqueViolationError struct {
+	Index string // Index 违反约束的唯一索引结构名
+	Key   string // Key 已占用该索引值的记录主键
+}
+
+func (e *UniqueViolationError) Error() string {
+	return strings.Join([]string{"unique index ", e.Index, " is violated by existing key ", e.Key}, "")
+}
+
+// checkUnique 校验写入数据在表全部唯一索引中的索引值未被其它主键的当前记录占用
+//
+// 在预写日志及任何数据、索引写入之前执行，违反任一唯一索引时整体拒绝写入，调用方持有表写锁
+//
+// 数据中缺少索引字段时该索引不记录此数据，不参与校验，索引字段为数组时每个元素均不能被其它主键的记录占用
+func (d *database) checkUnique(key string, indexes map[string]Index, value interface{}) error {
+	for _, idx := range indexes {
+		// 回填失败的唯一索引中已有数据违反约束，不再参与校验
+		if !idx.isUnique() || idx.getStatus().State == IndexFailed {
+			continue
+		}
+		customKeys, err := customIndexKeys(idx, value)
+		if nil != err {
+			continue
+		}
+		for _, ck := range customKeys {
+			if holder := d.occupied(idx, key, ck); gnomon.StringIsNotEmpty(holder) {
+				return &UniqueViolationError{Index: idx.getKeyStructure(), Key: holder}
+			}
+		}
+	}
+	return nil
+}
+
+// occupied 获取唯一索引中占用索引key的其它主键，未被占用时返回空字符串
+//
+// 唯一索引的链表key即索引key，仅读取该链表指向的记录，记录已更新为其它索引值、已删除或已过期时视为未被占用
+func (d *database) occupied(idx Index, key string, ck *customKey) string {
+	ln := idx.getLink(postingKey(idx, ck.key, key), ck.hashKey)
+	if nil == ln || ln.getSeekStartIndex() == -1 {
+		return ""
+	}
+	if rs := readLink(ln); nil == rs.err && rs.key != key {
+		return rs.key
+	}
+	return ""
+}
@@ -0,0 +1,226 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"errors"
+	"github.com/aberic/gnomon/log"
+	"sync"
+	"time"
+)
+
+// ErrVersionNotFound key不存在或指定版本未保留
+var ErrVersionNotFound = errors.New("version not found")
+
+// RecordVersion 数据的一个版本
+type RecordVersion struct {
+	Version uint64      // Version 版本号，自1开始递增
+	Value   interface{} // Value 该版本存储数据，删除产生的版本为nil
+	Deleted bool        // Deleted 该版本是否为删除操作
+	Time    int64       // Time 写入时间，unix纳秒，旧版数据为0
+	Expire  int64       // Expire 过期时间，unix纳秒，0表示永不过期
+}
+
+func newRecordVersion(vd *valueData) *RecordVersion {
+	version := &RecordVersion{Version: vd.version(), Deleted: !vd.I, Time: vd.T, Expire: vd.E}
+	if vd.I {
+		version.Value = vd.V
+	}
+	return version
+}
+
+// hasDefaultIndex 表是否存在主键索引，SQL表仅有自增ID索引
+func hasDefaultIndex(form Form) bool {
+	for _, index := range form.getIndexes() {
+		if index.getKeyStructure() == indexDefaultID {
+			return true
+		}
+	}
+	return false
+}
+
+// currentLink 链表是否指向key当前记录
+//
+// 有主键索引的表以主键索引链表为准，没有主键索引的表以 recordHeads 为准
+func currentLink(form Form, key string, ln Link) bool {
+	location, ok := currentLocation(form, key)
+	return ok && location == recordLocation{segment: ln.getSegment(), seekStart: ln.getSeekStart()}
+}
+
+// currentLocation 获取key当前记录的位置，key不存在时返回false
+//
+// 有主键索引的表以主键索引链表为准，没有主键索引的表以 recordHeads 为准
+func currentLocation(form Form, key string) (recordLocation, bool) {
+	if hasDefaultIndex(form) {
+		current := defaultLink(form, key)
+		if nil == current {
+			return recordLocation{}, false
+		}
+		return recordLocation{segment: current.getSegment(), seekStart: current.getSeekStart()}, true
+	}
+	return form.getHeads().get(key)
+}
+
+// recordHeads 没有主键索引的表中每个key当前记录的位置，已删除的key不在其中
+//
+// 写入时更新，重启时依据数据文件重新生成，压缩后随记录迁移
+type recordHeads struct {
+	locations map[string]recordLocation
+	lock      sync.RWMutex
+}
+
+func (h *recordHeads) get(key string) (recordLocation, bool) {
+	defer h.lock.RUnlock()
+	h.lock.RLock()
+	location, ok := h.locations[key]
+	return location, ok
+}
+
+// set 更新key当前记录的位置，valid 为 false 表示记录已删除
+func (h *recordHeads) set(key string, location recordLocation, valid bool) {
+	defer h.lock.Unlock()
+	h.lock.Lock()
+	if nil == h.locations {
+		h.locations = map[string]recordLocation{}
+	}
+	if valid {
+		h.locations[key] = location
+	} else {
+		delete(h.locations, key)
+	}
+}
+
+// load 依据数据文件中每个key最新且有效的记录生成
+func (h *recordHeads) load(records []*scannedRecord) {
+	defer h.lock.Unlock()
+	h.lock.Lock()
+	h.locations = make(map[string]recordLocation, len(records))
+	for _, record := range records {
+		h.locations[record.vd.K] = recordLocation{segment: record.segment, seekStart: record.seekStart}
+	}
+}
+
+// relocate 压缩后将当前记录位置更新为新位置，未被保留的记录随之移除
+func (h *recordHeads) relocate(moved map[recordLocation]movedRecord) {
+	defer h.lock.Unlock()
+	h.lock.Lock()
+	for key, location := range h.locations {
+		if to, ok := moved[location]; ok {
+			h.locations[key] = to.recordLocation
+		} else {
+			delete(h.locations, key)
+		}
+	}
+}
+
+// snapshot 全部当前记录位置
+func (h *recordHeads) snapshot() map[recordLocation]bool {
+	defer h.lock.RUnlock()
+	h.lock.RLock()
+	current := make(map[recordLocation]bool, len(h.locations))
+	for _, location := range h.locations {
+		current[location] = true
+	}
+	return current
+}
+
+// defaultLink 获取表主键索引中key对应且已落盘的链表对象，不存在时返回nil
+func defaultLink(form Form, key string) Link {
+	for _, index := range form.getIndexes() {
+		if index.getKeyStructure() != indexDefaultID {
+			continue
+		}
+		if ln := index.getLink(key, hash(key)); nil != ln && ln.getSeekStartIndex() != -1 {
+			return ln
+		}
+		return nil
+	}
+	return nil
+}
+
+// chainVersion 主键已存在时，新记录版本号在上一版本基础上递增，并记录上一版本位置
+//
+// ibs 本次写入的索引检索结果，调用方持有表写锁
+func chainVersion(ibs []IndexBack, vd *valueData) {
+	vd.N = 1
+	for _, ib := range ibs {
+		ln := ib.getLink()
+		if ln.getNodal().getIndex().getKeyStructure() != indexDefaultID || ln.getSeekStartIndex() == -1 {
+			continue
+		}
+		prev, err := ln.getRecord()
+		if nil != err {
+			log.Warn("previous version is unreadable, start a new version chain", log.Field("key", vd.K), log.Err(err))
+			return
+		}
+		vd.N = prev.version() + 1
+		vd.S, vd.O, vd.L = ln.getSegment(), ln.getSeekStart(), ln.getSeekLast()
+		return
+	}
+}
+
+// history 获取key保留的版本集合，由新至旧，最多返回表保留的版本数
+//
+// 上一版本已被压缩回收或位置失效时提前结束，读取期间持有表读锁
+func (d *database) history(formName, key string) ([]*RecordVersion, error) {
+	form := d.getForms()[formName]
+	if nil == form {
+		return nil, formIsInvalid(formName)
+	}
+	if form.getEngine() != EngineFile {
+		return nil, ErrEngineUnsupported
+	}
+	defer form.rUnLock()
+	form.rLock()
+	ln := defaultLink(form, key)
+	if nil == ln {
+		return nil, ErrVersionNotFound
+	}
+	vd, err := ln.getRecord()
+	if nil != err {
+		return nil, err
+	}
+	versions := []*RecordVersion{newRecordVersion(vd)}
+	for uint32(len(versions)) < form.getVersions() && vd.L > 0 {
+		prev, err := store().readRecord(pathFormDataFile(d.id, form.getID(), vd.S), vd.O, vd.L, d.keys)
+		if nil != err || prev.K != key || prev.version()+1 != vd.version() {
+			break
+		}
+		vd = prev
+		versions = append(versions, newRecordVersion(vd))
+	}
+	return versions, nil
+}
+
+// getVersion 获取key指定版本的数据，该版本为删除操作或已过期时与 get 返回相同错误
+func (d *database) getVersion(formName, key string, version uint64) (interface{}, error) {
+	versions, err := d.history(formName, key)
+	if nil != err {
+		return nil, err
+	}
+	for _, v := range versions {
+		if v.Version != version {
+			continue
+		}
+		if v.Deleted {
+			return nil, ErrValueInvalid
+		}
+		if v.Expire > 0 && v.Expire <= time.Now().UnixNano() {
+			return nil, ErrValueExpired
+		}
+		return v.Value, nil
+	}
+	return nil, ErrVersionNotFound
+}
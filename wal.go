@@ -0,0 +1,326 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"github.com/aberic/gnomon"
+	"github.com/aberic/gnomon/log"
+	"github.com/vmihailenco/msgpack"
+	"hash/crc32"
+	"io"
+	"os"
+	"sync"
+)
+
+const (
+	walBegin  uint8 = iota // walBegin 操作开始，记录完整的逻辑操作
+	walCommit              // walCommit 操作完成，数据及所有索引均已落盘
+	walAbort               // walAbort 操作失败，已告知调用方，重启时不再重做
+)
+
+const (
+	// walFrameHeadLen 日志帧头长度，4字节长度 + 4字节crc32
+	walFrameHeadLen = 8
+	// walCheckpointSize 日志文件超过该大小且无未完成操作时截断
+	walCheckpointSize int64 = 4 << 20
+	// walFrameSealed 日志帧长度中的加密位，帧内容以库数据密钥加密
+	walFrameSealed uint32 = 1 << 31
+)
+
+// ErrWALCorrupt 预写日志帧校验失败
+var ErrWALCorrupt = errors.New("wal frame is corrupt")
+
+// walEntry 预写日志条目
+//
+// 一次 put/set/remove 对应一条 walBegin 及一条 walCommit 或 walAbort 记录
+type walEntry struct {
+	S uint64      // 日志序列号
+	T uint8       // 条目类型 walBegin/walCommit/walAbort
+	F string      // 表唯一ID
+	K string      // key
+	I bool        // 是否有效，false 表示删除
+	V interface{} // 存储数据
+	E int64       // 过期时间，unix纳秒，0表示永不过期
+}
+
+// wal 库级预写日志
+//
+// 存储格式 {dataDir}/data/{dataID}/database.wal
+//
+// 帧格式：4字节大端长度 + 4字节crc32 + msgpack(walEntry)，启用加密时长度最高位置1，msgpack 内容以库数据密钥加密
+type wal struct {
+	path    string              // 日志文件路径
+	keys    *keyring            // 库数据密钥环
+	seq     uint64              // 当前日志序列号
+	size    int64               // 当前日志文件大小
+	pending map[uint64]struct{} // 已开始但未完成的操作集合
+	mode    string              // 落盘策略，为空时使用配置 SyncMode
+	dirty   bool                // 是否有尚未落盘的日志帧
+	file    *os.File
+	wLock   sync.Mutex
+}
+
+func newWAL(dataID string, keys *keyring) *wal {
+	return &wal{path: pathDatabaseWALFile(dataID), keys: keys, pending: map[uint64]struct{}{}}
+}
+
+// begin 记录一次逻辑操作，返回该操作的日志序列号
+//
+// formID 表唯一ID
+//
+// valid 存储有效性，如无效则表示删除
+//
+// expire 过期时间，unix纳秒，0表示永不过期
+func (w *wal) begin(formID, key string, value interface{}, valid bool, expire int64) (uint64, error) {
+	defer w.wLock.Unlock()
+	w.wLock.Lock()
+	w.seq++
+	if err := w.append(&walEntry{S: w.seq, T: walBegin, F: formID, K: key, I: valid, V: value, E: expire}); nil != err {
+		return 0, err
+	}
+	w.pending[w.seq] = struct{}{}
+	return w.seq, nil
+}
+
+// commit 标记操作完成，无未完成操作且日志文件过大时执行截断
+func (w *wal) commit(seq uint64) error {
+	return w.finish(seq, walCommit)
+}
+
+// abort 标记操作失败，重启时不再重做
+//
+// 标记写入失败时同样移出未完成操作集合，避免日志文件无法截断，此时该操作在截断前重启仍会被重做
+func (w *wal) abort(seq uint64) error {
+	return w.finish(seq, walAbort)
+}
+
+// finish 写入完成或失败标记并移出未完成操作集合，无未完成操作且日志文件过大时执行截断
+func (w *wal) finish(seq uint64, t uint8) error {
+	defer w.wLock.Unlock()
+	w.wLock.Lock()
+	err := w.append(&walEntry{S: seq, T: t})
+	if nil != err && walCommit == t {
+		return err
+	}
+	delete(w.pending, seq)
+	if nil != err {
+		return err
+	}
+	if len(w.pending) == 0 && w.size > walCheckpointSize {
+		return w.truncate()
+	}
+	return nil
+}
+
+// append 写入日志帧，调用方持有 wLock
+func (w *wal) append(entry *walEntry) error {
+	var (
+		data []byte
+		err  error
+	)
+	if data, err = msgpack.Marshal(entry); nil != err {
+		return err
+	}
+	length := uint32(0)
+	if w.keys.enabled() {
+		if data, err = w.keys.seal(data); nil != err {
+			return err
+		}
+		length = walFrameSealed
+	}
+	if nil == w.file {
+		if w.file, err = os.OpenFile(w.path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644); nil != err {
+			return err
+		}
+		if w.size, err = w.file.Seek(0, io.SeekEnd); nil != err {
+			return err
+		}
+	}
+	frame := make([]byte, walFrameHeadLen+len(data))
+	binary.BigEndian.PutUint32(frame[0:4], length|uint32(len(data)))
+	binary.BigEndian.PutUint32(frame[4:8], crc32.ChecksumIEEE(data))
+	copy(frame[walFrameHeadLen:], data)
+	n, err := w.file.Write(frame)
+	w.size += int64(n)
+	if nil != err {
+		return err
+	}
+	switch w.syncMode() {
+	case SyncModeAlways:
+		return w.file.Sync()
+	case SyncModeInterval:
+		w.dirty = true
+	}
+	return nil
+}
+
+// syncMode 当前生效的落盘策略，调用方持有 wLock
+func (w *wal) syncMode() string {
+	if gnomon.StringIsEmpty(w.mode) {
+		return obtainConf().SyncMode
+	}
+	return w.mode
+}
+
+// setSyncMode 设置落盘策略，为空时使用配置 SyncMode
+func (w *wal) setSyncMode(syncMode string) {
+	defer w.wLock.Unlock()
+	w.wLock.Lock()
+	w.mode = syncMode
+}
+
+// flush 落盘尚未落盘的日志帧
+func (w *wal) flush() error {
+	defer w.wLock.Unlock()
+	w.wLock.Lock()
+	return w.sync()
+}
+
+// sync 有尚未落盘的日志帧时落盘，调用方持有 wLock
+func (w *wal) sync() error {
+	if nil == w.file || !w.dirty {
+		return nil
+	}
+	w.dirty = false
+	return w.file.Sync()
+}
+
+// checkpoint 无未完成操作时清空日志文件，返回是否已清空
+func (w *wal) checkpoint() (bool, error) {
+	defer w.wLock.Unlock()
+	w.wLock.Lock()
+	if len(w.pending) > 0 {
+		return false, nil
+	}
+	if nil == w.file {
+		if !gnomon.FilePathExists(w.path) {
+			return true, nil
+		}
+		return true, os.Truncate(w.path, 0)
+	}
+	return true, w.truncate()
+}
+
+// truncate 清空日志文件，调用方持有 wLock
+func (w *wal) truncate() error {
+	if nil == w.file {
+		return nil
+	}
+	if err := w.file.Truncate(0); nil != err {
+		return err
+	}
+	w.size = 0
+	return nil
+}
+
+// incomplete 读取日志文件中所有未完成的操作，按日志顺序返回
+//
+// 日志尾部残缺或校验失败的帧视为崩溃时未写完，其后内容全部忽略
+func (w *wal) incomplete() ([]*walEntry, error) {
+	file, err := os.OpenFile(w.path, os.O_RDONLY, 0644)
+	if nil != err {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+	var (
+		reader  = bufio.NewReader(file)
+		begins  = map[uint64]*walEntry{}
+		ordered []uint64
+		head    = make([]byte, walFrameHeadLen)
+	)
+	for {
+		if _, err = io.ReadFull(reader, head); nil != err {
+			break
+		}
+		length := binary.BigEndian.Uint32(head[0:4])
+		data := make([]byte, length&^walFrameSealed)
+		if _, err = io.ReadFull(reader, data); nil != err {
+			break
+		}
+		if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(head[4:8]) {
+			err = ErrWALCorrupt
+			break
+		}
+		if length&walFrameSealed != 0 {
+			if data, err = w.keys.open(data); ErrDataKeyNotFound == err {
+				return nil, err
+			} else if nil != err {
+				break
+			}
+		}
+		entry := &walEntry{}
+		if err = msgpack.Unmarshal(data, entry); nil != err {
+			break
+		}
+		if entry.S > w.seq {
+			w.seq = entry.S
+		}
+		switch entry.T {
+		case walBegin:
+			begins[entry.S] = entry
+			ordered = append(ordered, entry.S)
+		case walCommit, walAbort:
+			delete(begins, entry.S)
+		}
+	}
+	if nil != err && io.EOF != err {
+		log.Warn("wal tail is torn, ignore the rest", log.Field("path", w.path), log.Err(err))
+	}
+	var entries []*walEntry
+	for _, seq := range ordered {
+		if entry, ok := begins[seq]; ok {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// recover 重做所有未完成的操作，完成后截断日志文件
+//
+// 数据文件只追加，索引以最后一次写入为准，因此重做未完成的操作可以使数据与索引重新一致
+func (w *wal) recover(redo func(entry *walEntry) error) error {
+	entries, err := w.incomplete()
+	if nil != err {
+		return err
+	}
+	for _, entry := range entries {
+		if err = redo(entry); nil != err {
+			return err
+		}
+	}
+	_, err = w.checkpoint()
+	return err
+}
+
+// close 落盘尚未落盘的日志帧后关闭日志文件
+func (w *wal) close() error {
+	defer w.wLock.Unlock()
+	w.wLock.Lock()
+	if nil == w.file {
+		return nil
+	}
+	err := w.sync()
+	if errClose := w.file.Close(); nil == err {
+		err = errClose
+	}
+	w.file = nil
+	return err
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright (c) 2020. Aberic - All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lily
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWAL_Recover(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lily-wal")
+	if nil != err {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	w := &wal{path: filepath.Join(dir, "database.wal"), pending: map[uint64]struct{}{}}
+	seq1, err := w.begin("form", "1", 1, true, 0)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if err = w.commit(seq1); nil != err {
+		t.Fatal(err)
+	}
+	if _, err = w.begin("form", "2", map[string]interface{}{"a": "b"}, true, 0); nil != err {
+		t.Fatal(err)
+	}
+	if _, err = w.begin("form", "3", 3, false, 0); nil != err {
+		t.Fatal(err)
+	}
+	_ = w.close()
+	// 模拟崩溃时写了一半的帧
+	file, err := os.OpenFile(w.path, os.O_WRONLY|os.O_APPEND, 0644)
+	if nil != err {
+		t.Fatal(err)
+	}
+	_, _ = file.Write([]byte{0, 0, 0, 9, 1, 2})
+	_ = file.Close()
+
+	restarted := &wal{path: w.path, pending: map[uint64]struct{}{}}
+	var keys []string
+	if err = restarted.recover(func(entry *walEntry) error {
+		keys = append(keys, entry.K)
+		if entry.K == "3" && entry.I {
+			t.Error("remove entry should be invalid")
+		}
+		return nil
+	}); nil != err {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 || keys[0] != "2" || keys[1] != "3" {
+		t.Fatal("redo keys mismatch", keys)
+	}
+	if restarted.seq != 3 {
+		t.Error("seq should continue from 3, got", restarted.seq)
+	}
+	info, err := os.Stat(w.path)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if info.Size() != 0 {
+		t.Error("wal should be truncated after recover")
+	}
+}
+
+func TestWAL_Abort(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lily-wal")
+	if nil != err {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	w := &wal{path: filepath.Join(dir, "database.wal"), pending: map[uint64]struct{}{}}
+	seq, err := w.begin("form", "1", 1, true, 0)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if err = w.abort(seq); nil != err {
+		t.Fatal(err)
+	}
+	// 失败的操作不再阻止截断，重启时不再重做
+	if len(w.pending) != 0 {
+		t.Error("aborted entry should leave pending", w.pending)
+	}
+	_ = w.close()
+	restarted := &wal{path: w.path, pending: map[uint64]struct{}{}}
+	if err = restarted.recover(func(entry *walEntry) error {
+		t.Error("aborted entry should not be redone", entry.K)
+		return nil
+	}); nil != err {
+		t.Fatal(err)
+	}
+}